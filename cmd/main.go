@@ -4,11 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/app"
@@ -61,6 +66,7 @@ func main() {
 	rootCmd := buildRootCmd()
 
 	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
@@ -90,6 +96,9 @@ a single TUI powered by Bubbletea.`,
 	rootCmd.AddCommand(buildCompletionCmd())
 	rootCmd.AddCommand(buildZedCmd())
 	rootCmd.AddCommand(buildCodeCmd())
+	rootCmd.AddCommand(buildConfigCmd())
+	rootCmd.AddCommand(buildSequenceEditorCmd())
+	rootCmd.AddCommand(buildAskpassCmd())
 
 	rootCmd.Flags().StringP("path", "p", ".", "Path to the git repository")
 
@@ -638,6 +647,307 @@ func defaultCodeTasks() []map[string]any {
 	}
 }
 
+// buildConfigCmd creates the `zgv config` subcommand and its show, path,
+// init, validate and keys subcommands.
+func buildConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and manage the zgv configuration",
+		Long: `Inspect and manage the zgv configuration.
+
+Values are merged from built-in defaults, the config file and ZGV_
+environment variables (highest precedence).
+
+Examples:
+  zgv config show
+  zgv config path
+  zgv config init
+  zgv config validate
+  zgv config keys`,
+	}
+
+	configCmd.AddCommand(buildConfigShowCmd())
+	configCmd.AddCommand(buildConfigPathCmd())
+	configCmd.AddCommand(buildConfigInitCmd())
+	configCmd.AddCommand(buildConfigValidateCmd())
+	configCmd.AddCommand(buildConfigKeysCmd())
+
+	return configCmd
+}
+
+func buildConfigShowCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective config and where each value came from",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			r, err := config.Resolve()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				type entry struct {
+					Value  any    `json:"value"`
+					Source string `json:"source"`
+				}
+				out := make(map[string]entry, len(config.Keys))
+				for _, key := range config.Keys {
+					out[key] = entry{Value: r.Values[key], Source: configSourceLabel(r, key)}
+				}
+				for key, val := range keyOverrides(r) {
+					out[key] = entry{Value: val, Source: "file " + r.File}
+				}
+				data, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			overrides := keyOverrides(r)
+			names := slices.Sorted(maps.Keys(overrides))
+			width := 0
+			for _, key := range append(slices.Clone(config.Keys), names...) {
+				width = max(width, len(key))
+			}
+			for _, key := range config.Keys {
+				fmt.Printf("%-*s  %-10v  # %s\n", width, key, formatConfigValue(r.Values[key]), configSourceLabel(r, key))
+			}
+			for _, key := range names {
+				fmt.Printf("%-*s  %-10v  # file %s\n", width, key, formatConfigValue(overrides[key]), r.File)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	return cmd
+}
+
+func buildConfigPathCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "Print the config file in use",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			r, err := config.Resolve()
+			if err != nil {
+				return err
+			}
+			if r.File != "" {
+				fmt.Println(r.File)
+				return nil
+			}
+			fmt.Printf("%s (not created — run `zgv config init`)\n", config.DefaultPath())
+			return nil
+		},
+	}
+}
+
+func buildConfigInitCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Write a commented default config file",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			path, err := config.WriteDefault(force)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Wrote default config to %s\n", path)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing config file")
+	return cmd
+}
+
+func buildConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file and environment for errors",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			r, err := config.Resolve()
+			if err != nil {
+				return err
+			}
+
+			var problems []string
+			for _, key := range r.Unknown {
+				problems = append(problems, fmt.Sprintf("unknown key %q", key))
+			}
+			if err := r.Config.Validate(); err != nil {
+				problems = append(problems, strings.Split(err.Error(), "\n")...)
+			}
+			if _, err := ui.LoadTheme(r.Config.Theme); err != nil {
+				problems = append(problems, err.Error())
+			}
+
+			source := "defaults only (no config file)"
+			if r.File != "" {
+				source = r.File
+			}
+			if len(problems) > 0 {
+				fmt.Printf("%s: %d problem(s)\n", source, len(problems))
+				for _, p := range problems {
+					fmt.Printf("  - %s\n", p)
+				}
+				return errors.New("invalid configuration")
+			}
+
+			fmt.Printf("%s: OK\n", source)
+			if editor := r.Config.EditorCommand(); editor != "" {
+				if _, err := exec.LookPath(strings.Fields(editor)[0]); err != nil {
+					fmt.Printf("  warning: editor %q not found in PATH\n", editor)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func buildConfigKeysCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "List every keymap scope, action and its effective keys",
+		Long: `List every keymap scope, action and its effective keys.
+
+Rebind an action under keys.<scope>.<action> in the config file. A scope
+also sees the keys of the scopes it inherits, so a key may only be bound
+once across them.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			r, err := config.Resolve()
+			if err != nil {
+				return err
+			}
+			km, err := config.BuildKeymap(r.Config.KeyOverrides)
+			if err != nil {
+				return err
+			}
+			overridden := keyOverrides(r)
+
+			if jsonOutput {
+				out := make(map[string]map[string][]string, len(km))
+				for scope, actions := range km {
+					out[scope] = actions
+				}
+				data, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			for i, scope := range config.KeyScopes {
+				if i > 0 {
+					fmt.Println()
+				}
+				header := scope.Name
+				if len(scope.Inherits) > 0 {
+					header += " (inherits " + strings.Join(scope.Inherits, ", ") + ")"
+				}
+				fmt.Println(header)
+				width := 0
+				for _, a := range scope.Actions {
+					width = max(width, len(a.Name))
+				}
+				for _, a := range scope.Actions {
+					bound := km.Keys(scope.Name, a.Name)
+					names := make([]string, len(bound))
+					for j, k := range bound {
+						names[j] = config.DisplayKey(k)
+					}
+					line := fmt.Sprintf("  %-*s  %s", width, a.Name, strings.Join(names, ", "))
+					if _, ok := overridden["keys."+scope.Name+"."+a.Name]; ok {
+						line += "  # file"
+					}
+					fmt.Println(line)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	return cmd
+}
+
+// keyOverrides flattens the config file's keys section into
+// "keys.<scope>.<action>" → value.
+func keyOverrides(r *config.Resolved) map[string]any {
+	out := make(map[string]any)
+	for scope, raw := range r.Config.KeyOverrides {
+		actions, ok := raw.(map[string]any)
+		if !ok {
+			out["keys."+scope] = raw
+			continue
+		}
+		for action, val := range actions {
+			out["keys."+scope+"."+action] = val
+		}
+	}
+	return out
+}
+
+// configSourceLabel describes where a config value came from, naming the
+// file or environment variable.
+func configSourceLabel(r *config.Resolved, key string) string {
+	switch r.Sources[key] {
+	case config.SourceEnv:
+		return "env " + config.EnvVar(key)
+	case config.SourceFile:
+		return "file " + r.File
+	}
+	return "default"
+}
+
+func formatConfigValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// buildSequenceEditorCmd is the GIT_SEQUENCE_EDITOR shim used by the
+// rebase view: git calls `zgv __sequence-editor <prepared> <git-todo>` and
+// we swap in the todo list edited in the TUI.
+func buildSequenceEditorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    git.SequenceEditorCommand + " <prepared-todo> <git-todo>",
+		Hidden: true,
+		Args:   cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return git.RunSequenceEditor(args[0], args[1])
+		},
+	}
+}
+
+// buildAskpassCmd is the GIT_ASKPASS/SSH_ASKPASS helper used for network
+// commands: git or ssh calls `zgv __askpass <prompt>` and we print the
+// answer typed into the running TUI.
+func buildAskpassCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    git.AskpassCommand + " [prompt]",
+		Hidden: true,
+		Args:   cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			prompt := "Password: "
+			if len(args) == 1 {
+				prompt = args[0]
+			}
+			answer, err := git.RunAskpass(prompt)
+			if err != nil {
+				return err
+			}
+			fmt.Println(answer)
+			return nil
+		},
+	}
+}
+
 // buildVersionCmd creates the `zgv version` subcommand supporting --json.
 func buildVersionCmd() *cobra.Command {
 	var jsonOutput bool
@@ -727,29 +1037,75 @@ func runApp(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("opening repository: %w", err)
 	}
 
+	cliSvc.SetDiffContext(cfg.DiffContextLines)
+	cliSvc.SetCommitTimeout(time.Duration(cfg.CommitTimeout) * time.Second)
+	cliSvc.SetRebaseTimeout(time.Duration(cfg.RebaseTimeout) * time.Second)
+
 	// Wrap with a 2-second TTL cache to deduplicate git calls within a
 	// single refresh cycle. Critical for monorepo performance.
-	gitSvc := git.NewCachedService(cliSvc, 2*time.Second)
+	// Writes go through the undo journal underneath the cache, so an undo
+	// invalidates cached reads like any other write.
+	gitSvc := git.NewCachedService(git.NewJournalService(cliSvc, cfg.UndoLevels), 2*time.Second)
 
-	styles := ui.DefaultStyles()
+	theme, err := ui.LoadTheme(cfg.Theme)
+	if err != nil {
+		return fmt.Errorf("loading theme: %w", err)
+	}
+	styles := ui.NewStyles(theme)
+	if cfg.SyntaxHighlight {
+		styles.Syntax = ui.NewHighlighter(theme)
+	}
 
 	viewMap := map[common.TabID]common.View{
-		common.TabStatus:    views.NewStatusView(gitSvc, styles),
-		common.TabLog:       views.NewLogView(gitSvc, styles),
-		common.TabDiff:      views.NewDiffView(gitSvc, styles),
-		common.TabBranches:  views.NewBranchView(gitSvc, styles),
-		common.TabStash:     views.NewStashView(gitSvc, styles),
-		common.TabRemotes:   views.NewRemoteView(gitSvc, styles),
-		common.TabRebase:    views.NewRebaseView(gitSvc, styles),
-		common.TabConflicts: views.NewConflictView(gitSvc, styles),
-		common.TabWorktrees: views.NewWorktreeView(gitSvc, styles),
-		common.TabBisect:    views.NewBisectView(gitSvc, styles),
+		common.TabStatus:    views.NewStatusView(gitSvc, styles, cfg),
+		common.TabLog:       views.NewLogView(gitSvc, styles, cfg),
+		common.TabDiff:      views.NewDiffView(gitSvc, styles, cfg),
+		common.TabBranches:  views.NewBranchView(gitSvc, styles, cfg),
+		common.TabStash:     views.NewStashView(gitSvc, styles, cfg),
+		common.TabRemotes:   views.NewRemoteView(gitSvc, styles, cfg),
+		common.TabRebase:    views.NewRebaseView(gitSvc, styles, cfg),
+		common.TabConflicts: views.NewConflictView(gitSvc, styles, cfg),
+		common.TabWorktrees: views.NewWorktreeView(gitSvc, styles, cfg),
+		common.TabBisect:    views.NewBisectView(gitSvc, styles, cfg),
+		common.TabReflog:    views.NewReflogView(gitSvc, styles, cfg),
+		common.TabTags:      views.NewTagView(gitSvc, styles, cfg),
 	}
 
 	model := app.New(gitSvc, cfg, viewMap)
 
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
+	// Answer credential and host-key prompts from fetch/pull/push in the
+	// TUI instead of letting them hang on the terminal it owns.
+	var askID atomic.Int64
+	askpass, err := git.StartAskpass(func(prompt string, gone <-chan struct{}) (string, bool) {
+		type answer struct {
+			value string
+			ok    bool
+		}
+		ch := make(chan answer, 1)
+		id := int(askID.Add(1))
+		p.Send(common.AskpassMsg{ID: id, Prompt: prompt, Reply: func(value string, ok bool) {
+			ch <- answer{value, ok}
+		}})
+		select {
+		case a := <-ch:
+			return a.value, a.ok
+		case <-gone:
+			p.Send(common.AskpassCancelMsg{ID: id})
+			return "", false
+		}
+	})
+	if err == nil {
+		defer askpass.Close()
+		cliSvc.SetNetworkEnv(askpass.Env())
+	} else {
+		// Still start, but have git fail rather than prompt on the
+		// terminal the TUI owns.
+		cliSvc.SetNetworkEnv([]string{"GIT_TERMINAL_PROMPT=0"})
+		go p.Send(common.ErrMsg{Err: fmt.Errorf("fetch, pull and push can't ask for credentials: %w", err)})
+	}
+
 	// Start filesystem watcher — only watches .git internals, safe for huge monorepos.
 	if watchCh, stop, watchErr := watcher.Watch(cliSvc.RepoRoot(), cliSvc.GitDir(), 500*time.Millisecond); watchErr == nil {
 		defer stop()
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
@@ -8,6 +10,7 @@ import (
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -29,9 +32,30 @@ type Model struct {
 	statusExp time.Time
 	dialog    *components.Dialog
 
+	// pendingConfirm runs when the open confirm dialog is accepted;
+	// confirmQueue holds confirmations that arrived while another dialog
+	// was open.
+	pendingConfirm tea.Cmd
+	confirmQueue   []common.ConfirmMsg
+	// skipConfirm holds the ConfirmMsg kinds the user chose not to be
+	// asked about again this session.
+	skipConfirm map[string]bool
+	// asking is the credential prompt the open input dialog answers;
+	// askQueue holds prompts that arrived while another dialog was open.
+	asking   *common.AskpassMsg
+	askQueue []common.AskpassMsg
+
+	// Global and navigation key sets, rendered by the help overlay.
+	globalKeys keys.Set
+	navKeys    keys.Set
+
 	// Cached status bar data — refreshed via tea.Cmd, never computed in View().
 	barData components.StatusBarData
 
+	// ops are the running cancellable operations, oldest first. The status
+	// bar shows the newest, which is the one the cancel key stops.
+	ops []operation
+
 	// viewStale tracks which views need a re-init on next switch.
 	viewStale map[common.TabID]bool
 
@@ -48,6 +72,14 @@ type tabHitZone struct {
 	End   int // exclusive X
 }
 
+// operation is a running fetch, pull or push (see common.OperationStartMsg).
+type operation struct {
+	id       int
+	label    string
+	progress string
+	cancel   func()
+}
+
 // statusBarMsg carries refreshed status bar data from a background command.
 type statusBarMsg struct {
 	data components.StatusBarData
@@ -56,14 +88,17 @@ type statusBarMsg struct {
 // New creates a new application model.
 func New(gitSvc git.Service, cfg *config.Config, views map[common.TabID]common.View) Model {
 	return Model{
-		git:       gitSvc,
-		cfg:       cfg,
-		styles:    ui.DefaultStyles(),
-		keys:      DefaultKeyMap(),
-		activeTab: common.TabStatus,
-		views:     views,
-		barData:   components.StatusBarData{RepoRoot: gitSvc.RepoRoot()},
-		viewStale: make(map[common.TabID]bool),
+		git:         gitSvc,
+		cfg:         cfg,
+		styles:      ui.DefaultStyles(),
+		keys:        NewKeyMap(cfg.Keymap),
+		globalKeys:  keys.New(cfg.Keymap, config.ScopeGlobal),
+		navKeys:     keys.New(cfg.Keymap, config.ScopeNavigation),
+		activeTab:   common.TabStatus,
+		views:       views,
+		barData:     components.StatusBarData{RepoRoot: gitSvc.RepoRoot()},
+		viewStale:   make(map[common.TabID]bool),
+		skipConfirm: make(map[string]bool),
 	}
 }
 
@@ -90,6 +125,8 @@ func (m Model) refreshStatusBar() tea.Cmd {
 		data.Clean, _ = svc.IsClean()
 		data.Merging = svc.IsMerging()
 		data.Rebasing = svc.IsRebasing()
+		data.CherryPicking = svc.IsCherryPicking()
+		data.Reverting = svc.IsReverting()
 		return statusBarMsg{data: data}
 	}
 }
@@ -98,14 +135,15 @@ func (m Model) refreshStatusBar() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
-	// Dialog has exclusive input when visible.
+	// Dialog has exclusive input when visible; other messages (async
+	// results, resizes) still flow to the views underneath.
 	if m.dialog != nil && m.dialog.Visible() {
-		d, cmd := m.dialog.Update(msg)
-		m.dialog = &d
-		if cmd != nil {
-			cmds = append(cmds, cmd)
+		switch msg.(type) {
+		case tea.KeyMsg, tea.MouseMsg:
+			d, cmd := m.dialog.Update(msg)
+			m.dialog = &d
+			return m, cmd
 		}
-		return m, tea.Batch(cmds...)
 	}
 
 	switch msg := msg.(type) {
@@ -142,6 +180,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case key.Matches(msg, m.keys.Refresh):
 			return m, m.triggerRefresh()
+		case key.Matches(msg, m.keys.Undo):
+			return m, m.replay("Undid", m.git.Undo)
+		case key.Matches(msg, m.keys.Redo):
+			return m, m.replay("Redid", m.git.Redo)
+		case key.Matches(msg, m.keys.Cancel):
+			if len(m.ops) > 0 {
+				op := m.ops[len(m.ops)-1]
+				op.cancel()
+				return m, common.CmdInfo("Cancelling " + op.label + "…")
+			}
 		case key.Matches(msg, m.keys.NextTab):
 			m.cycleTab(1)
 			return m, m.initActiveView()
@@ -170,6 +218,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.switchTo(common.TabWorktrees)
 		case key.Matches(msg, m.keys.TabBisect):
 			return m, m.switchTo(common.TabBisect)
+		case key.Matches(msg, m.keys.TabReflog):
+			return m, m.switchTo(common.TabReflog)
+		case key.Matches(msg, m.keys.TabTags):
+			return m, m.switchTo(common.TabTags)
 
 		case key.Matches(msg, m.keys.Back):
 			if m.showHelp {
@@ -183,6 +235,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.barData = msg.data
 		return m, nil
 
+	case common.OperationStartMsg:
+		m.ops = append(m.ops, operation{id: msg.ID, label: msg.Label, cancel: msg.Cancel})
+		return m, msg.Next
+
+	case common.ProgressMsg:
+		for i := range m.ops {
+			if m.ops[i].id == msg.ID {
+				m.ops[i].progress = msg.Text
+			}
+		}
+		return m, msg.Next
+
+	case common.OperationDoneMsg:
+		m.ops = slices.DeleteFunc(m.ops, func(op operation) bool { return op.id == msg.ID })
+		return m.Update(msg.Result)
+
 	case common.RefreshMsg:
 		// Only refresh the ACTIVE view + status bar. Inactive views will
 		// reload when the user switches to them (lazy init). This prevents
@@ -218,8 +286,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case common.SwitchTabMsg:
 		return m, m.switchTo(msg.Tab)
 
+	case common.ConfirmMsg:
+		if !m.cfg.ConfirmDestructive || m.skipConfirm[msg.Kind] {
+			return m, msg.OnConfirm
+		}
+		m.confirmQueue = append(m.confirmQueue, msg)
+		return m, m.nextDialog()
+
+	case common.AskpassMsg:
+		m.askQueue = append(m.askQueue, msg)
+		return m, m.nextDialog()
+
+	case common.AskpassCancelMsg:
+		if m.asking != nil && m.asking.ID == msg.ID {
+			m.asking = nil
+			m.dialog = nil
+			return m, m.nextDialog()
+		}
+		m.askQueue = slices.DeleteFunc(m.askQueue, func(a common.AskpassMsg) bool { return a.ID == msg.ID })
+		return m, nil
+
 	case components.DialogResult:
 		m.dialog = nil
+		if ask := m.asking; ask != nil && msg.Tag == askpassTag {
+			m.asking = nil
+			ask.Reply(msg.Value, msg.Confirmed)
+			return m, m.nextDialog()
+		}
+		if action := m.pendingConfirm; action != nil {
+			m.pendingConfirm = nil
+			if !msg.Confirmed {
+				return m, m.nextDialog()
+			}
+			if msg.Remember {
+				m.skipConfirm[msg.Tag] = true
+			}
+			return m, tea.Batch(action, m.nextDialog())
+		}
+		if cmd := m.nextDialog(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	// Forward unhandled messages to the active view.
@@ -241,7 +347,7 @@ func (m Model) View() string {
 	}
 
 	if m.showHelp {
-		sections := components.GlobalHelpEntries()
+		sections := components.GlobalHelpEntries(m.globalKeys, m.navKeys)
 		tabName := ""
 		for _, t := range common.AllTabs {
 			if t.ID == m.activeTab {
@@ -270,6 +376,14 @@ func (m Model) View() string {
 	content = lipgloss.NewStyle().Width(m.width).Height(contentH).Render(content)
 
 	barData := m.barData
+	if len(m.ops) > 0 {
+		op := m.ops[len(m.ops)-1]
+		barData.Progress = op.label
+		if op.progress != "" {
+			barData.Progress += ": " + op.progress
+		}
+		barData.Progress += "  (" + m.globalKeys.First("cancel_operation") + " cancel)"
+	}
 	if m.statusMsg != "" && time.Now().Before(m.statusExp) {
 		barData.Message = m.statusMsg
 		barData.IsError = m.statusErr
@@ -320,6 +434,60 @@ func (m *Model) switchTo(tab common.TabID) tea.Cmd {
 	return m.initActiveView()
 }
 
+// askpassTag identifies the credential prompt's dialog.
+const askpassTag = "askpass"
+
+// nextDialog opens the oldest queued dialog once none is showing.
+// Credential prompts go first, since git is waiting on them. A queued
+// confirmation whose kind the user has since chosen not to be asked about
+// runs straight away.
+func (m *Model) nextDialog() tea.Cmd {
+	if m.dialog != nil && m.dialog.Visible() {
+		return nil
+	}
+	if m.nextAskpass() {
+		return nil
+	}
+	var skipped []tea.Cmd
+	for len(m.confirmQueue) > 0 {
+		c := m.confirmQueue[0]
+		m.confirmQueue = m.confirmQueue[1:]
+		if m.skipConfirm[c.Kind] {
+			skipped = append(skipped, c.OnConfirm)
+			continue
+		}
+		d := components.NewConfirmDialog(m.styles, c.Title, c.Detail, c.Kind).
+			WithRemember("Don't ask again this session")
+		m.dialog = &d
+		m.pendingConfirm = c.OnConfirm
+		break
+	}
+	return tea.Batch(skipped...)
+}
+
+// nextAskpass opens the dialog for the oldest queued credential prompt,
+// reporting whether it did. Input is masked except for usernames and
+// ssh's yes/no host-key question.
+func (m *Model) nextAskpass() bool {
+	if m.asking != nil || len(m.askQueue) == 0 {
+		return false
+	}
+	ask := m.askQueue[0]
+	m.askQueue = m.askQueue[1:]
+	m.asking = &ask
+
+	prompt := strings.TrimSpace(ask.Prompt)
+	d := components.NewInputDialog(m.styles, "Authentication required", "", askpassTag).WithMessage(prompt)
+	switch {
+	case strings.Contains(prompt, "(yes/no"):
+		d = components.NewInputDialog(m.styles, "Unknown host", "yes / no", askpassTag).WithMessage(prompt)
+	case !strings.HasPrefix(prompt, "Username"):
+		d = d.WithMask()
+	}
+	m.dialog = &d
+	return true
+}
+
 // initActiveView calls Init on the current tab to load its data.
 func (m Model) initActiveView() tea.Cmd {
 	if v, ok := m.views[m.activeTab]; ok {
@@ -342,6 +510,17 @@ func (m Model) triggerRefresh() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// replay runs an undo or redo, then refreshes whatever it changed.
+func (m Model) replay(verb string, fn func() (string, error)) tea.Cmd {
+	return tea.Sequence(func() tea.Msg {
+		desc, err := fn()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.InfoMsg{Text: verb + " " + desc}
+	}, common.CmdRefresh)
+}
+
 // handleMouse processes mouse events: tab clicks, scroll wheel, and click-through.
 func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -0,0 +1,109 @@
+// Package clipboard copies text to the user's clipboard.
+//
+// Two mechanisms are used:
+//
+//   - The OSC 52 escape sequence, written to the terminal. The terminal
+//     sets the clipboard itself, so this works over SSH and inside
+//     terminals embedded in editors, as long as the terminal supports it
+//     (most modern ones do; tmux needs `set -g set-clipboard on`).
+//   - A system clipboard tool: wl-copy on Wayland, xclip or xsel on X11,
+//     pbcopy on macOS and clip.exe on Windows and WSL.
+//
+// A terminal can't report whether it honoured OSC 52, so in the default
+// mode both are tried and the copy only fails when neither could be
+// attempted.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Modes select how text is copied.
+const (
+	ModeAuto   = "auto"   // OSC 52 and, when present, a system tool
+	ModeOSC52  = "osc52"  // OSC 52 only
+	ModeSystem = "system" // a system tool only
+)
+
+// ErrNoBackend is returned when no clipboard mechanism is available for
+// the mode.
+var ErrNoBackend = errors.New("no clipboard available (install wl-copy, xclip or xsel, or use a terminal with OSC 52)")
+
+// Output is where OSC 52 sequences are written: the terminal the TUI
+// draws on.
+var Output io.Writer = os.Stdout
+
+// Copy puts text on the clipboard according to mode.
+func Copy(mode, text string) error {
+	switch mode {
+	case ModeOSC52:
+		return writeOSC52(text)
+	case ModeSystem:
+		return copySystem(text)
+	}
+	oscErr := writeOSC52(text)
+	sysErr := copySystem(text)
+	if oscErr != nil && sysErr != nil {
+		return errors.Join(oscErr, sysErr)
+	}
+	return nil
+}
+
+// writeOSC52 writes the clipboard escape sequence in a single write, so it
+// isn't split by the renderer's output. Inside tmux or screen the sequence
+// is wrapped so the multiplexer passes it on to the outer terminal.
+func writeOSC52(text string) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = "\x1bP" + seq + "\x1b\\"
+	}
+	if _, err := io.WriteString(Output, seq); err != nil {
+		return fmt.Errorf("writing OSC 52: %w", err)
+	}
+	return nil
+}
+
+// systemTools are the clipboard commands tried in order; a tool is only
+// used when its condition holds and it is installed.
+var systemTools = []struct {
+	when func() bool
+	args []string
+}{
+	{func() bool { return os.Getenv("WAYLAND_DISPLAY") != "" }, []string{"wl-copy"}},
+	{func() bool { return os.Getenv("DISPLAY") != "" }, []string{"xclip", "-selection", "clipboard"}},
+	{func() bool { return os.Getenv("DISPLAY") != "" }, []string{"xsel", "--clipboard", "--input"}},
+	{func() bool { return runtime.GOOS == "darwin" }, []string{"pbcopy"}},
+	{func() bool { return true }, []string{"clip.exe"}},
+}
+
+// copySystem pipes text into the first available system tool.
+func copySystem(text string) error {
+	for _, tool := range systemTools {
+		if !tool.when() {
+			continue
+		}
+		path, err := exec.LookPath(tool.args[0])
+		if err != nil {
+			continue
+		}
+		// No output is captured: xclip and wl-copy stay in the background
+		// to serve the selection, holding any pipe open.
+		cmd := exec.Command(path, tool.args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", tool.args[0], err)
+		}
+		return nil
+	}
+	return ErrNoBackend
+}
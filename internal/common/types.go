@@ -1,6 +1,8 @@
 package common
 
 import (
+	"context"
+
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -21,6 +23,8 @@ const (
 	TabConflicts
 	TabWorktrees
 	TabBisect
+	TabReflog
+	TabTags
 )
 
 // TabMeta describes a tab for display purposes.
@@ -44,12 +48,14 @@ var AllTabs = []TabMeta{
 	{TabBranches, "Branches", "⑂", "b", "branch"},
 	{TabRemotes, "Remotes", "⇄", "m", "branch"},
 	{TabStash, "Stash", "⊟", "t", "branch"},
+	{TabTags, "Tags", "⌂", "g", "branch"},
 
 	// ── Advanced Git operations ──────────────────────────────
 	{TabRebase, "Rebase", "↻", "e", "advanced"},
 	{TabConflicts, "Conflicts", "⚡", "x", "advanced"},
 	{TabWorktrees, "Worktrees", "⌥", "w", "advanced"},
 	{TabBisect, "Bisect", "◎", "i", "advanced"},
+	{TabReflog, "Reflog", "↺", "f", "advanced"},
 }
 
 // ── Custom messages ─────────────────────────────────────────────────────────
@@ -69,6 +75,57 @@ type SwitchTabMsg struct{ Tab TabID }
 // ToggleHelpMsg toggles the help overlay.
 type ToggleHelpMsg struct{}
 
+// ConfirmMsg asks the app to confirm a destructive action before running
+// OnConfirm. Detail says what would be lost. Kind groups confirmations so
+// the user can stop being asked about one kind for the rest of the session.
+type ConfirmMsg struct {
+	Kind      string
+	Title     string
+	Detail    string
+	OnConfirm tea.Cmd
+}
+
+// ── Long-running operations ─────────────────────────────────────────────────
+
+// OperationStartMsg announces a cancellable operation (fetch, pull,
+// push). The app shows its progress in the status bar and calls Cancel
+// when the user cancels it. Next waits for the operation's first update.
+type OperationStartMsg struct {
+	ID     int
+	Label  string // e.g. "push origin"
+	Cancel context.CancelFunc
+	Next   tea.Cmd
+}
+
+// ProgressMsg reports an operation's progress; Next waits for the
+// following update.
+type ProgressMsg struct {
+	ID   int
+	Text string // e.g. "Writing objects 45% (9/20)"
+	Next tea.Cmd
+}
+
+// OperationDoneMsg ends an operation. Result is the message the view that
+// started it expects, and is handled like any other message.
+type OperationDoneMsg struct {
+	ID     int
+	Result tea.Msg
+}
+
+// AskpassMsg is a credential or host-key prompt from a network command
+// (see git.Askpass). Reply must be called exactly once with the answer, or
+// with ok=false if the user cancelled, unless an AskpassCancelMsg with the
+// same ID withdraws the prompt first.
+type AskpassMsg struct {
+	ID     int
+	Prompt string
+	Reply  func(answer string, ok bool)
+}
+
+// AskpassCancelMsg withdraws a prompt whose command exited before it was
+// answered; its dialog is closed, or its queued entry dropped.
+type AskpassCancelMsg struct{ ID int }
+
 // CmdRefresh returns a RefreshMsg (use as return from tea.Cmd).
 func CmdRefresh() tea.Msg { return RefreshMsg{} }
 
@@ -1,12 +1,20 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix is the prefix for environment variable overrides, e.g.
+// ZGV_MAX_LOG_ENTRIES.
+const EnvPrefix = "ZGV"
+
 // Config holds the resolved application configuration.
 type Config struct {
 	// Theme name: "dark" (default), "light", or path to custom theme.
@@ -21,21 +29,134 @@ type Config struct {
 	DiffContextLines int `mapstructure:"diff_context_lines"`
 	// SideBySideDiff enables side-by-side diff mode by default.
 	SideBySideDiff bool `mapstructure:"side_by_side_diff"`
+	// SyntaxHighlight colours code in diffs by language.
+	SyntaxHighlight bool `mapstructure:"syntax_highlight"`
+	// UndoLevels is how many writes ctrl+z can undo; 0 turns the journal off.
+	UndoLevels int `mapstructure:"undo_levels"`
+	// CommitSubjectMax is the subject length the commit box warns above;
+	// 0 turns the check off.
+	CommitSubjectMax int `mapstructure:"commit_subject_max"`
+	// CommitBlankLine warns when a commit body doesn't start on line 3.
+	CommitBlankLine bool `mapstructure:"commit_blank_line"`
+	// CommitTypes is the conventional-commit type vocabulary (feat, fix,
+	// ...). Empty turns conventional-commit checks off.
+	CommitTypes []string `mapstructure:"commit_types"`
+	// CommitScopes limits conventional-commit scopes; empty allows any.
+	CommitScopes []string `mapstructure:"commit_scopes"`
+	// CommitHistory is how many recent commit messages are kept for recall.
+	CommitHistory int `mapstructure:"commit_history"`
+	// CommitSignOff starts the commit box with --signoff ticked.
+	CommitSignOff bool `mapstructure:"commit_signoff"`
+	// CommitTimeout is how many seconds a commit, hooks included, may run.
+	CommitTimeout int `mapstructure:"commit_timeout"`
+	// RebaseTimeout is how many seconds a rebase, exec lines and hooks
+	// included, may run.
+	RebaseTimeout int `mapstructure:"rebase_timeout"`
+	// PullMode is the pull strategy preselected in the pull prompt:
+	// "merge", "rebase" or "ff-only"; empty follows git's pull.rebase and
+	// pull.ff.
+	PullMode string `mapstructure:"pull_mode"`
+	// PullAutostash starts the pull prompt with --autostash ticked.
+	PullAutostash bool `mapstructure:"pull_autostash"`
+	// ProtectedBranches are branch name patterns (path.Match syntax, e.g.
+	// "release/*") that zgv refuses to force push to.
+	ProtectedBranches []string `mapstructure:"protected_branches"`
+	// BranchBase is a ref (e.g. "origin/main") every branch in the Branch
+	// view is also compared against; empty shows upstream counts only.
+	BranchBase string `mapstructure:"branch_base"`
+	// Clipboard is how text is copied: "auto" (OSC 52 plus a system tool
+	// when one is installed), "osc52" or "system".
+	Clipboard string `mapstructure:"clipboard"`
+	// KeyOverrides is the raw `keys` section: scope → action → key(s).
+	KeyOverrides map[string]any `mapstructure:"keys"`
+
+	// Keymap is the effective keymap (defaults merged with KeyOverrides),
+	// set by Load once the overrides have been validated.
+	Keymap Keymap `mapstructure:"-"`
+}
+
+// Keys lists every config key in display order.
+var Keys = []string{
+	"theme",
+	"editor",
+	"max_log_entries",
+	"confirm_destructive",
+	"diff_context_lines",
+	"side_by_side_diff",
+	"syntax_highlight",
+	"undo_levels",
+	"commit_subject_max",
+	"commit_blank_line",
+	"commit_types",
+	"commit_scopes",
+	"commit_history",
+	"commit_signoff",
+	"commit_timeout",
+	"rebase_timeout",
+	"pull_mode",
+	"pull_autostash",
+	"protected_branches",
+	"branch_base",
+	"clipboard",
 }
 
-// Load reads configuration from ~/.config/zgv/config.yaml (or TOML/JSON).
+// Source says where a resolved value came from.
+type Source string
+
+// Value sources, in increasing precedence.
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+)
+
+// Resolved is a loaded Config together with where each value came from.
+type Resolved struct {
+	Config *Config
+	// File is the config file that was read, or "" if none was found.
+	File string
+	// Sources maps each key in Keys to the source of its value.
+	Sources map[string]Source
+	// Values holds each key's effective value for display.
+	Values map[string]any
+	// Unknown lists keys present in the file that zgv doesn't recognise.
+	Unknown []string
+}
+
+// EnvVar returns the environment variable that overrides key.
+func EnvVar(key string) string { return EnvPrefix + "_" + strings.ToUpper(key) }
+
+// Load reads configuration from ~/.config/zgv/config.yaml (or TOML/JSON)
+// and validates it.
 func Load() (*Config, error) {
+	r, err := Resolve()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Config.Validate(); err != nil {
+		return nil, err
+	}
+	km, err := BuildKeymap(r.Config.KeyOverrides)
+	if err != nil {
+		return nil, err
+	}
+	r.Config.Keymap = km
+	return r.Config, nil
+}
+
+// Resolve merges defaults, the config file and ZGV_ environment
+// variables, recording the source of every value. It does not validate.
+func Resolve() (*Resolved, error) {
 	v := viper.New()
 	v.SetConfigName("config")
 	v.SetConfigType("yaml")
 
-	configDir := configDirectory()
-	v.AddConfigPath(configDir)
+	v.AddConfigPath(Directory())
 	v.AddConfigPath(".")
 
 	setDefaults(v)
 
-	v.SetEnvPrefix("ZGV")
+	v.SetEnvPrefix(EnvPrefix)
 	v.AutomaticEnv()
 
 	if err := v.ReadInConfig(); err != nil {
@@ -49,7 +170,161 @@ func Load() (*Config, error) {
 	if err := v.Unmarshal(cfg); err != nil {
 		return nil, err
 	}
-	return cfg, nil
+
+	r := &Resolved{
+		Config:  cfg,
+		File:    v.ConfigFileUsed(),
+		Sources: make(map[string]Source, len(Keys)),
+		Values:  make(map[string]any, len(Keys)),
+	}
+	known := make(map[string]bool, len(Keys))
+	for _, key := range Keys {
+		known[key] = true
+		r.Values[key] = cfg.Value(key)
+		switch {
+		case isEnvSet(key):
+			r.Sources[key] = SourceEnv
+		case v.InConfig(key):
+			r.Sources[key] = SourceFile
+		default:
+			r.Sources[key] = SourceDefault
+		}
+	}
+	for _, key := range v.AllKeys() {
+		if !known[key] && !strings.HasPrefix(key, "keys.") {
+			r.Unknown = append(r.Unknown, key)
+		}
+	}
+	return r, nil
+}
+
+// Value returns the typed value of a config key (see Keys), or nil for an
+// unknown key.
+func (c *Config) Value(key string) any {
+	switch key {
+	case "theme":
+		return c.Theme
+	case "editor":
+		return c.Editor
+	case "max_log_entries":
+		return c.MaxLogEntries
+	case "confirm_destructive":
+		return c.ConfirmDestructive
+	case "diff_context_lines":
+		return c.DiffContextLines
+	case "side_by_side_diff":
+		return c.SideBySideDiff
+	case "syntax_highlight":
+		return c.SyntaxHighlight
+	case "undo_levels":
+		return c.UndoLevels
+	case "commit_subject_max":
+		return c.CommitSubjectMax
+	case "commit_blank_line":
+		return c.CommitBlankLine
+	case "commit_types":
+		return c.CommitTypes
+	case "commit_scopes":
+		return c.CommitScopes
+	case "commit_history":
+		return c.CommitHistory
+	case "commit_signoff":
+		return c.CommitSignOff
+	case "commit_timeout":
+		return c.CommitTimeout
+	case "rebase_timeout":
+		return c.RebaseTimeout
+	case "pull_mode":
+		return c.PullMode
+	case "pull_autostash":
+		return c.PullAutostash
+	case "protected_branches":
+		return c.ProtectedBranches
+	case "branch_base":
+		return c.BranchBase
+	case "clipboard":
+		return c.Clipboard
+	}
+	return nil
+}
+
+func isEnvSet(key string) bool {
+	_, ok := os.LookupEnv(EnvVar(key))
+	return ok
+}
+
+// Validate reports values that can't be used. Theme files are checked by
+// the UI when they are loaded.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.MaxLogEntries < 1 {
+		errs = append(errs, fmt.Errorf("max_log_entries must be at least 1, got %d", c.MaxLogEntries))
+	}
+	if c.DiffContextLines < 0 {
+		errs = append(errs, fmt.Errorf("diff_context_lines must not be negative, got %d", c.DiffContextLines))
+	}
+	if c.UndoLevels < 0 {
+		errs = append(errs, fmt.Errorf("undo_levels must not be negative, got %d", c.UndoLevels))
+	}
+	if c.CommitSubjectMax < 0 {
+		errs = append(errs, fmt.Errorf("commit_subject_max must not be negative, got %d", c.CommitSubjectMax))
+	}
+	if c.CommitHistory < 0 {
+		errs = append(errs, fmt.Errorf("commit_history must not be negative, got %d", c.CommitHistory))
+	}
+	if c.CommitTimeout < 1 {
+		errs = append(errs, fmt.Errorf("commit_timeout must be at least 1 second, got %d", c.CommitTimeout))
+	}
+	if c.RebaseTimeout < 1 {
+		errs = append(errs, fmt.Errorf("rebase_timeout must be at least 1 second, got %d", c.RebaseTimeout))
+	}
+	if len(c.CommitScopes) > 0 && len(c.CommitTypes) == 0 {
+		errs = append(errs, errors.New("commit_scopes needs commit_types (scopes are only checked on conventional commits)"))
+	}
+	switch c.PullMode {
+	case "", "merge", "rebase", "ff-only":
+	default:
+		errs = append(errs, fmt.Errorf("pull_mode must be merge, rebase, ff-only or empty, got %q", c.PullMode))
+	}
+	switch c.Clipboard {
+	case "auto", "osc52", "system":
+	default:
+		errs = append(errs, fmt.Errorf("clipboard must be auto, osc52 or system, got %q", c.Clipboard))
+	}
+	for _, p := range c.ProtectedBranches {
+		if _, err := path.Match(p, ""); err != nil {
+			errs = append(errs, fmt.Errorf("protected_branches: bad pattern %q", p))
+		}
+	}
+	if strings.TrimSpace(c.Theme) == "" {
+		errs = append(errs, errors.New("theme must not be empty"))
+	}
+	if _, err := BuildKeymap(c.KeyOverrides); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// EditorCommand returns the editor to launch: the configured editor, then
+// $VISUAL, then $EDITOR, then vi.
+func (c *Config) EditorCommand() string {
+	for _, e := range []string{c.Editor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if e = strings.TrimSpace(e); e != "" {
+			return e
+		}
+	}
+	return "vi"
+}
+
+// ProtectedPattern returns the protected_branches pattern branch matches,
+// or "" if force pushing to it is allowed.
+func (c *Config) ProtectedPattern(branch string) string {
+	for _, p := range c.ProtectedBranches {
+		if ok, _ := path.Match(p, branch); ok {
+			return p
+		}
+	}
+	return ""
 }
 
 func setDefaults(v *viper.Viper) {
@@ -59,12 +334,144 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("confirm_destructive", true)
 	v.SetDefault("diff_context_lines", 3)
 	v.SetDefault("side_by_side_diff", false)
+	v.SetDefault("syntax_highlight", true)
+	v.SetDefault("undo_levels", 100)
+	v.SetDefault("commit_subject_max", 72)
+	v.SetDefault("commit_blank_line", true)
+	v.SetDefault("commit_types", []string{})
+	v.SetDefault("commit_scopes", []string{})
+	v.SetDefault("commit_history", 50)
+	v.SetDefault("commit_signoff", false)
+	v.SetDefault("commit_timeout", 300)
+	v.SetDefault("rebase_timeout", 3600)
+	v.SetDefault("pull_mode", "")
+	v.SetDefault("pull_autostash", false)
+	v.SetDefault("protected_branches", []string{"main", "master"})
+	v.SetDefault("branch_base", "")
+	v.SetDefault("clipboard", "auto")
 }
 
-func configDirectory() string {
+// Directory returns the per-user config directory ($XDG_CONFIG_HOME/zgv
+// or ~/.config/zgv).
+func Directory() string {
 	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
 		return filepath.Join(xdg, "zgv")
 	}
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".config", "zgv")
 }
+
+// StateDirectory returns where zgv keeps data between sessions, such as
+// the commit message history ($XDG_STATE_HOME/zgv or ~/.local/state/zgv).
+func StateDirectory() string {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "zgv")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "zgv")
+}
+
+// DefaultPath is where `zgv config init` writes the config file.
+func DefaultPath() string { return filepath.Join(Directory(), "config.yaml") }
+
+// DefaultFile is the commented config written by `zgv config init`.
+const DefaultFile = `# zgv configuration. Every key can also be set with a ZGV_ environment
+# variable, e.g. ZGV_MAX_LOG_ENTRIES=500.
+
+# Colour theme: dark, light, or a path to a theme file.
+theme: dark
+
+# Editor for files and commit messages. Empty uses $VISUAL, then $EDITOR.
+editor: ""
+
+# Number of commits loaded by the Log view.
+max_log_entries: 200
+
+# Ask before destructive operations (discard, branch delete, stash drop, tag
+# delete, push, reset).
+confirm_destructive: true
+
+# Context lines shown around diff changes (git diff -U<n>).
+diff_context_lines: 3
+
+# Open the Diff view in side-by-side mode.
+side_by_side_diff: false
+
+# Colour code in diffs and in the merge editor by language (judged by file
+# name). Turn off to save work on slow machines or connections.
+syntax_highlight: true
+
+# Writes ctrl+z can undo this session (0 disables the undo journal). Each
+# journaled write snapshots the index, and file-changing writes the working
+# tree, which costs a little time on very large repositories.
+undo_levels: 100
+
+# Commit box checks. They only warn; ctrl+s still commits. The subject limit
+# (0 disables) and the blank line between subject and body apply to every
+# message. Listing types turns on conventional-commit checks
+# ("type(scope): description"); listing scopes restricts the scope too.
+commit_subject_max: 72
+commit_blank_line: true
+commit_types: []
+# commit_types: [feat, fix, docs, style, refactor, perf, test, build, ci, chore, revert]
+commit_scopes: []
+
+# Recent commit messages kept for recall in the commit box (0 disables).
+commit_history: 50
+
+# Tick "sign off" (Signed-off-by trailer) in the commit box by default.
+# Signing follows git's commit.gpgsign and can be toggled per commit.
+commit_signoff: false
+
+# Seconds a commit may run, hooks and signing included, before it is killed.
+commit_timeout: 300
+
+# Seconds a rebase may run, exec lines and hooks included, before it is
+# killed. Continuing or skipping starts the clock again.
+rebase_timeout: 3600
+
+# Pull strategy preselected when pulling: merge, rebase or ff-only. Empty
+# follows git's pull.rebase and pull.ff settings. pull_autostash ticks
+# --autostash (stash local changes around the pull) by default.
+pull_mode: ""
+pull_autostash: false
+
+# Branches zgv refuses to force push to (even with --force-with-lease).
+# Patterns use shell glob syntax, e.g. "release/*".
+protected_branches: [main, master]
+
+# A ref every branch in the Branch view is compared against, besides its
+# upstream, e.g. origin/main. Empty turns the comparison off.
+branch_base: ""
+
+# How y copies: auto writes the OSC 52 terminal sequence (works over SSH)
+# and also uses wl-copy, xclip, xsel or pbcopy when installed; osc52 and
+# system use only one of the two.
+clipboard: auto
+
+# Rebind any action: keys.<scope>.<action> takes a key or a list of keys,
+# and replaces that action's defaults. Conflicting bindings are rejected.
+# Run ` + "`zgv config keys`" + ` to list every scope, action and default.
+# keys:
+#   navigation:
+#     up: [up, ctrl+p]
+#     down: [down, ctrl+n]
+#   status:
+#     stage: [s, a]
+`
+
+// WriteDefault writes DefaultFile to DefaultPath. It refuses to replace an
+// existing file unless force is set.
+func WriteDefault(force bool) (string, error) {
+	path := DefaultPath()
+	if _, err := os.Stat(path); err == nil && !force {
+		return path, fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return path, fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(DefaultFile), 0o644); err != nil {
+		return path, fmt.Errorf("writing config: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// loadFile runs Load on a config file holding yaml, isolated from the
+// user's config and ZGV_ environment.
+func loadFile(t *testing.T, yaml string) (*Config, error) {
+	t.Helper()
+	for _, key := range Keys {
+		t.Setenv(EnvVar(key), "") // viper ignores empty variables
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Chdir(dir)
+	if err := os.MkdirAll(Directory(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(DefaultPath(), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load()
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string // "" for a valid config
+	}{
+		{"defaults", "", ""},
+		{"the default file", DefaultFile, ""},
+		{"protected patterns", "protected_branches: [main, 'release/*']\n", ""},
+		{"bad protected pattern", "protected_branches: [main, 'release/[']\n",
+			`protected_branches: bad pattern "release/["`},
+		{"no log entries", "max_log_entries: 0\n", "max_log_entries must be at least 1, got 0"},
+		{"negative undo levels", "undo_levels: -1\n", "undo_levels must not be negative"},
+		{"zero rebase timeout", "rebase_timeout: 0\n", "rebase_timeout must be at least 1 second"},
+		{"scopes without types", "commit_scopes: [ui]\n", "commit_scopes needs commit_types"},
+		{"unknown pull mode", "pull_mode: squash\n", `pull_mode must be merge, rebase, ff-only or empty, got "squash"`},
+		{"unknown clipboard", "clipboard: x11\n", `clipboard must be auto, osc52 or system, got "x11"`},
+		{"bad key binding", "keys:\n  status:\n    stage: u\n", `"u" is bound to both status.stage and status.unstage`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadFile(t, tt.yaml)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Load = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Load = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	_, err := loadFile(t, "max_log_entries: 0\nclipboard: x11\nprotected_branches: ['[']\n")
+	if err == nil {
+		t.Fatal("Load succeeded")
+	}
+	for _, want := range []string{"max_log_entries", "clipboard", "protected_branches"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load = %v, want it to mention %s", err, want)
+		}
+	}
+}
+
+func TestProtectedPattern(t *testing.T) {
+	c := &Config{ProtectedBranches: []string{"main", "release/*", "hotfix-?"}}
+	tests := []struct {
+		branch, want string
+	}{
+		{"main", "main"},
+		{"main2", ""},
+		{"release/1.0", "release/*"},
+		{"release/1.0/rc", ""}, // * doesn't cross /
+		{"hotfix-1", "hotfix-?"},
+		{"hotfix-12", ""},
+		{"feature", ""},
+	}
+	for _, tt := range tests {
+		if got := c.ProtectedPattern(tt.branch); got != tt.want {
+			t.Errorf("ProtectedPattern(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Keymap maps scope → action → keys. Keys use Bubble Tea's key names
+// ("s", "ctrl+s", "alt+x", "pgdown", " " for space).
+type Keymap map[string]map[string][]string
+
+// KeyScope is a group of actions that are live at the same time, e.g. the
+// Status view's file list. Keys must be unique across a scope and every
+// scope it inherits, otherwise one action would shadow another.
+type KeyScope struct {
+	Name     string
+	Inherits []string
+	Actions  []KeyAction
+}
+
+// KeyAction is a rebindable action and its default keys.
+type KeyAction struct {
+	Name string
+	Keys []string
+}
+
+// Scopes shared by most views.
+const (
+	ScopeGlobal     = "global"
+	ScopeNavigation = "navigation"
+)
+
+// views inherit the app-level keys (which are matched first) and the
+// shared navigation keys.
+var viewInherits = []string{ScopeGlobal, ScopeNavigation}
+
+// KeyScopes is the catalogue of every rebindable action with its default
+// keys. Modal scopes (text-capturing editors) only inherit navigation,
+// because the app stops intercepting global keys while they are open.
+var KeyScopes = []KeyScope{
+	{Name: ScopeGlobal, Actions: []KeyAction{
+		{"quit", []string{"q", "ctrl+c"}},
+		{"help", []string{"?"}},
+		{"refresh", []string{"r", "ctrl+r"}},
+		{"undo", []string{"ctrl+z"}},
+		{"redo", []string{"ctrl+y"}},
+		{"cancel_operation", []string{"ctrl+x"}},
+		{"next_tab", []string{"right", "l"}},
+		{"prev_tab", []string{"left", "h"}},
+		{"tab_status", []string{"alt+s"}},
+		{"tab_diff", []string{"alt+d"}},
+		{"tab_log", []string{"alt+l"}},
+		{"tab_branches", []string{"alt+b"}},
+		{"tab_remotes", []string{"alt+m"}},
+		{"tab_stash", []string{"alt+t"}},
+		{"tab_rebase", []string{"alt+e"}},
+		{"tab_conflicts", []string{"alt+x"}},
+		{"tab_worktrees", []string{"alt+w"}},
+		{"tab_bisect", []string{"alt+i"}},
+		{"tab_reflog", []string{"alt+f"}},
+		{"tab_tags", []string{"alt+g"}},
+	}},
+	{Name: ScopeNavigation, Inherits: []string{ScopeGlobal}, Actions: []KeyAction{
+		{"up", []string{"up", "k"}},
+		{"down", []string{"down", "j"}},
+		{"page_up", []string{"pgup", "ctrl+u"}},
+		{"page_down", []string{"pgdown", "ctrl+d"}},
+		{"top", []string{"home", "g"}},
+		{"bottom", []string{"end", "G"}},
+		{"back", []string{"esc"}},
+	}},
+	{Name: "status", Inherits: viewInherits, Actions: []KeyAction{
+		{"stage", []string{"s"}},
+		{"stage_all", []string{"S"}},
+		{"unstage", []string{"u"}},
+		{"unstage_all", []string{"U"}},
+		{"discard", []string{"x"}},
+		{"edit", []string{"e"}},
+		{"copy_path", []string{"y"}},
+		{"commit", []string{"c"}},
+		{"amend", []string{"C"}},
+		{"focus_diff", []string{"d", "enter"}},
+		{"switch_pane", []string{"tab"}},
+	}},
+	// The commit box is a text editor, so it takes no other keys.
+	{Name: "commit", Actions: []KeyAction{
+		{"submit", []string{"ctrl+s"}},
+		{"cancel", []string{"esc"}},
+		{"editor", []string{"ctrl+o"}},
+		{"signoff", []string{"alt+o"}},
+		{"sign", []string{"alt+g"}},
+		{"no_verify", []string{"alt+v"}},
+		{"history_prev", []string{"alt+p"}},
+		{"history_next", []string{"alt+n"}},
+	}},
+	{Name: "status_diff", Inherits: viewInherits, Actions: []KeyAction{
+		{"toggle_line", []string{" "}},
+		{"stage_hunk", []string{"s"}},
+		{"unstage_hunk", []string{"u"}},
+		{"discard_lines", []string{"x"}},
+		{"discard_hunk", []string{"X"}},
+		{"select", []string{"v"}},
+		{"next_hunk", []string{"]"}},
+		{"prev_hunk", []string{"["}},
+		{"copy_diff", []string{"y"}},
+		{"switch_pane", []string{"tab"}},
+	}},
+	{Name: "log", Inherits: viewInherits, Actions: []KeyAction{
+		{"detail", []string{"enter", "d"}},
+		{"copy_hash", []string{"y"}},
+		{"copy", []string{"Y"}},
+		{"tag", []string{"t"}},
+		{"reset", []string{"R"}},
+		{"mark", []string{" "}},
+		{"compare", []string{"="}},
+		{"cherry_pick", []string{"c"}},
+		{"revert", []string{"v"}},
+		{"fixup", []string{"f"}},
+		{"squash", []string{"s"}},
+		{"autosquash", []string{"a"}},
+		{"reword", []string{"w"}},
+		{"continue", []string{"C"}},
+		{"skip", []string{"S"}},
+		{"abort", []string{"A"}},
+	}},
+	// The Log view's copy menu; each text offered has its own action.
+	{Name: "copy_menu", Actions: []KeyAction{
+		{"submit", []string{"enter"}},
+		{"cancel", []string{"esc"}},
+		{"next", []string{"down", "tab"}},
+		{"prev", []string{"up", "shift+tab"}},
+		{"hash", []string{"h"}},
+		{"full_hash", []string{"H"}},
+		{"subject", []string{"s"}},
+		{"message", []string{"m"}},
+		{"patch", []string{"p"}},
+	}},
+	// The options prompt shown before a cherry-pick or revert runs.
+	{Name: "log_pick", Actions: []KeyAction{
+		{"submit", []string{"enter"}},
+		{"cancel", []string{"esc"}},
+		{"record_origin", []string{"x"}},
+		{"no_commit", []string{"n"}},
+		{"mainline", []string{"m"}},
+	}},
+	{Name: "diff", Inherits: viewInherits, Actions: []KeyAction{
+		{"toggle_side_by_side", []string{"v"}},
+		{"copy_diff", []string{"y"}},
+		{"compare", []string{"c"}},
+		{"toggle_range", []string{"."}},
+		{"swap", []string{"s"}},
+	}},
+	// The file tree and per-file diff of the Diff view and the Log view's
+	// commit detail.
+	{Name: "diff_browser", Inherits: viewInherits, Actions: []KeyAction{
+		{"next_file", []string{"n"}},
+		{"prev_file", []string{"p"}},
+		{"next_hunk", []string{"]"}},
+		{"prev_hunk", []string{"["}},
+		{"open", []string{"enter", " "}},
+		{"switch_pane", []string{"tab"}},
+	}},
+	{Name: "branches", Inherits: viewInherits, Actions: []KeyAction{
+		{"checkout", []string{"enter"}},
+		{"new", []string{"n"}},
+		{"rename", []string{"R"}},
+		{"delete", []string{"D"}},
+		{"merge", []string{"m"}},
+		{"reset", []string{"X"}},
+		{"set_upstream", []string{"u"}},
+		{"unset_upstream", []string{"U"}},
+		{"push_track", []string{"P"}},
+		{"copy_name", []string{"y"}},
+	}},
+	{Name: "tags", Inherits: viewInherits, Actions: []KeyAction{
+		{"new", []string{"n"}},
+		{"delete", []string{"D"}},
+		{"push", []string{"P"}},
+		{"delete_remote", []string{"X"}},
+	}},
+	{Name: "stash", Inherits: viewInherits, Actions: []KeyAction{
+		{"save", []string{"s"}},
+		{"pop", []string{"p"}},
+		{"apply", []string{"a"}},
+		{"drop", []string{"D"}},
+		{"show", []string{"enter", "d"}},
+		{"copy_ref", []string{"y"}},
+		{"copy_diff", []string{"Y"}},
+	}},
+	{Name: "remotes", Inherits: viewInherits, Actions: []KeyAction{
+		{"fetch", []string{"f"}},
+		{"fetch_all", []string{"F"}},
+		{"pull", []string{"p"}},
+		{"push", []string{"P"}},
+		{"expand", []string{"enter", " "}},
+		{"checkout", []string{"c"}},
+		{"add", []string{"n"}},
+		{"rename", []string{"R"}},
+		{"remove", []string{"D"}},
+		{"edit_url", []string{"e"}},
+		{"edit_push_url", []string{"E"}},
+		{"prune", []string{"x"}},
+		{"copy", []string{"y"}},
+	}},
+	// The push form has a text field, so it takes no other keys.
+	{Name: "push", Actions: []KeyAction{
+		{"submit", []string{"enter"}},
+		{"cancel", []string{"esc"}},
+		{"next_remote", []string{"tab"}},
+		{"prev_remote", []string{"shift+tab"}},
+		{"set_upstream", []string{"alt+u"}},
+		{"force", []string{"alt+f"}},
+		{"tags", []string{"alt+t"}},
+		{"no_verify", []string{"alt+v"}},
+	}},
+	{Name: "pull", Actions: []KeyAction{
+		{"submit", []string{"enter"}},
+		{"cancel", []string{"esc"}},
+		{"next", []string{"down", "tab"}},
+		{"prev", []string{"up", "shift+tab"}},
+		{"merge", []string{"m"}},
+		{"rebase", []string{"r"}},
+		{"ff_only", []string{"f"}},
+		{"autostash", []string{"a"}},
+	}},
+	{Name: "rebase", Inherits: viewInherits, Actions: []KeyAction{
+		{"start", []string{"i"}},
+		{"continue", []string{"c"}},
+		{"skip", []string{"s"}},
+		{"abort", []string{"a"}},
+	}},
+	{Name: "rebase_editor", Inherits: []string{ScopeNavigation}, Actions: []KeyAction{
+		{"pick", []string{"p"}},
+		{"reword", []string{"r"}},
+		{"edit", []string{"e"}},
+		{"squash", []string{"s"}},
+		{"fixup", []string{"f"}},
+		{"drop", []string{"d"}},
+		{"exec", []string{"x"}},
+		{"remove_exec", []string{"D", "delete"}},
+		{"move_down", []string{"J", "shift+down"}},
+		{"move_up", []string{"K", "shift+up"}},
+		{"start", []string{"enter", "ctrl+s"}},
+		{"cancel", []string{"q"}},
+	}},
+	{Name: "conflicts", Inherits: viewInherits, Actions: []KeyAction{
+		{"merge", []string{"enter", "e"}},
+		{"take_ours", []string{"o"}},
+		{"take_theirs", []string{"t"}},
+		{"mark_resolved", []string{"m"}},
+		{"diff", []string{"d"}},
+	}},
+	{Name: "conflicts_merge", Inherits: []string{ScopeNavigation}, Actions: []KeyAction{
+		{"next_block", []string{"n", "]", "tab"}},
+		{"prev_block", []string{"N", "[", "shift+tab"}},
+		{"ours", []string{"o"}},
+		{"theirs", []string{"t"}},
+		{"both", []string{"b"}},
+		{"base", []string{"B"}},
+		{"unresolve", []string{"u", "backspace"}},
+		{"all_ours", []string{"O"}},
+		{"all_theirs", []string{"T"}},
+		{"write", []string{"w", "ctrl+s"}},
+		{"cancel", []string{"q"}},
+	}},
+	{Name: "reflog", Inherits: viewInherits, Actions: []KeyAction{
+		{"detail", []string{"enter", "d"}},
+		{"checkout", []string{"c"}},
+		{"branch", []string{"n"}},
+		{"reset", []string{"R"}},
+		{"next_ref", []string{"tab"}},
+		{"prev_ref", []string{"shift+tab"}},
+	}},
+	{Name: "worktrees", Inherits: viewInherits, Actions: []KeyAction{
+		{"add", []string{"n"}},
+		{"remove", []string{"D"}},
+	}},
+	// Bisect uses g/B for good/bad, so it doesn't take navigation keys.
+	{Name: "bisect", Inherits: []string{ScopeGlobal}, Actions: []KeyAction{
+		{"start", []string{"b"}},
+		{"good", []string{"g"}},
+		{"bad", []string{"B"}},
+		{"reset", []string{"R"}},
+	}},
+}
+
+// DefaultKeymap returns the built-in bindings.
+func DefaultKeymap() Keymap {
+	km := make(Keymap, len(KeyScopes))
+	for _, s := range KeyScopes {
+		actions := make(map[string][]string, len(s.Actions))
+		for _, a := range s.Actions {
+			actions[a.Name] = slices.Clone(a.Keys)
+		}
+		km[s.Name] = actions
+	}
+	return km
+}
+
+// keyAliases lets config files spell keys the way they appear in help.
+var keyAliases = map[string]string{
+	"space":    " ",
+	"return":   "enter",
+	"escape":   "esc",
+	"pagedown": "pgdown",
+	"pageup":   "pgup",
+	"del":      "delete",
+}
+
+// NormalizeKey maps a configured key name to Bubble Tea's spelling.
+// Modifier prefixes are lower-cased; the key itself keeps its case so
+// "G" and "g" stay distinct.
+func NormalizeKey(k string) string {
+	if k == " " {
+		return k
+	}
+	k = strings.TrimSpace(k)
+	if alias, ok := keyAliases[strings.ToLower(k)]; ok {
+		return alias
+	}
+	if i := strings.LastIndex(k, "+"); i > 0 && i < len(k)-1 {
+		mods, base := strings.ToLower(k[:i]), k[i+1:]
+		if alias, ok := keyAliases[strings.ToLower(base)]; ok {
+			base = alias
+		} else if len(base) > 1 || strings.Contains(mods, "ctrl") {
+			// Terminals can't distinguish ctrl+S from ctrl+s.
+			base = strings.ToLower(base)
+		}
+		return mods + "+" + base
+	}
+	if len(k) > 1 {
+		return strings.ToLower(k)
+	}
+	return k
+}
+
+// BuildKeymap merges user overrides (the `keys` config section) over the
+// defaults. Each override replaces an action's keys entirely and may be a
+// single key or a list. Unknown scopes/actions, malformed values and key
+// conflicts are reported together.
+func BuildKeymap(overrides map[string]any) (Keymap, error) {
+	km := DefaultKeymap()
+	var errs []error
+
+	for scope, raw := range overrides {
+		actions, ok := km[scope]
+		if !ok {
+			errs = append(errs, fmt.Errorf("keys: unknown scope %q", scope))
+			continue
+		}
+		entries, ok := raw.(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("keys.%s: expected a map of action: keys", scope))
+			continue
+		}
+		for action, val := range entries {
+			if _, ok := actions[action]; !ok {
+				errs = append(errs, fmt.Errorf("keys.%s: unknown action %q", scope, action))
+				continue
+			}
+			keys, err := keyList(val)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("keys.%s.%s: %w", scope, action, err))
+				continue
+			}
+			actions[action] = keys
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(sortErrors(errs)...)
+	}
+	if err := km.Conflicts(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+func keyList(val any) ([]string, error) {
+	var raw []string
+	switch v := val.(type) {
+	case string:
+		raw = []string{v}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected key names, got %v", item)
+			}
+			raw = append(raw, s)
+		}
+	case []string:
+		raw = v
+	default:
+		return nil, fmt.Errorf("expected a key or list of keys, got %v", val)
+	}
+	keys := make([]string, 0, len(raw))
+	for _, k := range raw {
+		n := NormalizeKey(k)
+		if n == "" {
+			return nil, errors.New("empty key name")
+		}
+		keys = append(keys, n)
+	}
+	return keys, nil
+}
+
+// Conflicts reports keys bound to more than one action within a scope
+// and the scopes it inherits.
+func (km Keymap) Conflicts() error {
+	var errs []error
+	for _, s := range KeyScopes {
+		owner := make(map[string]string) // key → "scope.action"
+		for _, scope := range append([]string{s.Name}, s.Inherits...) {
+			for _, action := range sortedActions(km[scope]) {
+				for _, k := range km[scope][action] {
+					name := scope + "." + action
+					if prev, dup := owner[k]; dup && prev != name {
+						// Report each clash once, from the scope that owns it.
+						if scope == s.Name || strings.HasPrefix(prev, s.Name+".") {
+							errs = append(errs, fmt.Errorf("keys: %q is bound to both %s and %s", DisplayKey(k), prev, name))
+						}
+						continue
+					}
+					owner[k] = name
+				}
+			}
+		}
+	}
+	return errors.Join(sortErrors(dedupe(errs))...)
+}
+
+// Keys returns the keys bound to scope.action.
+func (km Keymap) Keys(scope, action string) []string { return km[scope][action] }
+
+// DisplayKey renders a key name for help text.
+func DisplayKey(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+func sortedActions(actions map[string][]string) []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortErrors(errs []error) []error {
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+func dedupe(errs []error) []error {
+	seen := make(map[string]bool, len(errs))
+	out := errs[:0]
+	for _, e := range errs {
+		if !seen[e.Error()] {
+			seen[e.Error()] = true
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeymapHasNoConflicts(t *testing.T) {
+	if err := DefaultKeymap().Conflicts(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildKeymap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]any
+		scope     string
+		action    string
+		want      []string // the action's keys afterwards
+		wantErr   string
+	}{
+		{"defaults", nil, "status", "stage", []string{"s"}, ""},
+		{"single key", map[string]any{"status": map[string]any{"stage": "a"}},
+			"status", "stage", []string{"a"}, ""},
+		{"list with aliases", map[string]any{"navigation": map[string]any{"top": []any{"Home", "ctrl+T"}}},
+			"navigation", "top", []string{"home", "ctrl+t"}, ""},
+		{"inherited scope override", map[string]any{"global": map[string]any{"help": "F1"}},
+			"global", "help", []string{"f1"}, ""},
+		{"conflict within a scope", map[string]any{"status": map[string]any{"stage": "u"}},
+			"", "", nil, `keys: "u" is bound to both status.stage and status.unstage`},
+		{"global override clashing in a view", map[string]any{"global": map[string]any{"help": "x"}},
+			"", "", nil, `keys: "x" is bound to both status.discard and global.help`},
+		{"navigation override clashing in a view", map[string]any{"navigation": map[string]any{"top": "s"}},
+			"", "", nil, `keys: "s" is bound to both status.stage and navigation.top`},
+		{"unknown scope", map[string]any{"nope": map[string]any{"x": "y"}},
+			"", "", nil, `keys: unknown scope "nope"`},
+		{"unknown action", map[string]any{"status": map[string]any{"nope": "y"}},
+			"", "", nil, `keys.status: unknown action "nope"`},
+		{"not a map", map[string]any{"status": "s"},
+			"", "", nil, "keys.status: expected a map of action: keys"},
+		{"not a key", map[string]any{"status": map[string]any{"stage": 1}},
+			"", "", nil, "keys.status.stage: expected a key or list of keys, got 1"},
+		{"empty key", map[string]any{"status": map[string]any{"stage": ""}},
+			"", "", nil, "keys.status.stage: empty key name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := BuildKeymap(tt.overrides)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("BuildKeymap = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := km.Keys(tt.scope, tt.action); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s.%s = %q, want %q", tt.scope, tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConflictsReportedOnce(t *testing.T) {
+	km := DefaultKeymap()
+	km["global"]["help"] = []string{"ctrl+z"}
+	err := km.Conflicts()
+	if err == nil {
+		t.Fatal("Conflicts = nil, want the clash with global.undo")
+	}
+	if got := strings.Count(err.Error(), "\n") + 1; got != 1 {
+		t.Errorf("Conflicts reported %d errors, want 1:\n%v", got, err)
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"G", "G"},
+		{"Enter", "enter"},
+		{"Escape", "esc"},
+		{"space", " "},
+		{" ", " "},
+		{"PageDown", "pgdown"},
+		{"Ctrl+S", "ctrl+s"},
+		{"alt+X", "alt+X"},
+		{"Shift+Tab", "shift+tab"},
+		{"alt+Space", "alt+ "},
+	}
+	for _, tt := range tests {
+		if got := NormalizeKey(tt.in); got != tt.want {
+			t.Errorf("NormalizeKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// AskpassCommand is the hidden zgv subcommand that git and ssh run (via a
+// wrapper script, since they exec GIT_ASKPASS/SSH_ASKPASS directly) when
+// they need a password, passphrase or host-key confirmation. It forwards
+// the prompt to the running TUI (see RunAskpass).
+const AskpassCommand = "__askpass"
+
+// askpassSocketEnv tells the askpass helper where the TUI listens.
+const askpassSocketEnv = "ZGV_ASKPASS_SOCKET"
+
+// ErrAskpassCancelled is returned by RunAskpass when the user dismissed
+// the prompt.
+var ErrAskpassCancelled = errors.New("prompt cancelled")
+
+// askpassRequest and askpassReply are the helper↔TUI protocol: one JSON
+// line each way per prompt.
+type askpassRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+type askpassReply struct {
+	Answer string `json:"answer"`
+	OK     bool   `json:"ok"`
+}
+
+// Askpass answers git's and ssh's credential prompts from the TUI. It
+// listens on a unix socket in a private temporary directory; the helper
+// connects to it for each prompt.
+type Askpass struct {
+	dir      string
+	listener net.Listener
+	ask      AskFunc
+}
+
+// AskFunc answers a prompt, blocking until the user responds. gone is
+// closed if the helper hangs up first — git or ssh exited, say because
+// the command was cancelled or timed out — and the answer is then
+// discarded, so ask should give up and withdraw the prompt.
+type AskFunc func(prompt string, gone <-chan struct{}) (answer string, ok bool)
+
+// StartAskpass starts serving prompts, each answered by ask.
+func StartAskpass(ask AskFunc) (*Askpass, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating zgv executable: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "zgv-askpass-")
+	if err != nil {
+		return nil, fmt.Errorf("creating askpass directory: %w", err)
+	}
+	script := "#!/bin/sh\nexec " + shellQuote(exe) + " " + AskpassCommand + " \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "askpass"), []byte(script), 0o700); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("writing askpass helper: %w", err)
+	}
+	l, err := net.Listen("unix", filepath.Join(dir, "socket"))
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("listening for askpass: %w", err)
+	}
+	a := &Askpass{dir: dir, listener: l, ask: ask}
+	go a.serve()
+	return a, nil
+}
+
+// Env returns the environment that routes a git command's prompts to the
+// TUI. Terminal prompts are turned off: the TUI owns the terminal.
+func (a *Askpass) Env() []string {
+	helper := filepath.Join(a.dir, "askpass")
+	env := []string{
+		"GIT_ASKPASS=" + helper,
+		"SSH_ASKPASS=" + helper,
+		"SSH_ASKPASS_REQUIRE=force",
+		"GIT_TERMINAL_PROMPT=0",
+		askpassSocketEnv + "=" + filepath.Join(a.dir, "socket"),
+	}
+	// ssh before 8.4 ignores SSH_ASKPASS_REQUIRE and only uses the helper
+	// when DISPLAY is set.
+	if os.Getenv("DISPLAY") == "" {
+		env = append(env, "DISPLAY=zgv:0")
+	}
+	return env
+}
+
+// Close stops serving and removes the socket and helper.
+func (a *Askpass) Close() error {
+	err := a.listener.Close()
+	_ = os.RemoveAll(a.dir)
+	return err
+}
+
+func (a *Askpass) serve() {
+	for {
+		conn, err := a.listener.Accept()
+		if err != nil {
+			return // closed
+		}
+		go a.handle(conn)
+	}
+}
+
+func (a *Askpass) handle(conn net.Conn) {
+	defer conn.Close()
+	var req askpassRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	// The helper sends nothing more, so a read returns only once it has
+	// gone (or once conn is closed below).
+	gone := make(chan struct{})
+	go func() {
+		_, _ = conn.Read(make([]byte, 1))
+		close(gone)
+	}()
+	answer, ok := a.ask(req.Prompt, gone)
+	_ = json.NewEncoder(conn).Encode(askpassReply{Answer: answer, OK: ok})
+}
+
+// RunAskpass is the body of the askpass helper: it sends prompt to the
+// TUI named by the environment and returns the user's answer.
+func RunAskpass(prompt string) (string, error) {
+	socket := os.Getenv(askpassSocketEnv)
+	if socket == "" {
+		return "", fmt.Errorf("%s is not set; %s is only run by zgv", askpassSocketEnv, AskpassCommand)
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return "", fmt.Errorf("connecting to zgv: %w", err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(askpassRequest{Prompt: prompt}); err != nil {
+		return "", err
+	}
+	var reply askpassReply
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&reply); err != nil {
+		return "", fmt.Errorf("reading answer: %w", err)
+	}
+	if !reply.OK {
+		return "", ErrAskpassCancelled
+	}
+	return reply.Answer, nil
+}
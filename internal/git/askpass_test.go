@@ -0,0 +1,148 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary stand in for zgv as the askpass helper:
+// the helper script Askpass writes runs the current executable.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == AskpassCommand {
+		answer, err := RunAskpass(strings.Join(os.Args[2:], " "))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(answer)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// newAuthRemote serves a bare copy of the repository at dir over smart
+// HTTP, behind basic auth for user u and password p.
+func newAuthRemote(t *testing.T, dir string) string {
+	t.Helper()
+	execPath, err := exec.Command("git", "--exec-path").Output()
+	if err != nil {
+		t.Skip("git --exec-path:", err)
+	}
+	backend := filepath.Join(strings.TrimSpace(string(execPath)), "git-http-backend")
+	if _, err := os.Stat(backend); err != nil {
+		t.Skip("git-http-backend not installed")
+	}
+	root := t.TempDir()
+	if out, err := exec.Command("git", "clone", "-q", "--bare", dir, filepath.Join(root, "r.git")).CombinedOutput(); err != nil {
+		t.Fatalf("git clone --bare: %v\n%s", err, out)
+	}
+	h := &cgi.Handler{Path: backend, Env: []string{"GIT_PROJECT_ROOT=" + root, "GIT_HTTP_EXPORT_ALL=1"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "u" || p != "p" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL + "/r.git"
+}
+
+func TestAskpassFetch(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers map[string]string // by prompt prefix; a missing one is cancelled
+		wantErr bool
+	}{
+		{"answered", map[string]string{"Username": "u", "Password": "p"}, false},
+		{"wrong password", map[string]string{"Username": "u", "Password": "x"}, true},
+		{"cancelled", map[string]string{"Username": "u"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, dir, git := newTestRepo(t, "a\n")
+			git("remote", "add", "origin", newAuthRemote(t, dir))
+
+			var mu sync.Mutex
+			var prompts []string
+			a, err := StartAskpass(func(prompt string, _ <-chan struct{}) (string, bool) {
+				mu.Lock()
+				prompts = append(prompts, prompt)
+				mu.Unlock()
+				for prefix, answer := range tt.answers {
+					if strings.HasPrefix(prompt, prefix) {
+						return answer, true
+					}
+				}
+				return "", false
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer a.Close()
+			s.SetNetworkEnv(a.Env())
+
+			err = s.Fetch(context.Background(), "origin", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch = %v, want error %v", err, tt.wantErr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(prompts) == 0 || !strings.HasPrefix(prompts[0], "Username for 'http://127.0.0.1:") {
+				t.Errorf("prompts = %q, want a username prompt first", prompts)
+			}
+			if !tt.wantErr {
+				if got := git("rev-parse", "origin/main"); got != git("rev-parse", "main") {
+					t.Errorf("origin/main = %s after fetching, want main's commit", got)
+				}
+			}
+		})
+	}
+}
+
+func TestAskpassGoneWhenCancelled(t *testing.T) {
+	s, dir, git := newTestRepo(t, "a\n")
+	git("remote", "add", "origin", newAuthRemote(t, dir))
+
+	asked := make(chan struct{})
+	withdrawn := make(chan bool, 1)
+	a, err := StartAskpass(func(_ string, gone <-chan struct{}) (string, bool) {
+		close(asked)
+		select {
+		case <-gone:
+			withdrawn <- true
+		case <-time.After(10 * time.Second):
+			withdrawn <- false
+		}
+		return "", false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	s.SetNetworkEnv(a.Env())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-asked
+		cancel()
+	}()
+	if err := s.Fetch(ctx, "origin", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Fetch = %v, want context.Canceled", err)
+	}
+	if !<-withdrawn {
+		t.Error("the prompt wasn't withdrawn when the fetch was cancelled")
+	}
+}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 )
@@ -142,6 +144,26 @@ func (c *CachedService) IsRebasing() bool {
 	return v
 }
 
+// IsCherryPicking delegates to the inner service (cached).
+func (c *CachedService) IsCherryPicking() bool {
+	if v, ok, _ := c.get("ischerrypicking"); ok {
+		return v.(bool)
+	}
+	v := c.inner.IsCherryPicking()
+	c.set("ischerrypicking", v, nil)
+	return v
+}
+
+// IsReverting delegates to the inner service (cached).
+func (c *CachedService) IsReverting() bool {
+	if v, ok, _ := c.get("isreverting"); ok {
+		return v.(bool)
+	}
+	v := c.inner.IsReverting()
+	c.set("isreverting", v, nil)
+	return v
+}
+
 // AheadBehind delegates to the inner service (cached).
 func (c *CachedService) AheadBehind() (int, int, error) {
 	type ab struct{ a, b int }
@@ -154,6 +176,17 @@ func (c *CachedService) AheadBehind() (int, int, error) {
 	return a, b, err
 }
 
+// BranchesAheadBehind delegates to the inner service (cached).
+func (c *CachedService) BranchesAheadBehind(base string) (map[string][2]int, error) {
+	key := "aheadbehind:" + base
+	if v, ok, err := c.get(key); ok {
+		return v.(map[string][2]int), err
+	}
+	counts, err := c.inner.BranchesAheadBehind(base)
+	c.set(key, counts, err)
+	return counts, err
+}
+
 // Upstream delegates to the inner service (cached).
 func (c *CachedService) Upstream() string {
 	if v, ok, _ := c.get("upstream"); ok {
@@ -164,6 +197,11 @@ func (c *CachedService) Upstream() string {
 	return v
 }
 
+// RefHash delegates to the inner service (not cached).
+func (c *CachedService) RefHash(ref string) string {
+	return c.inner.RefHash(ref)
+}
+
 // ── Status (cached) ─────────────────────────────────────────────────────────
 
 // Status delegates to the inner service (cached).
@@ -203,14 +241,49 @@ func (c *CachedService) Discard(paths ...string) error {
 	return c.invalidateAndReturn(c.inner.Discard(paths...))
 }
 
+// StagePatch stages a partial patch and invalidates the cache.
+func (c *CachedService) StagePatch(patch string) error {
+	return c.invalidateAndReturn(c.inner.StagePatch(patch))
+}
+
+// UnstagePatch unstages a partial patch and invalidates the cache.
+func (c *CachedService) UnstagePatch(patch string) error {
+	return c.invalidateAndReturn(c.inner.UnstagePatch(patch))
+}
+
+// DiscardPatch discards a partial patch and invalidates the cache.
+func (c *CachedService) DiscardPatch(patch string) error {
+	return c.invalidateAndReturn(c.inner.DiscardPatch(patch))
+}
+
 // Commit creates a commit and invalidates the cache.
-func (c *CachedService) Commit(message string) error {
-	return c.invalidateAndReturn(c.inner.Commit(message))
+func (c *CachedService) Commit(message string, opts CommitOptions) error {
+	return c.invalidateAndReturn(c.inner.Commit(message, opts))
 }
 
 // CommitAmend amends the last commit and invalidates the cache.
-func (c *CachedService) CommitAmend(message string) error {
-	return c.invalidateAndReturn(c.inner.CommitAmend(message))
+func (c *CachedService) CommitAmend(message string, opts CommitOptions) error {
+	return c.invalidateAndReturn(c.inner.CommitAmend(message, opts))
+}
+
+// CommitSigning delegates to the inner service (not cached).
+func (c *CachedService) CommitSigning() (Signing, error) {
+	return c.inner.CommitSigning()
+}
+
+// CommitFixup creates a fixup!/squash! commit and invalidates the cache.
+func (c *CachedService) CommitFixup(target string, squash bool, message string) error {
+	return c.invalidateAndReturn(c.inner.CommitFixup(target, squash, message))
+}
+
+// Reword rewrites a commit message and invalidates the cache.
+func (c *CachedService) Reword(hash, message string) error {
+	return c.invalidateAndReturn(c.inner.Reword(hash, message))
+}
+
+// CommitTemplate delegates to the inner service (not cached).
+func (c *CachedService) CommitTemplate() (string, error) {
+	return c.inner.CommitTemplate()
 }
 
 // ── Log (not cached — already limited by max-count) ─────────────────────────
@@ -225,9 +298,30 @@ func (c *CachedService) LogGraph(limit int) ([]GraphEntry, error) {
 	return c.inner.LogGraph(limit)
 }
 
-// Show delegates to the inner service (not cached).
-func (c *CachedService) Show(hash string) (*Commit, string, error) {
-	return c.inner.Show(hash)
+// CommitFiles delegates to the inner service (not cached).
+func (c *CachedService) CommitFiles(hash string) ([]DiffFileStat, error) {
+	return c.inner.CommitFiles(hash)
+}
+
+// CommitFile delegates to the inner service (not cached).
+func (c *CachedService) CommitFile(hash, path, oldPath string) (string, error) {
+	return c.inner.CommitFile(hash, path, oldPath)
+}
+
+// Reflog returns a ref's reflog (cached).
+func (c *CachedService) Reflog(ref string, limit int) ([]ReflogEntry, error) {
+	key := fmt.Sprintf("reflog:%s:%d", ref, limit)
+	if v, ok, err := c.get(key); ok {
+		return v.([]ReflogEntry), err
+	}
+	v, err := c.inner.Reflog(ref, limit)
+	c.set(key, v, err)
+	return v, err
+}
+
+// Reset resets to ref and invalidates the cache.
+func (c *CachedService) Reset(ref string, mode ResetMode) error {
+	return c.invalidateAndReturn(c.inner.Reset(ref, mode))
 }
 
 // ── Diff (not cached — content is large and changes per-file) ───────────────
@@ -237,11 +331,41 @@ func (c *CachedService) Diff(staged bool, path string) (string, error) {
 	return c.inner.Diff(staged, path)
 }
 
+// DiffStat delegates to the inner service (not cached).
+func (c *CachedService) DiffStat(staged bool, path string) (string, error) {
+	return c.inner.DiffStat(staged, path)
+}
+
 // DiffRange delegates to the inner service (not cached).
 func (c *CachedService) DiffRange(from, to string) (string, error) {
 	return c.inner.DiffRange(from, to)
 }
 
+// DiffRangeStat delegates to the inner service (not cached).
+func (c *CachedService) DiffRangeStat(from, to string) (string, error) {
+	return c.inner.DiffRangeStat(from, to)
+}
+
+// DiffFiles delegates to the inner service (not cached).
+func (c *CachedService) DiffFiles(staged bool) ([]DiffFileStat, error) {
+	return c.inner.DiffFiles(staged)
+}
+
+// DiffFile delegates to the inner service (not cached).
+func (c *CachedService) DiffFile(staged bool, path, oldPath string) (string, error) {
+	return c.inner.DiffFile(staged, path, oldPath)
+}
+
+// DiffRangeFiles delegates to the inner service (not cached).
+func (c *CachedService) DiffRangeFiles(rng string) ([]DiffFileStat, error) {
+	return c.inner.DiffRangeFiles(rng)
+}
+
+// DiffRangeFile delegates to the inner service (not cached).
+func (c *CachedService) DiffRangeFile(rng, path, oldPath string) (string, error) {
+	return c.inner.DiffRangeFile(rng, path, oldPath)
+}
+
 // ── Branches (cached) ───────────────────────────────────────────────────────
 
 // Branches delegates to the inner service (cached).
@@ -255,8 +379,8 @@ func (c *CachedService) Branches() ([]Branch, error) {
 }
 
 // CreateBranch creates a branch and invalidates the cache.
-func (c *CachedService) CreateBranch(name string) error {
-	return c.invalidateAndReturn(c.inner.CreateBranch(name))
+func (c *CachedService) CreateBranch(name, start string) error {
+	return c.invalidateAndReturn(c.inner.CreateBranch(name, start))
 }
 
 // SwitchBranch switches to a branch and invalidates the cache.
@@ -264,6 +388,11 @@ func (c *CachedService) SwitchBranch(name string) error {
 	return c.invalidateAndReturn(c.inner.SwitchBranch(name))
 }
 
+// CheckoutDetached detaches HEAD at rev and invalidates the cache.
+func (c *CachedService) CheckoutDetached(rev string) error {
+	return c.invalidateAndReturn(c.inner.CheckoutDetached(rev))
+}
+
 // DeleteBranch deletes a branch and invalidates the cache.
 func (c *CachedService) DeleteBranch(name string, force bool) error {
 	return c.invalidateAndReturn(c.inner.DeleteBranch(name, force))
@@ -274,11 +403,59 @@ func (c *CachedService) MergeBranch(name string) error {
 	return c.invalidateAndReturn(c.inner.MergeBranch(name))
 }
 
+// CheckoutTracking creates and switches to a tracking branch and
+// invalidates the cache.
+func (c *CachedService) CheckoutTracking(name, remoteBranch string) error {
+	return c.invalidateAndReturn(c.inner.CheckoutTracking(name, remoteBranch))
+}
+
+// SetUpstream sets a branch's upstream and invalidates the cache.
+func (c *CachedService) SetUpstream(branch, upstream string) error {
+	return c.invalidateAndReturn(c.inner.SetUpstream(branch, upstream))
+}
+
+// UnsetUpstream unsets a branch's upstream and invalidates the cache.
+func (c *CachedService) UnsetUpstream(branch string) error {
+	return c.invalidateAndReturn(c.inner.UnsetUpstream(branch))
+}
+
 // RenameBranch renames a branch and invalidates the cache.
 func (c *CachedService) RenameBranch(oldName, newName string) error {
 	return c.invalidateAndReturn(c.inner.RenameBranch(oldName, newName))
 }
 
+// ── Tags (cached list, invalidate on mutation) ──────────────────────────────
+
+// Tags delegates to the inner service (cached).
+func (c *CachedService) Tags() ([]Tag, error) {
+	if v, ok, err := c.get("tags"); ok {
+		return v.([]Tag), err
+	}
+	v, err := c.inner.Tags()
+	c.set("tags", v, err)
+	return v, err
+}
+
+// CreateTag creates a tag and invalidates the cache.
+func (c *CachedService) CreateTag(name, ref, message string, annotated, signed bool) error {
+	return c.invalidateAndReturn(c.inner.CreateTag(name, ref, message, annotated, signed))
+}
+
+// DeleteTag deletes a tag and invalidates the cache.
+func (c *CachedService) DeleteTag(name string) error {
+	return c.invalidateAndReturn(c.inner.DeleteTag(name))
+}
+
+// PushTag pushes a tag and invalidates the cache.
+func (c *CachedService) PushTag(ctx context.Context, remote, name string, progress ProgressFunc) error {
+	return c.invalidateAndReturn(c.inner.PushTag(ctx, remote, name, progress))
+}
+
+// DeleteRemoteTag deletes a remote tag and invalidates the cache.
+func (c *CachedService) DeleteRemoteTag(ctx context.Context, remote, name string, progress ProgressFunc) error {
+	return c.invalidateAndReturn(c.inner.DeleteRemoteTag(ctx, remote, name, progress))
+}
+
 // ── Stash (cached list, invalidate on mutation) ─────────────────────────────
 
 // StashList delegates to the inner service (cached).
@@ -316,6 +493,11 @@ func (c *CachedService) StashShow(index int) (string, error) {
 	return c.inner.StashShow(index)
 }
 
+// StashStat delegates to the inner service (not cached).
+func (c *CachedService) StashStat(index int) (string, error) {
+	return c.inner.StashStat(index)
+}
+
 // ── Remotes (cached) ────────────────────────────────────────────────────────
 
 // Remotes delegates to the inner service (cached).
@@ -328,19 +510,61 @@ func (c *CachedService) Remotes() ([]Remote, error) {
 	return v, err
 }
 
+// RemoteBranches returns a remote's remote-tracking branches (cached).
+func (c *CachedService) RemoteBranches(remote string) ([]Branch, error) {
+	key := "remote-branches:" + remote
+	if v, ok, err := c.get(key); ok {
+		return v.([]Branch), err
+	}
+	v, err := c.inner.RemoteBranches(remote)
+	c.set(key, v, err)
+	return v, err
+}
+
 // Fetch fetches from remote and invalidates the cache.
-func (c *CachedService) Fetch(remote string) error {
-	return c.invalidateAndReturn(c.inner.Fetch(remote))
+func (c *CachedService) Fetch(ctx context.Context, remote string, progress ProgressFunc) error {
+	return c.invalidateAndReturn(c.inner.Fetch(ctx, remote, progress))
 }
 
 // Pull pulls from remote and invalidates the cache.
-func (c *CachedService) Pull(remote, branch string) error {
-	return c.invalidateAndReturn(c.inner.Pull(remote, branch))
+func (c *CachedService) Pull(ctx context.Context, remote, branch string, opts PullOptions, progress ProgressFunc) error {
+	return c.invalidateAndReturn(c.inner.Pull(ctx, remote, branch, opts, progress))
+}
+
+// PullDefault delegates to the inner service (not cached).
+func (c *CachedService) PullDefault() PullMode {
+	return c.inner.PullDefault()
 }
 
 // Push pushes to remote and invalidates the cache.
-func (c *CachedService) Push(remote, branch string, force bool) error {
-	return c.invalidateAndReturn(c.inner.Push(remote, branch, force))
+func (c *CachedService) Push(ctx context.Context, opts PushOptions, progress ProgressFunc) error {
+	return c.invalidateAndReturn(c.inner.Push(ctx, opts, progress))
+}
+
+// AddRemote adds a remote and invalidates the cache.
+func (c *CachedService) AddRemote(name, url string) error {
+	return c.invalidateAndReturn(c.inner.AddRemote(name, url))
+}
+
+// RemoveRemote removes a remote and invalidates the cache.
+func (c *CachedService) RemoveRemote(name string) error {
+	return c.invalidateAndReturn(c.inner.RemoveRemote(name))
+}
+
+// RenameRemote renames a remote and invalidates the cache.
+func (c *CachedService) RenameRemote(oldName, newName string) error {
+	return c.invalidateAndReturn(c.inner.RenameRemote(oldName, newName))
+}
+
+// SetRemoteURL changes a remote's URL and invalidates the cache.
+func (c *CachedService) SetRemoteURL(name, url string, push bool) error {
+	return c.invalidateAndReturn(c.inner.SetRemoteURL(name, url, push))
+}
+
+// PruneRemote prunes stale remote-tracking branches and invalidates the
+// cache.
+func (c *CachedService) PruneRemote(ctx context.Context, name string) error {
+	return c.invalidateAndReturn(c.inner.PruneRemote(ctx, name))
 }
 
 // ── Worktrees ───────────────────────────────────────────────────────────────
@@ -368,8 +592,13 @@ func (c *CachedService) WorktreeRemove(path string) error {
 // ── Rebase (write-only, always invalidates) ─────────────────────────────────
 
 // RebaseInteractive starts interactive rebase and invalidates the cache.
-func (c *CachedService) RebaseInteractive(onto string) error {
-	return c.invalidateAndReturn(c.inner.RebaseInteractive(onto))
+func (c *CachedService) RebaseInteractive(onto string, todo []RebaseTodoItem) error {
+	return c.invalidateAndReturn(c.inner.RebaseInteractive(onto, todo))
+}
+
+// RebaseAutosquash folds fixup commits and invalidates the cache.
+func (c *CachedService) RebaseAutosquash(onto string) error {
+	return c.invalidateAndReturn(c.inner.RebaseAutosquash(onto))
 }
 
 // RebaseContinue continues rebase and invalidates the cache.
@@ -377,11 +606,48 @@ func (c *CachedService) RebaseContinue() error {
 	return c.invalidateAndReturn(c.inner.RebaseContinue())
 }
 
+// RebaseSkip skips the current rebase step and invalidates the cache.
+func (c *CachedService) RebaseSkip() error {
+	return c.invalidateAndReturn(c.inner.RebaseSkip())
+}
+
+// RebaseProgress is never cached — it reads the rebase state directly.
+func (c *CachedService) RebaseProgress() (*RebaseProgress, error) {
+	return c.inner.RebaseProgress()
+}
+
 // RebaseAbort aborts rebase and invalidates the cache.
 func (c *CachedService) RebaseAbort() error {
 	return c.invalidateAndReturn(c.inner.RebaseAbort())
 }
 
+// ── Cherry-pick & revert (mutations → invalidate) ───────────────────────────
+
+// CherryPick applies commits and invalidates the cache.
+func (c *CachedService) CherryPick(hashes []string, opts PickOptions) error {
+	return c.invalidateAndReturn(c.inner.CherryPick(hashes, opts))
+}
+
+// Revert reverts commits and invalidates the cache.
+func (c *CachedService) Revert(hashes []string, opts PickOptions) error {
+	return c.invalidateAndReturn(c.inner.Revert(hashes, opts))
+}
+
+// SequencerContinue continues the sequence and invalidates the cache.
+func (c *CachedService) SequencerContinue() error {
+	return c.invalidateAndReturn(c.inner.SequencerContinue())
+}
+
+// SequencerSkip skips the current commit and invalidates the cache.
+func (c *CachedService) SequencerSkip() error {
+	return c.invalidateAndReturn(c.inner.SequencerSkip())
+}
+
+// SequencerAbort aborts the sequence and invalidates the cache.
+func (c *CachedService) SequencerAbort() error {
+	return c.invalidateAndReturn(c.inner.SequencerAbort())
+}
+
 // ── Bisect ──────────────────────────────────────────────────────────────────
 
 // BisectStart starts bisect and invalidates the cache.
@@ -421,7 +687,48 @@ func (c *CachedService) ConflictFiles() ([]string, error) {
 	return v, err
 }
 
+// ConflictDetails delegates to the inner service (cached).
+func (c *CachedService) ConflictDetails() ([]ConflictFile, error) {
+	if v, ok, err := c.get("conflict-details"); ok {
+		return v.([]ConflictFile), err
+	}
+	v, err := c.inner.ConflictDetails()
+	c.set("conflict-details", v, err)
+	return v, err
+}
+
+// ConflictContent is never cached — the merge editor must see the file as
+// it is on disk.
+func (c *CachedService) ConflictContent(path string) (string, error) {
+	return c.inner.ConflictContent(path)
+}
+
 // MarkResolved marks a conflict as resolved and invalidates the cache.
 func (c *CachedService) MarkResolved(path string) error {
 	return c.invalidateAndReturn(c.inner.MarkResolved(path))
 }
+
+// ResolveConflict writes a resolution and invalidates the cache.
+func (c *CachedService) ResolveConflict(path, content string) error {
+	return c.invalidateAndReturn(c.inner.ResolveConflict(path, content))
+}
+
+// ResolveWithSide takes one side of a conflict and invalidates the cache.
+func (c *CachedService) ResolveWithSide(path string, side ConflictSide) error {
+	return c.invalidateAndReturn(c.inner.ResolveWithSide(path, side))
+}
+
+// ── Undo journal ────────────────────────────────────────────────────────────
+
+// Undo delegates to the inner service and invalidates the cache — even on
+// failure, since an undo can stop part-way.
+func (c *CachedService) Undo() (string, error) {
+	defer c.Invalidate()
+	return c.inner.Undo()
+}
+
+// Redo delegates to the inner service and invalidates the cache.
+func (c *CachedService) Redo() (string, error) {
+	defer c.Invalidate()
+	return c.inner.Redo()
+}
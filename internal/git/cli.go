@@ -5,9 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -57,6 +59,21 @@ func releaseGitSemaphore() {
 type CLIService struct {
 	root   string // Absolute path to the repo root.
 	gitDir string // Path to the .git directory.
+
+	// diffContext is the -U<n> passed to diff-producing commands; a
+	// negative value leaves git's default (3) in place.
+	diffContext int
+
+	// commitTimeout bounds a commit, hooks and signing included.
+	commitTimeout time.Duration
+
+	// rebaseTimeout bounds a rebase, which may replay many commits and
+	// run exec lines and hooks on each.
+	rebaseTimeout time.Duration
+
+	// networkEnv is added to the environment of network commands; it
+	// routes credential prompts to the TUI (see Askpass).
+	networkEnv []string
 }
 
 // Compile-time check that CLIService implements Service.
@@ -81,11 +98,38 @@ func NewCLIService(path string) (*CLIService, error) {
 		gd = filepath.Join(strings.TrimSpace(topLevel), gd)
 	}
 	return &CLIService{
-		root:   strings.TrimSpace(topLevel),
-		gitDir: gd,
+		root:          strings.TrimSpace(topLevel),
+		gitDir:        gd,
+		diffContext:   -1,
+		commitTimeout: cmdTimeoutWrite,
+		rebaseTimeout: cmdTimeoutWrite,
 	}, nil
 }
 
+// SetDiffContext sets the number of context lines used by Diff,
+// DiffRange, Show and StashShow. Negative values restore git's default.
+func (s *CLIService) SetDiffContext(lines int) { s.diffContext = lines }
+
+// SetCommitTimeout sets how long Commit and CommitAmend may run, for
+// repositories with slow pre-commit hooks.
+func (s *CLIService) SetCommitTimeout(d time.Duration) { s.commitTimeout = d }
+
+// SetRebaseTimeout sets how long a rebase (or a step of one, when it is
+// continued or skipped) may run, exec lines and hooks included.
+func (s *CLIService) SetRebaseTimeout(d time.Duration) { s.rebaseTimeout = d }
+
+// SetNetworkEnv sets extra environment for fetch, pull and push.
+func (s *CLIService) SetNetworkEnv(env []string) { s.networkEnv = env }
+
+// contextArgs returns the -U<n> flag for diff-producing commands, or nil
+// when git's default applies.
+func (s *CLIService) contextArgs() []string {
+	if s.diffContext < 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("-U%d", s.diffContext)}
+}
+
 // GitDir returns the path to the .git directory.
 func (s *CLIService) GitDir() string { return s.gitDir }
 
@@ -107,16 +151,166 @@ func (s *CLIService) runWrite(args ...string) (string, error) {
 	return runGit(s.root, nil, cmdTimeoutWrite, args...)
 }
 
+// runWriteInput executes a write git command with the given string piped
+// to stdin. Used by `git apply` to read patches without a temp file.
+func (s *CLIService) runWriteInput(stdin string, args ...string) (string, error) {
+	return runGitInput(s.root, nil, cmdTimeoutWrite, stdin, args...)
+}
+
+// runWriteEnv executes a write git command with extra environment
+// variables (e.g. GIT_SEQUENCE_EDITOR for rebases).
+func (s *CLIService) runWriteEnv(env []string, args ...string) (string, error) {
+	return runGit(s.root, env, cmdTimeoutWrite, args...)
+}
+
+// runRebase executes a rebase command with the rebase timeout; killing
+// one early would leave the repository mid-rebase.
+func (s *CLIService) runRebase(env []string, args ...string) (string, error) {
+	return runGit(s.root, env, s.rebaseTimeout, args...)
+}
+
 // runNetwork executes a network git command (fetch/push/pull) with a
-// generous timeout.
-func (s *CLIService) runNetwork(args ...string) (string, error) {
-	return runGit(s.root, nil, cmdTimeoutNetwork, args...)
+// generous timeout. Unless progress is nil, --progress makes git report
+// progress although stderr isn't a terminal; each progress line is parsed
+// and handed to progress as it arrives. Cancelling ctx kills git.
+func (s *CLIService) runNetwork(ctx context.Context, progress ProgressFunc, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, cmdTimeoutNetwork)
+	defer cancel()
+
+	if err := acquireGitSemaphore(ctx); err != nil {
+		return fmt.Errorf("git %s: waiting for semaphore: %w", args[0], err)
+	}
+	defer releaseGitSemaphore()
+
+	if progress != nil {
+		args = append([]string{args[0], "--progress"}, args[1:]...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = s.root
+	if len(s.networkEnv) > 0 {
+		cmd.Env = append(os.Environ(), s.networkEnv...)
+	}
+	killGroup(cmd)
+	// A helper that escapes the group can still hold stderr open; don't
+	// wait on it.
+	cmd.WaitDelay = 2 * time.Second
+
+	pw := &progressWriter{progress: progress}
+	cmd.Stderr = pw
+	if err := cmd.Run(); err != nil {
+		pw.flush()
+		switch {
+		case errors.Is(ctx.Err(), context.Canceled):
+			return fmt.Errorf("git %s: %w", args[0], context.Canceled)
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return fmt.Errorf("git %s: timed out after %s", args[0], cmdTimeoutNetwork)
+		}
+		return fmt.Errorf("git %s: %s: %w", args[0], pw.errorText(), err)
+	}
+	return nil
+}
+
+// progressWriter splits a network command's stderr into lines (git ends
+// progress updates with \r), passing progress lines to progress and
+// keeping the rest for the error message.
+type progressWriter struct {
+	progress ProgressFunc
+	partial  []byte
+	messages []string
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexAny(w.partial, "\r\n")
+		if i < 0 {
+			return len(p), nil
+		}
+		w.line(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+}
+
+func (w *progressWriter) flush() {
+	w.line(string(w.partial))
+	w.partial = nil
+}
+
+func (w *progressWriter) line(line string) {
+	if p, ok := ParseProgress(line); ok {
+		if w.progress != nil {
+			w.progress(p)
+		}
+		return
+	}
+	if line = strings.TrimSpace(line); line != "" {
+		w.messages = append(w.messages, line)
+	}
+}
+
+// errorText picks git's complaints (fatal:, error:, rejected refs and
+// conflicts) out of the messages, so the reason a command failed isn't
+// buried under remote chatter. Without any, it returns every message.
+func (w *progressWriter) errorText() string {
+	var errs []string
+	for _, m := range w.messages {
+		for _, p := range []string{"fatal:", "error:", "!", "CONFLICT"} {
+			if strings.HasPrefix(m, p) {
+				errs = append(errs, m)
+				break
+			}
+		}
+	}
+	if len(errs) == 0 {
+		errs = w.messages
+	}
+	return strings.Join(errs, "\n")
+}
+
+// runWriteStream executes a write git command that may take a while and
+// print as it goes (hooks), copying its stdout and stderr to out as they
+// are written. stdin, when non-nil, is attached so the command can prompt.
+func (s *CLIService) runWriteStream(timeout time.Duration, stdin io.Reader, out io.Writer, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := acquireGitSemaphore(ctx); err != nil {
+		return fmt.Errorf("git %s: waiting for semaphore: %w", args[0], err)
+	}
+	defer releaseGitSemaphore()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = s.root
+	cmd.Stdin = stdin
+
+	// Keep stderr for the error; the full output already went to out.
+	var stderr bytes.Buffer
+	if out == nil {
+		out = io.Discard
+	}
+	cmd.Stdout = out
+	cmd.Stderr = io.MultiWriter(out, &stderr)
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("git %s: timed out after %s", args[0], timeout)
+		}
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		return fmt.Errorf("git %s: %s: %w", args[0], strings.TrimSpace(lines[len(lines)-1]), err)
+	}
+	return nil
 }
 
 // runGit executes a git command with a context timeout and a bounded
 // concurrency semaphore. Stdout and stderr are separated so stderr noise
 // doesn't corrupt output.
 func runGit(dir string, extraEnv []string, timeout time.Duration, args ...string) (string, error) {
+	return runGitInput(dir, extraEnv, timeout, "", args...)
+}
+
+// runGitInput is runGit with an optional stdin payload. An empty stdin
+// leaves the child's stdin unattached, matching runGit's behaviour.
+func runGitInput(dir string, extraEnv []string, timeout time.Duration, stdin string, args ...string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -134,6 +328,10 @@ func runGit(dir string, extraEnv []string, timeout time.Duration, args ...string
 		cmd.Env = append(os.Environ(), extraEnv...)
 	}
 
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -197,6 +395,31 @@ func (s *CLIService) IsRebasing() bool {
 	return false
 }
 
+// IsCherryPicking reports whether a cherry-pick is in progress: stopped on
+// a conflict, or between the commits of a multi-commit pick.
+func (s *CLIService) IsCherryPicking() bool {
+	return s.sequencerOp("CHERRY_PICK_HEAD", "pick")
+}
+
+// IsReverting reports whether a revert is in progress.
+func (s *CLIService) IsReverting() bool {
+	return s.sequencerOp("REVERT_HEAD", "revert")
+}
+
+// sequencerOp reports whether head exists in the git dir, or the
+// sequencer's todo list still has a cmd step left.
+func (s *CLIService) sequencerOp(head, cmd string) bool {
+	if _, err := os.Stat(filepath.Join(s.gitDir, head)); err == nil {
+		return true
+	}
+	todo, err := os.ReadFile(filepath.Join(s.gitDir, "sequencer", "todo"))
+	if err != nil {
+		return false
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(string(todo)), " ")
+	return first == cmd || (cmd == "pick" && first == "p")
+}
+
 // AheadBehind returns how many commits ahead/behind the upstream.
 func (s *CLIService) AheadBehind() (int, int, error) {
 	out, err := s.run("rev-list", "--left-right", "--count", "HEAD...@{upstream}")
@@ -213,6 +436,42 @@ func (s *CLIService) AheadBehind() (int, int, error) {
 	return ahead, behind, nil
 }
 
+// BranchesAheadBehind counts, for every local and remote-tracking branch,
+// the commits it has that base doesn't (ahead) and the reverse (behind),
+// keyed by full ref name. Git 2.41 and later count them all in one pass;
+// older versions are asked branch by branch.
+func (s *CLIService) BranchesAheadBehind(base string) (map[string][2]int, error) {
+	counts := map[string][2]int{}
+	out, err := s.run("for-each-ref", "--format=%(refname) %(ahead-behind:"+base+")", "refs/heads", "refs/remotes")
+	if err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			var ref string
+			var ahead, behind int
+			if _, err := fmt.Sscan(line, &ref, &ahead, &behind); err == nil {
+				counts[ref] = [2]int{ahead, behind}
+			}
+		}
+		return counts, nil
+	}
+
+	refs, err := s.run("for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range strings.Fields(refs) {
+		out, err := s.run("rev-list", "--left-right", "--count", ref+"..."+base)
+		if err != nil {
+			return nil, err
+		}
+		var ahead, behind int
+		if _, err := fmt.Sscan(out, &ahead, &behind); err != nil {
+			return nil, fmt.Errorf("counting %s...%s: %w", ref, base, err)
+		}
+		counts[ref] = [2]int{ahead, behind}
+	}
+	return counts, nil
+}
+
 // Upstream returns the upstream tracking branch name.
 func (s *CLIService) Upstream() string {
 	out, err := s.run("rev-parse", "--abbrev-ref", "@{upstream}")
@@ -222,6 +481,16 @@ func (s *CLIService) Upstream() string {
 	return strings.TrimSpace(out)
 }
 
+// RefHash returns the commit ref (a full ref name) points at, or "" if it
+// doesn't exist.
+func (s *CLIService) RefHash(ref string) string {
+	out, err := s.run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
 // ── Status & staging ────────────────────────────────────────────────────────
 
 // Status returns the current working tree status.
@@ -265,20 +534,171 @@ func (s *CLIService) Discard(paths ...string) error {
 	return err
 }
 
+// StagePatch applies a (partial) patch to the index only.
+func (s *CLIService) StagePatch(patch string) error {
+	return s.applyPatch(patch, "--cached")
+}
+
+// UnstagePatch reverse-applies a patch built from the staged diff to the index.
+func (s *CLIService) UnstagePatch(patch string) error {
+	return s.applyPatch(patch, "--cached", "--reverse")
+}
+
+// DiscardPatch reverse-applies a patch built from the unstaged diff to the
+// working tree, dropping just those lines.
+func (s *CLIService) DiscardPatch(patch string) error {
+	return s.applyPatch(patch, "--reverse")
+}
+
+// applyPatch pipes patch into `git apply`. Patches built from -U0 diffs
+// carry no context, which git only accepts with --unidiff-zero.
+func (s *CLIService) applyPatch(patch string, flags ...string) error {
+	args := append([]string{"apply"}, flags...)
+	args = append(args, "--whitespace=nowarn")
+	if s.diffContext == 0 {
+		args = append(args, "--unidiff-zero")
+	}
+	_, err := s.runWriteInput(patch, append(args, "-")...)
+	return err
+}
+
 // ── Commits ─────────────────────────────────────────────────────────────────
 
 // Commit creates a new commit with the given message.
-func (s *CLIService) Commit(message string) error {
-	_, err := s.runWrite("commit", "-m", message)
-	return err
+func (s *CLIService) Commit(message string, opts CommitOptions) error {
+	return s.runWriteStream(s.commitTimeout, opts.Stdin, opts.Output, commitArgs(opts, "-m", message)...)
 }
 
 // CommitAmend amends the last commit with the given message.
-func (s *CLIService) CommitAmend(message string) error {
-	_, err := s.runWrite("commit", "--amend", "-m", message)
+func (s *CLIService) CommitAmend(message string, opts CommitOptions) error {
+	return s.runWriteStream(s.commitTimeout, opts.Stdin, opts.Output, commitArgs(opts, "--amend", "-m", message)...)
+}
+
+// commitArgs builds a commit command line from opts and args.
+func commitArgs(opts CommitOptions, args ...string) []string {
+	cmd := []string{"commit"}
+	if opts.SignOff {
+		cmd = append(cmd, "--signoff")
+	}
+	if opts.Sign {
+		cmd = append(cmd, "-S")
+	} else {
+		cmd = append(cmd, "--no-gpg-sign")
+	}
+	if opts.NoVerify {
+		cmd = append(cmd, "--no-verify")
+	}
+	return append(cmd, args...)
+}
+
+// CommitSigning reads the repository's commit signing configuration.
+func (s *CLIService) CommitSigning() (Signing, error) {
+	get := func(args ...string) (string, error) {
+		out, err := s.run(append([]string{"config", "--default="}, args...)...)
+		return strings.TrimSpace(out), err
+	}
+	sign, err := get("--type=bool", "commit.gpgsign")
+	if err != nil {
+		return Signing{}, err
+	}
+	format, err := get("gpg.format")
+	if err != nil {
+		return Signing{}, err
+	}
+	if format == "" {
+		format = "openpgp"
+	}
+	key, err := get("user.signingkey")
+	if err != nil {
+		return Signing{}, err
+	}
+	return Signing{Enabled: sign == "true", Format: format, Key: key}, nil
+}
+
+// CommitFixup commits the staged changes as a "fixup!" (or, with squash,
+// "squash!") commit for target, to be folded in by RebaseAutosquash. A
+// squash commit's message is appended to target's when they are folded.
+func (s *CLIService) CommitFixup(target string, squash bool, message string) error {
+	args := []string{"commit", "--fixup=" + target}
+	if squash {
+		args = []string{"commit", "--squash=" + target}
+		if message != "" {
+			args = append(args, "-m", message)
+		}
+	}
+	_, err := s.runWriteEnv([]string{"GIT_EDITOR=:"}, args...)
 	return err
 }
 
+// Reword replaces the message of hash, which must be on the current
+// branch. HEAD is amended in place; older commits are replayed with an
+// interactive rebase that rewrites the message without opening an editor.
+func (s *CLIService) Reword(hash, message string) error {
+	full, err := s.run("rev-parse", "--verify", hash+"^{commit}")
+	if err != nil {
+		return err
+	}
+	head, err := s.run("rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return err
+	}
+	full, head = strings.TrimSpace(full), strings.TrimSpace(head)
+	if full == head {
+		_, err := s.runWrite("commit", "--amend", "--only", "--allow-empty", "-m", message)
+		return err
+	}
+
+	if _, err := s.run("merge-base", "--is-ancestor", full, head); err != nil {
+		return fmt.Errorf("%s is not on the current branch", shortHash(full))
+	}
+	merges, err := s.run("rev-list", "--merges", full+"..HEAD")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(merges) != "" {
+		return fmt.Errorf("cannot reword %s: merge commits follow it", shortHash(full))
+	}
+	// A root commit has no parent to rebase onto.
+	onto, span := "--root", "HEAD"
+	if _, err := s.run("rev-parse", "--verify", "--quiet", full+"^"); err == nil {
+		onto, span = full+"^", full+"^..HEAD"
+	}
+	out, err := s.run("rev-list", "--reverse", "--first-parent", span)
+	if err != nil {
+		return err
+	}
+	var todo []RebaseTodoItem
+	for _, h := range strings.Fields(out) {
+		item := RebaseTodoItem{Action: RebasePick, Hash: h}
+		if h == full {
+			item.Action, item.Message = RebaseReword, message
+		}
+		todo = append(todo, item)
+	}
+	return s.rebaseTodo(todo, "--autostash", onto)
+}
+
+// CommitTemplate returns the contents of the commit.template file, or ""
+// when none is configured.
+func (s *CLIService) CommitTemplate() (string, error) {
+	out, err := s.run("config", "--path", "--default=", "commit.template")
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(out)
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.root, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading commit.template: %w", err)
+	}
+	return string(data), nil
+}
+
 // Log returns the commit log.
 func (s *CLIService) Log(limit int, args ...string) ([]Commit, error) {
 	cmdArgs := []string{
@@ -293,6 +713,22 @@ func (s *CLIService) Log(limit int, args ...string) ([]Commit, error) {
 	return ParseLogOutput(out), nil
 }
 
+// Reflog returns up to limit entries of ref's reflog, newest first.
+func (s *CLIService) Reflog(ref string, limit int) ([]ReflogEntry, error) {
+	out, err := s.run("log", "-g", fmt.Sprintf("--max-count=%d", limit),
+		"--date=relative", reflogFormat, ref, "--")
+	if err != nil {
+		return nil, fmt.Errorf("reading reflog of %s: %w", ref, err)
+	}
+	return ParseReflog(ref, out), nil
+}
+
+// Reset moves the current branch (or a detached HEAD) to ref.
+func (s *CLIService) Reset(ref string, mode ResetMode) error {
+	_, err := s.runWrite("reset", "--"+string(mode), ref, "--")
+	return err
+}
+
 // LogGraph returns the commit log with ASCII graph.
 func (s *CLIService) LogGraph(limit int) ([]GraphEntry, error) {
 	// --graph --all can be expensive on repos with many refs.
@@ -307,21 +743,23 @@ func (s *CLIService) LogGraph(limit int) ([]GraphEntry, error) {
 	return ParseGraphOutput(out), nil
 }
 
-// Show returns the commit details and diff for a given hash.
-func (s *CLIService) Show(hash string) (*Commit, string, error) {
-	commits, err := s.Log(1, hash, "-1")
-	if err != nil || len(commits) == 0 {
-		return nil, "", fmt.Errorf("showing commit %s: %w", hash, err)
-	}
-	// --stat is cheaper than --patch for initial display.
-	diff, err := s.run("show", "--format=", "--patch", hash)
+// CommitFiles lists the files hash changed with their line counts. A
+// merge is compared with its first parent.
+func (s *CLIService) CommitFiles(hash string) ([]DiffFileStat, error) {
+	out, err := s.run("show", "--format=", "--numstat", "-z", "--find-renames",
+		"--diff-merges=first-parent", "--no-ext-diff", hash, "--")
 	if err != nil {
-		return &commits[0], "", nil
-	}
-	if len(diff) > maxDiffBytes {
-		diff = diff[:maxDiffBytes] + "\n\n... (diff truncated — exceeds 512 KB) ...\n"
+		return nil, fmt.Errorf("listing files of %s: %w", hash, err)
 	}
-	return &commits[0], diff, nil
+	return ParseNumstat(out), nil
+}
+
+// CommitFile returns the diff of one file changed by hash (see
+// CommitFiles); oldPath, when set, is the file's path before a rename.
+func (s *CLIService) CommitFile(hash, path, oldPath string) (string, error) {
+	args := append([]string{"show", "--format=", "--patch", "--color=never", "--find-renames",
+		"--diff-merges=first-parent", "--no-ext-diff"}, s.contextArgs()...)
+	return s.fileDiff(append(args, hash, "--"), path, oldPath)
 }
 
 // ── Diff ────────────────────────────────────────────────────────────────────
@@ -334,7 +772,7 @@ const maxDiffBytes = 512 * 1024
 
 // Diff returns the diff for a path.
 func (s *CLIService) Diff(staged bool, path string) (string, error) {
-	args := []string{"diff", "--color=never", "--no-ext-diff"}
+	args := append([]string{"diff", "--color=never", "--no-ext-diff"}, s.contextArgs()...)
 	if staged {
 		args = append(args, "--cached")
 	}
@@ -351,9 +789,65 @@ func (s *CLIService) Diff(staged bool, path string) (string, error) {
 	return out, nil
 }
 
+// DiffFiles lists the files with unstaged changes (or, when staged, the
+// staged ones) with their line counts.
+func (s *CLIService) DiffFiles(staged bool) ([]DiffFileStat, error) {
+	args := []string{"diff", "--numstat", "-z", "--find-renames", "--no-ext-diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	out, err := s.run(append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNumstat(out), nil
+}
+
+// DiffFile returns one file's unstaged (or staged) diff; oldPath, when
+// set, is the file's path before a rename.
+func (s *CLIService) DiffFile(staged bool, path, oldPath string) (string, error) {
+	args := append([]string{"diff", "--color=never", "--no-ext-diff", "--find-renames"}, s.contextArgs()...)
+	if staged {
+		args = append(args, "--cached")
+	}
+	return s.fileDiff(append(args, "--"), path, oldPath)
+}
+
+// fileDiff runs args (a diff command ending in "--") for path and
+// oldPath. The size cap applies to the one file, so a large change
+// elsewhere doesn't cut it short.
+func (s *CLIService) fileDiff(args []string, path, oldPath string) (string, error) {
+	args = append(args, path)
+	if oldPath != "" {
+		args = append(args, oldPath)
+	}
+	out, err := s.run(args...)
+	if err != nil {
+		return "", err
+	}
+	if len(out) > maxDiffBytes {
+		return out[:maxDiffBytes] + "\n\n... (diff truncated — exceeds 512 KB) ...\n", nil
+	}
+	return out, nil
+}
+
+// DiffStat returns `git diff --stat` for the working tree (or the index
+// when staged), optionally limited to path.
+func (s *CLIService) DiffStat(staged bool, path string) (string, error) {
+	args := []string{"diff", "--stat", "--color=never", "--no-ext-diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	return s.run(args...)
+}
+
 // DiffRange returns the diff between two refs.
 func (s *CLIService) DiffRange(from, to string) (string, error) {
-	out, err := s.run("diff", "--color=never", "--no-ext-diff", from+".."+to)
+	args := append([]string{"diff", "--color=never", "--no-ext-diff"}, s.contextArgs()...)
+	out, err := s.run(append(args, from+".."+to)...)
 	if err != nil {
 		return "", err
 	}
@@ -363,9 +857,37 @@ func (s *CLIService) DiffRange(from, to string) (string, error) {
 	return out, nil
 }
 
+// DiffRangeFiles lists the files changed in rng ("from..to", or
+// "from...to" for the changes on to since the merge base) with their line
+// counts.
+func (s *CLIService) DiffRangeFiles(rng string) ([]DiffFileStat, error) {
+	out, err := s.run("diff", "--numstat", "-z", "--find-renames", "--no-ext-diff", rng, "--")
+	if err != nil {
+		return nil, err
+	}
+	return ParseNumstat(out), nil
+}
+
+// DiffRangeFile returns the diff of one file in rng; oldPath, when set,
+// is the file's path before a rename.
+func (s *CLIService) DiffRangeFile(rng, path, oldPath string) (string, error) {
+	args := append([]string{"diff", "--color=never", "--no-ext-diff", "--find-renames"}, s.contextArgs()...)
+	return s.fileDiff(append(args, rng, "--"), path, oldPath)
+}
+
+// DiffRangeStat returns `git diff --stat` between two refs, or between
+// from and the working tree when to is empty.
+func (s *CLIService) DiffRangeStat(from, to string) (string, error) {
+	args := []string{"diff", "--stat", "--color=never", "--no-ext-diff", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	return s.run(append(args, "--")...)
+}
+
 // ── Branches ────────────────────────────────────────────────────────────────
 
-const branchFormat = "%(HEAD)%00%(refname:short)%00%(objectname:short)%00%(upstream:short)%00%(upstream:track)%00%(subject)"
+const branchFormat = "%(HEAD)%00%(refname)%00%(objectname:short)%00%(upstream:short)%00%(upstream:track)%00%(subject)"
 
 // Branches returns all branches.
 func (s *CLIService) Branches() ([]Branch, error) {
@@ -374,12 +896,19 @@ func (s *CLIService) Branches() ([]Branch, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ParseBranchOutput(out), nil
+	// Leave out remotes' HEADs (origin/HEAD), which just name a branch.
+	return slices.DeleteFunc(ParseBranchOutput(out), func(b Branch) bool {
+		return b.IsRemote && strings.HasSuffix(b.Name, "/HEAD")
+	}), nil
 }
 
-// CreateBranch creates a new branch.
-func (s *CLIService) CreateBranch(name string) error {
-	_, err := s.runWrite("branch", name)
+// CreateBranch creates a new branch at start ("" means HEAD).
+func (s *CLIService) CreateBranch(name, start string) error {
+	args := []string{"branch", name}
+	if start != "" {
+		args = append(args, start)
+	}
+	_, err := s.runWrite(args...)
 	return err
 }
 
@@ -405,12 +934,82 @@ func (s *CLIService) MergeBranch(name string) error {
 	return err
 }
 
+// CheckoutDetached checks out rev with a detached HEAD.
+func (s *CLIService) CheckoutDetached(rev string) error {
+	_, err := s.runWrite("switch", "--detach", rev)
+	return err
+}
+
+// CheckoutTracking creates branch name from remoteBranch (e.g.
+// "origin/feature"), set to track it, and switches to it.
+func (s *CLIService) CheckoutTracking(name, remoteBranch string) error {
+	_, err := s.runWrite("switch", "-c", name, "--track", remoteBranch)
+	return err
+}
+
+// SetUpstream makes branch track upstream (e.g. "origin/main").
+func (s *CLIService) SetUpstream(branch, upstream string) error {
+	_, err := s.runWrite("branch", "--set-upstream-to="+upstream, branch)
+	return err
+}
+
+// UnsetUpstream stops branch tracking its upstream.
+func (s *CLIService) UnsetUpstream(branch string) error {
+	_, err := s.runWrite("branch", "--unset-upstream", branch)
+	return err
+}
+
 // RenameBranch renames a branch.
 func (s *CLIService) RenameBranch(oldName, newName string) error {
 	_, err := s.runWrite("branch", "-m", oldName, newName)
 	return err
 }
 
+// ── Tags ────────────────────────────────────────────────────────────────────
+
+// Tags returns all tags, newest first.
+func (s *CLIService) Tags() ([]Tag, error) {
+	out, err := s.run("for-each-ref", "--sort=-creatordate", tagFormat, "refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	return ParseTags(out), nil
+}
+
+// CreateTag tags ref ("" means HEAD). Signed tags are always annotated;
+// an annotated tag takes message as-is (it may be empty).
+func (s *CLIService) CreateTag(name, ref, message string, annotated, signed bool) error {
+	args := []string{"tag"}
+	switch {
+	case signed:
+		args = append(args, "-s", "-m", message)
+	case annotated:
+		args = append(args, "-a", "-m", message)
+	}
+	args = append(args, "--", name)
+	if ref != "" {
+		args = append(args, ref)
+	}
+	_, err := s.runWrite(args...)
+	return err
+}
+
+// DeleteTag deletes a local tag.
+func (s *CLIService) DeleteTag(name string) error {
+	_, err := s.runWrite("tag", "-d", name)
+	return err
+}
+
+// PushTag pushes a single tag to remote.
+func (s *CLIService) PushTag(ctx context.Context, remote, name string, progress ProgressFunc) error {
+	return s.runNetwork(ctx, progress, "push", remote, "refs/tags/"+name)
+}
+
+// DeleteRemoteTag deletes a tag from remote.
+func (s *CLIService) DeleteRemoteTag(ctx context.Context, remote, name string, progress ProgressFunc) error {
+	return s.runNetwork(ctx, progress, "push", remote, "--delete", "refs/tags/"+name)
+}
+
 // ── Stash ───────────────────────────────────────────────────────────────────
 
 // StashList returns stash entries.
@@ -452,7 +1051,13 @@ func (s *CLIService) StashDrop(index int) error {
 
 // StashShow shows the diff for a stash entry.
 func (s *CLIService) StashShow(index int) (string, error) {
-	return s.run("stash", "show", "-p", fmt.Sprintf("stash@{%d}", index))
+	args := append([]string{"stash", "show", "-p"}, s.contextArgs()...)
+	return s.run(append(args, fmt.Sprintf("stash@{%d}", index))...)
+}
+
+// StashStat returns the diffstat of a stash entry.
+func (s *CLIService) StashStat(index int) (string, error) {
+	return s.run("stash", "show", "--stat", "--color=never", fmt.Sprintf("stash@{%d}", index))
 }
 
 // ── Remotes ─────────────────────────────────────────────────────────────────
@@ -466,28 +1071,134 @@ func (s *CLIService) Remotes() ([]Remote, error) {
 	return ParseRemoteOutput(out), nil
 }
 
-// Fetch fetches from the given remote.
-func (s *CLIService) Fetch(remote string) error {
-	_, err := s.runNetwork("fetch", remote)
+// RemoteBranches returns remote's remote-tracking branches, newest first,
+// leaving out its HEAD.
+func (s *CLIService) RemoteBranches(remote string) ([]Branch, error) {
+	out, err := s.run("for-each-ref", "--format="+branchFormat, "--sort=-committerdate", "refs/remotes/"+remote+"/")
+	if err != nil {
+		return nil, err
+	}
+	var branches []Branch
+	for _, b := range ParseBranchOutput(out) {
+		if b.Name == remote || b.Name == remote+"/HEAD" {
+			continue
+		}
+		b.IsRemote = true
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
+
+// AddRemote adds a remote called name at url.
+func (s *CLIService) AddRemote(name, url string) error {
+	_, err := s.runWrite("remote", "add", name, url)
 	return err
 }
 
-// Pull pulls from the given remote and branch.
-func (s *CLIService) Pull(remote, branch string) error {
-	_, err := s.runNetwork("pull", remote, branch)
+// RemoveRemote removes a remote, with its remote-tracking branches and
+// configuration.
+func (s *CLIService) RemoveRemote(name string) error {
+	_, err := s.runWrite("remote", "remove", name)
 	return err
 }
 
-// Push pushes to the given remote and branch.
-func (s *CLIService) Push(remote, branch string, force bool) error {
-	args := []string{"push", remote, branch}
-	if force {
-		args = append(args, "--force-with-lease")
+// RenameRemote renames a remote, moving its remote-tracking branches and
+// updating the branches that track them.
+func (s *CLIService) RenameRemote(oldName, newName string) error {
+	_, err := s.runWrite("remote", "rename", oldName, newName)
+	return err
+}
+
+// SetRemoteURL sets a remote's fetch URL, or its push URL when push is
+// set.
+func (s *CLIService) SetRemoteURL(name, url string, push bool) error {
+	args := []string{"remote", "set-url"}
+	if push {
+		args = append(args, "--push")
 	}
-	_, err := s.runNetwork(args...)
+	_, err := s.runWrite(append(args, name, url)...)
 	return err
 }
 
+// PruneRemote deletes remote-tracking branches whose branch no longer
+// exists on the remote.
+func (s *CLIService) PruneRemote(ctx context.Context, name string) error {
+	return s.runNetwork(ctx, nil, "remote", "prune", name)
+}
+
+// Fetch fetches from the given remote.
+func (s *CLIService) Fetch(ctx context.Context, remote string, progress ProgressFunc) error {
+	return s.runNetwork(ctx, progress, "fetch", remote)
+}
+
+// Pull pulls branch from remote, integrating it as opts.Mode says.
+func (s *CLIService) Pull(ctx context.Context, remote, branch string, opts PullOptions, progress ProgressFunc) error {
+	args := []string{"pull"}
+	switch opts.Mode {
+	case PullRebase:
+		args = append(args, "--rebase")
+	case PullFFOnly:
+		args = append(args, "--ff-only")
+	default:
+		// --ff overrides pull.ff=only, which would refuse the merge.
+		args = append(args, "--no-rebase", "--ff")
+	}
+	if opts.Autostash {
+		args = append(args, "--autostash")
+	}
+	return s.runNetwork(ctx, progress, append(args, remote, branch)...)
+}
+
+// PullDefault returns the pull mode git's configuration asks for: a
+// rebase if pull.rebase is set, else pull.ff=only, else a merge.
+func (s *CLIService) PullDefault() PullMode {
+	get := func(args ...string) (string, error) {
+		out, err := s.run(append([]string{"config", "--get"}, args...)...)
+		return strings.TrimSpace(out), err
+	}
+	// pull.rebase is a boolean, or a rebase style --type=bool rejects.
+	if v, err := get("--type=bool", "pull.rebase"); err == nil {
+		if v == "true" {
+			return PullRebase
+		}
+	} else if v, _ := get("pull.rebase"); slices.Contains([]string{"merges", "m", "interactive", "i"}, v) {
+		return PullRebase
+	}
+	if v, _ := get("pull.ff"); v == "only" {
+		return PullFFOnly
+	}
+	return PullMerge
+}
+
+// Push pushes a branch as opts describe.
+func (s *CLIService) Push(ctx context.Context, opts PushOptions, progress ProgressFunc) error {
+	target := opts.Target
+	if target == "" {
+		target = opts.Branch
+	}
+	args := []string{"push"}
+	if opts.SetUpstream {
+		args = append(args, "--set-upstream")
+	}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease=refs/heads/"+target+leaseExpect(opts.Expect))
+	}
+	if opts.Tags {
+		args = append(args, "--tags")
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	return s.runNetwork(ctx, progress, append(args, opts.Remote, "refs/heads/"+opts.Branch+":refs/heads/"+target)...)
+}
+
+func leaseExpect(hash string) string {
+	if hash == "" {
+		return ""
+	}
+	return ":" + hash
+}
+
 // ── Worktrees ───────────────────────────────────────────────────────────────
 
 // WorktreeList returns all worktrees.
@@ -517,17 +1228,155 @@ func (s *CLIService) WorktreeRemove(path string) error {
 
 // ── Rebase ──────────────────────────────────────────────────────────────────
 
-// RebaseInteractive starts an interactive rebase.
-func (s *CLIService) RebaseInteractive(onto string) error {
-	_, err := s.runWrite("rebase", "-i", onto)
+// RebaseInteractive starts an interactive rebase onto `onto` using the
+// given todo list. zgv acts as git's sequence editor so the list prepared
+// in the TUI is used verbatim; no editor is ever opened.
+func (s *CLIService) RebaseInteractive(onto string, todo []RebaseTodoItem) error {
+	return s.rebaseTodo(todo, onto)
+}
+
+// rebaseTodo runs "git rebase -i args..." with todo as the todo list.
+func (s *CLIService) rebaseTodo(todo []RebaseTodoItem, args ...string) error {
+	if err := ValidateRebaseTodo(todo); err != nil {
+		return err
+	}
+	removeRewordMessages(s.gitDir)
+	content, err := FormatRebaseTodo(todo, s.gitDir)
+	if err != nil {
+		return err
+	}
+	todoPath := filepath.Join(s.gitDir, rebaseTodoFile)
+	if err := os.WriteFile(todoPath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("writing rebase todo: %w", err)
+	}
+	env, err := sequenceEditorEnv(todoPath)
+	if err != nil {
+		return err
+	}
+	_, err = s.runRebase(env, append([]string{"rebase", "-i"}, args...)...)
+	s.finishRebase()
 	return err
 }
 
-// RebaseContinue continues a rebase in progress.
-func (s *CLIService) RebaseContinue() error { _, err := s.runWrite("rebase", "--continue"); return err }
+// RebaseAutosquash folds the fixup!/squash! commits after onto into the
+// commits they name. Local changes are stashed around the rebase, and
+// squash messages are combined without opening an editor.
+func (s *CLIService) RebaseAutosquash(onto string) error {
+	env := []string{"GIT_SEQUENCE_EDITOR=:", "GIT_EDITOR=:"}
+	_, err := s.runRebase(env, "rebase", "-i", "--autosquash", "--autostash", onto)
+	s.finishRebase()
+	return err
+}
+
+// RebaseContinue continues a rebase in progress, keeping the prepared
+// commit message (GIT_EDITOR=:) instead of opening an editor.
+func (s *CLIService) RebaseContinue() error {
+	_, err := s.runRebase([]string{"GIT_EDITOR=:"}, "rebase", "--continue")
+	s.finishRebase()
+	return err
+}
+
+// RebaseSkip skips the commit the rebase is stopped on.
+func (s *CLIService) RebaseSkip() error {
+	_, err := s.runRebase([]string{"GIT_EDITOR=:"}, "rebase", "--skip")
+	s.finishRebase()
+	return err
+}
+
+// RebaseProgress returns the done/remaining steps of the rebase in
+// progress, or nil when no rebase is running.
+func (s *CLIService) RebaseProgress() (*RebaseProgress, error) {
+	return readRebaseProgress(s.gitDir), nil
+}
 
 // RebaseAbort aborts a rebase in progress.
-func (s *CLIService) RebaseAbort() error { _, err := s.runWrite("rebase", "--abort"); return err }
+func (s *CLIService) RebaseAbort() error {
+	_, err := s.runWrite("rebase", "--abort")
+	s.finishRebase()
+	return err
+}
+
+// finishRebase removes zgv's reword message files once the rebase is over.
+func (s *CLIService) finishRebase() {
+	if !s.IsRebasing() {
+		removeRewordMessages(s.gitDir)
+	}
+}
+
+// ── Cherry-pick & revert ────────────────────────────────────────────────────
+
+// CherryPick applies hashes onto HEAD in the order given.
+func (s *CLIService) CherryPick(hashes []string, opts PickOptions) error {
+	args := []string{"cherry-pick"}
+	if opts.RecordOrigin {
+		args = append(args, "-x")
+	}
+	args = append(append(args, pickArgs(opts)...), hashes...)
+	_, err := s.runWriteEnv([]string{"GIT_EDITOR=:"}, args...)
+	return err
+}
+
+// Revert reverts hashes in the order given, so pass the newest first.
+func (s *CLIService) Revert(hashes []string, opts PickOptions) error {
+	args := append(append([]string{"revert", "--no-edit"}, pickArgs(opts)...), hashes...)
+	_, err := s.runWriteEnv([]string{"GIT_EDITOR=:"}, args...)
+	return err
+}
+
+// pickArgs renders the options cherry-pick and revert share.
+func pickArgs(opts PickOptions) []string {
+	var args []string
+	if opts.NoCommit {
+		args = append(args, "--no-commit")
+	}
+	if opts.Mainline > 0 {
+		args = append(args, "-m", fmt.Sprint(opts.Mainline))
+	}
+	return args
+}
+
+// sequencerCmd is the command whose sequence is in progress.
+func (s *CLIService) sequencerCmd() (string, error) {
+	switch {
+	case s.IsCherryPicking():
+		return "cherry-pick", nil
+	case s.IsReverting():
+		return "revert", nil
+	}
+	return "", errors.New("no cherry-pick or revert in progress")
+}
+
+// SequencerContinue commits the resolved commit of the cherry-pick or
+// revert in progress and applies the rest.
+func (s *CLIService) SequencerContinue() error {
+	cmd, err := s.sequencerCmd()
+	if err != nil {
+		return err
+	}
+	_, err = s.runWriteEnv([]string{"GIT_EDITOR=:"}, cmd, "--continue")
+	return err
+}
+
+// SequencerSkip drops the commit the cherry-pick or revert stopped on.
+func (s *CLIService) SequencerSkip() error {
+	cmd, err := s.sequencerCmd()
+	if err != nil {
+		return err
+	}
+	_, err = s.runWriteEnv([]string{"GIT_EDITOR=:"}, cmd, "--skip")
+	return err
+}
+
+// SequencerAbort cancels the cherry-pick or revert in progress and returns
+// to where it started.
+func (s *CLIService) SequencerAbort() error {
+	cmd, err := s.sequencerCmd()
+	if err != nil {
+		return err
+	}
+	_, err = s.runWrite(cmd, "--abort")
+	return err
+}
 
 // ── Bisect ──────────────────────────────────────────────────────────────────
 
@@ -570,3 +1419,90 @@ func (s *CLIService) MarkResolved(path string) error {
 	_, err := s.runWrite("add", path)
 	return err
 }
+
+// binarySniffLen matches the prefix git inspects for NUL bytes when
+// deciding whether a file is binary.
+const binarySniffLen = 8000
+
+// ConflictDetails returns the unmerged paths with their conflict kind and
+// whether the working copy is binary.
+func (s *CLIService) ConflictDetails() ([]ConflictFile, error) {
+	out, err := s.run("ls-files", "-u", "-z")
+	if err != nil {
+		return nil, err
+	}
+	files := ParseUnmerged(out)
+	for i := range files {
+		files[i].Binary = s.isBinaryFile(files[i].Path)
+	}
+	return files, nil
+}
+
+func (s *CLIService) isBinaryFile(path string) bool {
+	f, err := os.Open(filepath.Join(s.root, path))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	buf := make([]byte, binarySniffLen)
+	n, _ := f.Read(buf)
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+// ConflictContent returns the working-tree content of a conflicted file,
+// markers included.
+func (s *CLIService) ConflictContent(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(s.root, path))
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// ResolveConflict writes the resolved content over the working copy and
+// marks the path resolved.
+func (s *CLIService) ResolveConflict(path, content string) error {
+	full := filepath.Join(s.root, path)
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(full); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(full, []byte(content), mode); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return s.MarkResolved(path)
+}
+
+// ResolveWithSide resolves the whole file by taking one side, like
+// `git checkout --ours/--theirs` followed by `git add`. When that side
+// deleted the file, the deletion is taken instead (`git rm`).
+func (s *CLIService) ResolveWithSide(path string, side ConflictSide) error {
+	out, err := s.run("ls-files", "-u", "-z", "--", path)
+	if err != nil {
+		return err
+	}
+	files := ParseUnmerged(out)
+	if len(files) == 0 {
+		return fmt.Errorf("%s is not conflicted", path)
+	}
+	if !files[0].HasSide(side) {
+		_, err := s.runWrite("rm", "--quiet", "--", path)
+		return err
+	}
+	flag := "--ours"
+	if side == ConflictTheirs {
+		flag = "--theirs"
+	}
+	if _, err := s.runWrite("checkout", flag, "--", path); err != nil {
+		return err
+	}
+	return s.MarkResolved(path)
+}
+
+// ── Undo journal ────────────────────────────────────────────────────────────
+
+// Undo always fails: CLIService keeps no journal (see JournalService).
+func (s *CLIService) Undo() (string, error) { return "", ErrNothingToUndo }
+
+// Redo always fails: CLIService keeps no journal (see JournalService).
+func (s *CLIService) Redo() (string, error) { return "", ErrNothingToRedo }
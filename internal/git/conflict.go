@@ -0,0 +1,143 @@
+package git
+
+import "strings"
+
+// Conflict marker prefixes (git's default conflict-marker-size of 7).
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSplit  = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// ParseConflicts splits a conflicted file into plain and conflict
+// segments. Both merge (two-way) and diff3/zdiff3 (with a base section)
+// marker styles are understood. An unterminated block is kept as plain
+// text so nothing is lost.
+func ParseConflicts(content string) *ConflictDoc {
+	lines := strings.SplitAfter(content, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+
+	doc := &ConflictDoc{}
+	var text []string
+	flushText := func() {
+		if len(text) > 0 {
+			doc.Segments = append(doc.Segments, ConflictSegment{Text: text})
+			text = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		label, ok := markerLabel(lines[i], markerOurs)
+		if !ok {
+			text = append(text, lines[i])
+			continue
+		}
+		block, next := parseConflictBlock(lines, i, label)
+		if block == nil {
+			text = append(text, lines[i])
+			continue
+		}
+		flushText()
+		doc.Segments = append(doc.Segments, ConflictSegment{Conflict: block})
+		doc.Blocks = append(doc.Blocks, block)
+		i = next
+	}
+	flushText()
+	return doc
+}
+
+// parseConflictBlock parses the block opened at lines[start]. It returns
+// the block and the index of its closing marker, or nil if the block is
+// never closed.
+func parseConflictBlock(lines []string, start int, oursLabel string) (*ConflictBlock, int) {
+	b := &ConflictBlock{OursLabel: oursLabel, Markers: []string{lines[start]}}
+	section := &b.Ours
+	for i := start + 1; i < len(lines); i++ {
+		l := lines[i]
+		if label, ok := markerLabel(l, markerBase); ok && section == &b.Ours {
+			b.HasBase = true
+			b.BaseLabel = label
+			b.Markers = append(b.Markers, l)
+			section = &b.Base
+			continue
+		}
+		if _, ok := markerLabel(l, markerSplit); ok && section != &b.Theirs {
+			b.Markers = append(b.Markers, l)
+			section = &b.Theirs
+			continue
+		}
+		if label, ok := markerLabel(l, markerTheirs); ok && section == &b.Theirs {
+			b.TheirsLabel = label
+			b.Markers = append(b.Markers, l)
+			return b, i
+		}
+		*section = append(*section, l)
+	}
+	return nil, start
+}
+
+// markerLabel reports whether line is the given conflict marker and
+// returns the label that follows it (e.g. "HEAD").
+func markerLabel(line, marker string) (string, bool) {
+	if !strings.HasPrefix(line, marker) {
+		return "", false
+	}
+	rest := strings.TrimRight(line[len(marker):], "\r\n")
+	if rest != "" && rest[0] != ' ' {
+		return "", false // longer marker run or unrelated text
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// Lines returns the content a choice resolves the block to. Unresolved
+// blocks keep their markers so the file still reads as conflicted.
+func (b *ConflictBlock) Lines(choice ConflictChoice) []string {
+	switch choice {
+	case ChoiceOurs:
+		return b.Ours
+	case ChoiceTheirs:
+		return b.Theirs
+	case ChoiceBoth:
+		return append(append([]string(nil), b.Ours...), b.Theirs...)
+	case ChoiceBase:
+		return b.Base
+	}
+	out := []string{b.Markers[0]}
+	out = append(out, b.Ours...)
+	m := 1
+	if b.HasBase {
+		out = append(out, b.Markers[m])
+		out = append(out, b.Base...)
+		m++
+	}
+	out = append(out, b.Markers[m])
+	out = append(out, b.Theirs...)
+	return append(out, b.Markers[m+1])
+}
+
+// Resolve renders the document with choices[i] applied to Blocks[i].
+// Missing choices count as unresolved.
+func (d *ConflictDoc) Resolve(choices []ConflictChoice) string {
+	var sb strings.Builder
+	bi := 0
+	for _, seg := range d.Segments {
+		if seg.Conflict == nil {
+			for _, l := range seg.Text {
+				sb.WriteString(l)
+			}
+			continue
+		}
+		choice := ChoiceUnresolved
+		if bi < len(choices) {
+			choice = choices[bi]
+		}
+		bi++
+		for _, l := range seg.Conflict.Lines(choice) {
+			sb.WriteString(l)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		text    []string // each plain segment's lines, joined
+		blocks  []ConflictBlock
+	}{
+		{
+			name:    "merge style",
+			content: "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> topic\nz\n",
+			text:    []string{"a\n", "z\n"},
+			blocks: []ConflictBlock{{
+				Ours: []string{"ours\n"}, Theirs: []string{"theirs\n"},
+				Markers:   []string{"<<<<<<< HEAD\n", "=======\n", ">>>>>>> topic\n"},
+				OursLabel: "HEAD", TheirsLabel: "topic",
+			}},
+		},
+		{
+			name:    "diff3 style",
+			content: "<<<<<<< HEAD\nours\n||||||| merged common ancestors\nbase\n=======\n>>>>>>> topic\n",
+			blocks: []ConflictBlock{{
+				Ours: []string{"ours\n"}, Base: []string{"base\n"}, HasBase: true,
+				Markers:   []string{"<<<<<<< HEAD\n", "||||||| merged common ancestors\n", "=======\n", ">>>>>>> topic\n"},
+				OursLabel: "HEAD", BaseLabel: "merged common ancestors", TheirsLabel: "topic",
+			}},
+		},
+		{
+			name:    "CRLF and no labels",
+			content: "<<<<<<<\r\nours\r\n=======\r\ntheirs\r\n>>>>>>>\r\n",
+			blocks: []ConflictBlock{{
+				Ours: []string{"ours\r\n"}, Theirs: []string{"theirs\r\n"},
+				Markers: []string{"<<<<<<<\r\n", "=======\r\n", ">>>>>>>\r\n"},
+			}},
+		},
+		{
+			name:    "separator inside theirs is content",
+			content: "<<<<<<< a\n=======\n=======\n>>>>>>> b",
+			blocks: []ConflictBlock{{
+				Theirs:    []string{"=======\n"},
+				Markers:   []string{"<<<<<<< a\n", "=======\n", ">>>>>>> b"},
+				OursLabel: "a", TheirsLabel: "b",
+			}},
+		},
+		{
+			name:    "unterminated block stays text",
+			content: "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n",
+			text:    []string{"a\n<<<<<<< HEAD\nours\n=======\ntheirs\n"},
+		},
+		{
+			name:    "longer marker runs are text",
+			content: "<<<<<<<< x\n========\n>>>>>>>> y\n",
+			text:    []string{"<<<<<<<< x\n========\n>>>>>>>> y\n"},
+		},
+		{
+			name:    "two blocks",
+			content: "<<<<<<< a\n1\n=======\n2\n>>>>>>> b\nmid\n<<<<<<< a\n3\n=======\n4\n>>>>>>> b\n",
+			text:    []string{"mid\n"},
+			blocks: []ConflictBlock{
+				{Ours: []string{"1\n"}, Theirs: []string{"2\n"}, Markers: []string{"<<<<<<< a\n", "=======\n", ">>>>>>> b\n"}, OursLabel: "a", TheirsLabel: "b"},
+				{Ours: []string{"3\n"}, Theirs: []string{"4\n"}, Markers: []string{"<<<<<<< a\n", "=======\n", ">>>>>>> b\n"}, OursLabel: "a", TheirsLabel: "b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseConflicts(tt.content)
+			var text []string
+			for _, seg := range doc.Segments {
+				if seg.Conflict == nil {
+					text = append(text, strings.Join(seg.Text, ""))
+				}
+			}
+			if !reflect.DeepEqual(text, tt.text) {
+				t.Errorf("text segments = %q, want %q", text, tt.text)
+			}
+			var blocks []ConflictBlock
+			for _, b := range doc.Blocks {
+				blocks = append(blocks, *b)
+			}
+			if !reflect.DeepEqual(blocks, tt.blocks) {
+				t.Errorf("blocks = %+v, want %+v", blocks, tt.blocks)
+			}
+			// Leaving every block unresolved reproduces the file.
+			if got := doc.Resolve(nil); got != tt.content {
+				t.Errorf("Resolve(nil) = %q, want the input", got)
+			}
+		})
+	}
+}
+
+func TestConflictResolve(t *testing.T) {
+	doc := ParseConflicts("a\n<<<<<<< HEAD\no\n||||||| base\nb\n=======\nt\n>>>>>>> x\nz\n")
+	tests := []struct {
+		choice ConflictChoice
+		want   string
+	}{
+		{ChoiceOurs, "a\no\nz\n"},
+		{ChoiceTheirs, "a\nt\nz\n"},
+		{ChoiceBoth, "a\no\nt\nz\n"},
+		{ChoiceBase, "a\nb\nz\n"},
+		{ChoiceUnresolved, "a\n<<<<<<< HEAD\no\n||||||| base\nb\n=======\nt\n>>>>>>> x\nz\n"},
+	}
+	for _, tt := range tests {
+		if got := doc.Resolve([]ConflictChoice{tt.choice}); got != tt.want {
+			t.Errorf("Resolve(%d) = %q, want %q", tt.choice, got, tt.want)
+		}
+	}
+}
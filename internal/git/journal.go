@@ -0,0 +1,781 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNothingToUndo and ErrNothingToRedo are returned when the journal is
+// empty in that direction.
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// JournalService wraps a CLIService with an undo journal. Before every
+// write it records the repository state the write may change — the HEAD
+// position, branch and tag tips, the stash list, the index tree and (for
+// writes that touch files) a stash-style snapshot of the working tree — so
+// Undo can put it back. Redo replays the original operation.
+//
+// Snapshots are plain git objects (`write-tree`, `stash create`), so
+// discarded changes stay recoverable for as long as git keeps unreachable
+// objects around.
+//
+// Reads, network operations, remote and upstream configuration, worktrees,
+// bisect and conflict resolution are passed through unjournaled.
+type JournalService struct {
+	*CLIService
+
+	mu    sync.Mutex
+	limit int
+	undo  []journalEntry
+	redo  []journalEntry
+	gen   int // bumped by every journaled write, undo and redo
+}
+
+// Compile-time check.
+var _ Service = (*JournalService)(nil)
+
+// NewJournalService wraps inner with a journal holding up to limit
+// entries; the oldest entries are forgotten first.
+func NewJournalService(inner *CLIService, limit int) *JournalService {
+	return &JournalService{CLIService: inner, limit: limit}
+}
+
+// journalScope says which parts of the repository a write can change.
+type journalScope int
+
+const (
+	scopeIndex journalScope = iota // the index only (staging)
+	scopeRefs                      // HEAD, branches, tags and stashes
+	scopeWork                      // refs, the index and tracked files
+)
+
+type journalEntry struct {
+	desc   string
+	scope  journalScope
+	before snapshot
+	after  snapshot
+	do     func() error // re-runs the operation for Redo; nil if it can't be
+}
+
+// snapshot is the repository state relevant to a journal entry. Trees are
+// always resolved; while files are conflicted (which neither write-tree
+// nor stash create can represent) they fall back to HEAD's tree.
+type snapshot struct {
+	head       string            // branch HEAD points at, "" when detached
+	commit     string            // HEAD commit
+	index      string            // index tree
+	work       string            // tree of the tracked files on disk
+	refs       map[string]string // branch and tag tips by full ref name
+	stashes    []stashRef        // newest first
+	op         string            // merge, rebase, cherry-pick or revert in progress
+	conflicted bool
+}
+
+type stashRef struct{ hash, message string }
+
+// literalEnv stops git from reading glob magic in file paths.
+var literalEnv = []string{"GIT_LITERAL_PATHSPECS=1"}
+
+// ── Journaled writes ────────────────────────────────────────────────────────
+
+// Stage records the index before staging.
+func (j *JournalService) Stage(paths ...string) error {
+	return j.record(describePaths("stage", paths), scopeIndex, func() error { return j.CLIService.Stage(paths...) })
+}
+
+// StageAll records the index before staging everything.
+func (j *JournalService) StageAll() error {
+	return j.record("stage all", scopeIndex, j.CLIService.StageAll)
+}
+
+// Unstage records the index before unstaging.
+func (j *JournalService) Unstage(paths ...string) error {
+	return j.record(describePaths("unstage", paths), scopeIndex, func() error { return j.CLIService.Unstage(paths...) })
+}
+
+// UnstageAll records the index before unstaging everything.
+func (j *JournalService) UnstageAll() error {
+	return j.record("unstage all", scopeIndex, j.CLIService.UnstageAll)
+}
+
+// Discard records the discarded content before checking the files out.
+func (j *JournalService) Discard(paths ...string) error {
+	return j.record(describePaths("discard", paths), scopeWork, func() error { return j.CLIService.Discard(paths...) })
+}
+
+// StagePatch records the index before applying the patch to it.
+func (j *JournalService) StagePatch(patch string) error {
+	return j.record("stage lines", scopeIndex, func() error { return j.CLIService.StagePatch(patch) })
+}
+
+// UnstagePatch records the index before reverse-applying the patch.
+func (j *JournalService) UnstagePatch(patch string) error {
+	return j.record("unstage lines", scopeIndex, func() error { return j.CLIService.UnstagePatch(patch) })
+}
+
+// DiscardPatch records the discarded content before reverse-applying the
+// patch to the working tree.
+func (j *JournalService) DiscardPatch(patch string) error {
+	return j.record("discard lines", scopeWork, func() error { return j.CLIService.DiscardPatch(patch) })
+}
+
+// Commit records HEAD before committing. The journal isn't locked while
+// hooks and signing run (see recordSlow).
+func (j *JournalService) Commit(message string, opts CommitOptions) error {
+	do := func() error { return j.CLIService.Commit(message, opts) }
+	return j.recordSlow("commit", scopeRefs, do, do)
+}
+
+// CommitAmend records HEAD before amending, without locking the journal
+// while hooks and signing run.
+func (j *JournalService) CommitAmend(message string, opts CommitOptions) error {
+	do := func() error { return j.CLIService.CommitAmend(message, opts) }
+	return j.recordSlow("amend", scopeRefs, do, do)
+}
+
+// CommitFixup records HEAD before committing.
+func (j *JournalService) CommitFixup(target string, squash bool, message string) error {
+	desc := "fixup! " + shortHash(target)
+	if squash {
+		desc = "squash! " + shortHash(target)
+	}
+	return j.record(desc, scopeRefs, func() error { return j.CLIService.CommitFixup(target, squash, message) })
+}
+
+// Reword records HEAD and the working tree (autostashed when rewording an
+// older commit) before rewriting the message.
+func (j *JournalService) Reword(hash, message string) error {
+	return j.record("reword "+shortHash(hash), scopeWork, func() error { return j.CLIService.Reword(hash, message) })
+}
+
+// Reset records HEAD, the index and the files before resetting.
+func (j *JournalService) Reset(ref string, mode ResetMode) error {
+	return j.record(fmt.Sprintf("reset --%s %s", mode, abbrev(ref)), scopeWork, func() error { return j.CLIService.Reset(ref, mode) })
+}
+
+// CreateBranch records the branch list before creating name.
+func (j *JournalService) CreateBranch(name, start string) error {
+	return j.record("create branch "+name, scopeRefs, func() error { return j.CLIService.CreateBranch(name, start) })
+}
+
+// SwitchBranch records HEAD and the working tree before switching.
+func (j *JournalService) SwitchBranch(name string) error {
+	return j.record("switch to "+name, scopeWork, func() error { return j.CLIService.SwitchBranch(name) })
+}
+
+// CheckoutDetached records HEAD and the working tree before detaching.
+func (j *JournalService) CheckoutDetached(rev string) error {
+	return j.record("checkout "+abbrev(rev), scopeWork, func() error { return j.CLIService.CheckoutDetached(rev) })
+}
+
+// CheckoutTracking records HEAD and the working tree before switching to
+// the new branch.
+func (j *JournalService) CheckoutTracking(name, remoteBranch string) error {
+	return j.record("checkout "+name+" tracking "+remoteBranch, scopeWork, func() error {
+		return j.CLIService.CheckoutTracking(name, remoteBranch)
+	})
+}
+
+// DeleteBranch records the branch tip before deleting it.
+func (j *JournalService) DeleteBranch(name string, force bool) error {
+	return j.record("delete branch "+name, scopeRefs, func() error { return j.CLIService.DeleteBranch(name, force) })
+}
+
+// MergeBranch records HEAD and the working tree before merging.
+func (j *JournalService) MergeBranch(name string) error {
+	return j.record("merge "+name, scopeWork, func() error { return j.CLIService.MergeBranch(name) })
+}
+
+// RenameBranch records the branch list before renaming.
+func (j *JournalService) RenameBranch(oldName, newName string) error {
+	return j.record("rename "+oldName+" to "+newName, scopeRefs, func() error { return j.CLIService.RenameBranch(oldName, newName) })
+}
+
+// CreateTag records the tag list before creating name.
+func (j *JournalService) CreateTag(name, ref, message string, annotated, signed bool) error {
+	return j.record("create tag "+name, scopeRefs, func() error {
+		return j.CLIService.CreateTag(name, ref, message, annotated, signed)
+	})
+}
+
+// DeleteTag records the tag (for an annotated tag, its tag object) before
+// deleting it.
+func (j *JournalService) DeleteTag(name string) error {
+	return j.record("delete tag "+name, scopeRefs, func() error { return j.CLIService.DeleteTag(name) })
+}
+
+// StashSave records the working tree before stashing it.
+func (j *JournalService) StashSave(message string) error {
+	return j.record("stash", scopeWork, func() error { return j.CLIService.StashSave(message) })
+}
+
+// StashPop records the working tree and stash list before popping.
+func (j *JournalService) StashPop(index int) error {
+	return j.record(fmt.Sprintf("pop stash@{%d}", index), scopeWork, func() error { return j.CLIService.StashPop(index) })
+}
+
+// StashApply records the working tree before applying.
+func (j *JournalService) StashApply(index int) error {
+	return j.record(fmt.Sprintf("apply stash@{%d}", index), scopeWork, func() error { return j.CLIService.StashApply(index) })
+}
+
+// StashDrop records the stash list before dropping the entry.
+func (j *JournalService) StashDrop(index int) error {
+	return j.record(fmt.Sprintf("drop stash@{%d}", index), scopeRefs, func() error { return j.CLIService.StashDrop(index) })
+}
+
+// Pull records HEAD and the working tree before pulling. The journal
+// isn't locked while git talks to the remote, and the pull can be undone
+// but not redone (see recordSlow).
+func (j *JournalService) Pull(ctx context.Context, remote, branch string, opts PullOptions, progress ProgressFunc) error {
+	return j.recordSlow("pull "+branch+" from "+remote, scopeWork, func() error {
+		return j.CLIService.Pull(ctx, remote, branch, opts, progress)
+	}, nil)
+}
+
+// RebaseInteractive records HEAD and the working tree before rebasing.
+func (j *JournalService) RebaseInteractive(onto string, todo []RebaseTodoItem) error {
+	return j.record("rebase onto "+onto, scopeWork, func() error { return j.CLIService.RebaseInteractive(onto, todo) })
+}
+
+// RebaseAutosquash records HEAD and the working tree before rebasing.
+func (j *JournalService) RebaseAutosquash(onto string) error {
+	return j.record("autosquash onto "+shortHash(onto), scopeWork, func() error { return j.CLIService.RebaseAutosquash(onto) })
+}
+
+// RebaseContinue records the rebase's position before continuing.
+func (j *JournalService) RebaseContinue() error {
+	return j.record("rebase continue", scopeWork, j.CLIService.RebaseContinue)
+}
+
+// RebaseSkip records the rebase's position before skipping.
+func (j *JournalService) RebaseSkip() error {
+	return j.record("rebase skip", scopeWork, j.CLIService.RebaseSkip)
+}
+
+// RebaseAbort records the rebase's position before aborting, so Undo can
+// bring back the commits rebased so far.
+func (j *JournalService) RebaseAbort() error {
+	return j.record("rebase abort", scopeWork, j.CLIService.RebaseAbort)
+}
+
+// CherryPick records HEAD and the working tree before applying commits.
+func (j *JournalService) CherryPick(hashes []string, opts PickOptions) error {
+	return j.record("cherry-pick "+pickSummary(hashes), scopeWork, func() error { return j.CLIService.CherryPick(hashes, opts) })
+}
+
+// Revert records HEAD and the working tree before reverting commits.
+func (j *JournalService) Revert(hashes []string, opts PickOptions) error {
+	return j.record("revert "+pickSummary(hashes), scopeWork, func() error { return j.CLIService.Revert(hashes, opts) })
+}
+
+// SequencerContinue records the sequence's position before continuing.
+func (j *JournalService) SequencerContinue() error {
+	return j.record(j.inProgress()+" continue", scopeWork, j.CLIService.SequencerContinue)
+}
+
+// SequencerSkip records the sequence's position before skipping.
+func (j *JournalService) SequencerSkip() error {
+	return j.record(j.inProgress()+" skip", scopeWork, j.CLIService.SequencerSkip)
+}
+
+// SequencerAbort records the sequence's position before aborting, so Undo
+// can bring back the commits applied so far.
+func (j *JournalService) SequencerAbort() error {
+	return j.record(j.inProgress()+" abort", scopeWork, j.CLIService.SequencerAbort)
+}
+
+// pickSummary names the commits of a cherry-pick or revert.
+func pickSummary(hashes []string) string {
+	if len(hashes) == 1 {
+		return abbrev(hashes[0])
+	}
+	return fmt.Sprintf("%d commits", len(hashes))
+}
+
+// ── Undo / redo ─────────────────────────────────────────────────────────────
+
+// Undo reverses the most recent journaled write and returns its
+// description. It refuses when the state the write left behind has since
+// changed (the undo would clobber that change), and drops the entry.
+// While a merge, rebase, cherry-pick or revert started by that write is
+// still in progress, Undo aborts it. Undoing a write that can't be
+// replayed, like a pull, clears the redo history.
+func (j *JournalService) Undo() (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.gen++
+
+	if len(j.undo) == 0 {
+		return "", ErrNothingToUndo
+	}
+	e := j.undo[len(j.undo)-1]
+	op := j.inProgress()
+	if op != "" && op != e.after.op {
+		return "", fmt.Errorf("can't undo %s while a %s is in progress; finish or abort it first", e.desc, op)
+	}
+	j.undo = j.undo[:len(j.undo)-1]
+
+	if err := j.revert(e, op); err != nil {
+		return "", fmt.Errorf("can't undo %s: %w", e.desc, err)
+	}
+	desc := e.desc
+	if e.do != nil {
+		j.redo = append(j.redo, e)
+	} else {
+		// What was undone before it would be replayed out of order.
+		j.redo = nil
+		desc += " (it can't be redone)"
+	}
+	if e.before.op != "" {
+		desc += fmt.Sprintf(" (back at %s; the %s can't be resumed)", shortHash(e.before.commit), e.before.op)
+	}
+	return desc, nil
+}
+
+// Redo re-runs the most recently undone write and returns its description.
+func (j *JournalService) Redo() (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.gen++
+
+	if len(j.redo) == 0 {
+		return "", ErrNothingToRedo
+	}
+	e := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+	if _, err := j.journal(e.desc, e.scope, e.do); err != nil {
+		return "", fmt.Errorf("can't redo %s: %w", e.desc, err)
+	}
+	return e.desc, nil
+}
+
+// record runs a write through the journal. A new entry invalidates the
+// redo history.
+func (j *JournalService) record(desc string, scope journalScope, do func() error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.gen++
+
+	recorded, err := j.journal(desc, scope, do)
+	if recorded {
+		j.redo = nil
+	}
+	return err
+}
+
+// recordSlow is record for writes that can wait on the network, hooks or
+// a passphrase prompt: the journal is locked only while snapshotting, so
+// undo, redo and other writes aren't held up meanwhile. If any of them
+// ran in between, the snapshots no longer describe this write alone and
+// no entry is recorded. Redo runs redo, which is nil when the write
+// can't be replayed (a pull's context is cancelled once it returns).
+func (j *JournalService) recordSlow(desc string, scope journalScope, do, redo func() error) error {
+	j.mu.Lock()
+	if j.limit <= 0 {
+		j.mu.Unlock()
+		return do()
+	}
+	j.gen++
+	gen := j.gen
+	before, snapErr := j.snapshot(scope)
+	j.mu.Unlock()
+
+	err := do()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if snapErr == nil && j.gen == gen && j.push(journalEntry{desc: desc, scope: scope, before: before, do: redo}) {
+		j.redo = nil
+	}
+	j.gen++
+	return err
+}
+
+// journal snapshots around do and pushes an entry if the state changed —
+// even when do failed, since a merge or stash pop that stops on conflicts
+// has still changed things. If the state can't be captured (e.g. there is
+// no commit yet) the write goes ahead unjournaled.
+func (j *JournalService) journal(desc string, scope journalScope, do func() error) (bool, error) {
+	if j.limit <= 0 {
+		return false, do()
+	}
+	before, snapErr := j.snapshot(scope)
+	err := do()
+	if snapErr != nil {
+		return false, err
+	}
+	return j.push(journalEntry{desc: desc, scope: scope, before: before, do: do}), err
+}
+
+// push snapshots the state e's write left behind and pushes e if it
+// differs from e.before.
+func (j *JournalService) push(e journalEntry) bool {
+	after, err := j.snapshot(e.scope)
+	if err != nil || e.before.equal(after) {
+		return false
+	}
+	e.after = after
+	j.undo = append(j.undo, e)
+	if len(j.undo) > j.limit {
+		j.undo = slices.Delete(j.undo, 0, len(j.undo)-j.limit)
+	}
+	return true
+}
+
+// revert puts back the state from before e. If op (the operation e left
+// in progress) is set it is aborted first, which resets the files, so
+// everything e touched is restored without checking for later changes.
+func (j *JournalService) revert(e journalEntry, op string) error {
+	if op != "" {
+		if _, err := j.runWrite(op, "--abort"); err != nil {
+			return err
+		}
+	}
+	cur, err := j.snapshot(e.scope)
+	if err != nil {
+		return err
+	}
+
+	var indexPaths, workPaths []string
+	if op != "" {
+		if indexPaths, err = j.treeChanges(cur.index, e.before.index); err != nil {
+			return err
+		}
+		if workPaths, err = j.treeChanges(cur.work, e.before.work); err != nil {
+			return err
+		}
+	} else {
+		if err := j.verify(cur, e); err != nil {
+			return err
+		}
+		if indexPaths, err = j.treeChanges(e.after.index, e.before.index); err != nil {
+			return err
+		}
+		if workPaths, err = j.treeChanges(e.after.work, e.before.work); err != nil {
+			return err
+		}
+	}
+
+	reflog := "zgv: undo " + e.desc
+	if e.scope != scopeIndex {
+		if err := j.restoreRefs(cur, e, reflog); err != nil {
+			return err
+		}
+	}
+	if e.scope == scopeWork && len(workPaths) > 0 {
+		if err := j.restorePaths("--worktree", e.before.work, workPaths); err != nil {
+			return err
+		}
+	}
+	if e.scope != scopeRefs && len(indexPaths) > 0 {
+		if err := j.restorePaths("--staged", e.before.index, indexPaths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verify checks that what e changed still looks the way e left it.
+func (j *JournalService) verify(cur snapshot, e journalEntry) error {
+	if e.scope != scopeIndex {
+		if cur.head != e.after.head || cur.commit != e.after.commit {
+			return errors.New("HEAD has moved since")
+		}
+		for _, ref := range changedRefs(e.before.refs, e.after.refs) {
+			name := shortRef(ref)
+			tip, ok := e.after.refs[ref]
+			if _, exists := cur.refs[ref]; !ok && exists {
+				return fmt.Errorf("%s has been recreated since", name)
+			}
+			if cur.refs[ref] != tip {
+				return fmt.Errorf("%s has moved since", name)
+			}
+		}
+		if !slices.Equal(e.before.stashes, e.after.stashes) && !slices.Equal(cur.stashes, e.after.stashes) {
+			return errors.New("the stash list has changed since")
+		}
+	}
+	if e.scope != scopeRefs {
+		if err := j.unchangedSince(e.after.index, e.before.index, cur.index, "the index"); err != nil {
+			return err
+		}
+	}
+	if e.scope == scopeWork {
+		if err := j.unchangedSince(e.after.work, e.before.work, cur.work, "the files"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unchangedSince reports an error if cur differs from after in any of the
+// paths that changed between before and after.
+func (j *JournalService) unchangedSince(after, before, cur, what string) error {
+	paths, err := j.treeChanges(after, before)
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	changed, err := j.treeChanges(after, cur, paths...)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("%s changed since (%s)", what, pathSummary(changed))
+	}
+	return nil
+}
+
+// restoreRefs puts back the branches, tags, stash list and HEAD position
+// that e changed. Refs e didn't touch are left alone.
+func (j *JournalService) restoreRefs(cur snapshot, e journalEntry, reflog string) error {
+	target := e.before
+	var gone []string
+	for _, ref := range changedRefs(e.before.refs, e.after.refs) {
+		tip, ok := target.refs[ref]
+		switch {
+		case !ok:
+			gone = append(gone, ref)
+		case cur.refs[ref] != tip:
+			if _, err := j.runWrite("update-ref", "-m", reflog, ref, tip); err != nil {
+				return err
+			}
+		}
+	}
+
+	if target.head != e.after.head || target.commit != e.after.commit {
+		switch {
+		case target.head != "" && target.head != cur.head:
+			if _, err := j.runWrite("symbolic-ref", "-m", reflog, "HEAD", "refs/heads/"+target.head); err != nil {
+				return err
+			}
+		case target.head == "" && (cur.head != "" || cur.commit != target.commit):
+			if _, err := j.runWrite("update-ref", "--no-deref", "-m", reflog, "HEAD", target.commit); err != nil {
+				return err
+			}
+		}
+	}
+	// Deleted last, once HEAD no longer points at them.
+	for _, ref := range gone {
+		if _, exists := cur.refs[ref]; exists {
+			if _, err := j.runWrite("update-ref", "-d", ref); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !slices.Equal(e.before.stashes, e.after.stashes) && !slices.Equal(cur.stashes, target.stashes) {
+		if len(cur.stashes) > 0 {
+			if _, err := j.runWrite("update-ref", "-d", "refs/stash"); err != nil {
+				return err
+			}
+		}
+		for i := len(target.stashes) - 1; i >= 0; i-- {
+			s := target.stashes[i]
+			if _, err := j.runWrite("stash", "store", "-m", s.message, s.hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// changedRefs lists the refs whose tips differ between a and b, including
+// refs present in only one of them.
+func changedRefs(a, b map[string]string) []string {
+	var refs []string
+	for ref, tip := range a {
+		if b[ref] != tip {
+			refs = append(refs, ref)
+		}
+	}
+	for ref := range b {
+		if _, ok := a[ref]; !ok {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// restorePaths checks paths out of tree into the index (--staged) or the
+// working tree (--worktree). Paths missing from tree are removed.
+func (j *JournalService) restorePaths(where, tree string, paths []string) error {
+	_, err := runGitInput(j.root, literalEnv, cmdTimeoutWrite, strings.Join(paths, "\x00"),
+		"restore", where, "--source="+tree, "--pathspec-from-file=-", "--pathspec-file-nul")
+	return err
+}
+
+// ── Snapshots ───────────────────────────────────────────────────────────────
+
+// snapshot captures the state scope can change. The working tree is only
+// captured for scopeWork: `stash create` is the expensive part.
+func (j *JournalService) snapshot(scope journalScope) (snapshot, error) {
+	var s snapshot
+	out, err := j.run("rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return s, err
+	}
+	s.commit = strings.TrimSpace(out)
+	if out, err := j.run("symbolic-ref", "-q", "HEAD"); err == nil {
+		s.head = strings.TrimPrefix(strings.TrimSpace(out), "refs/heads/")
+	}
+	s.op = j.inProgress()
+
+	headTree := s.commit + "^{tree}"
+	if out, err := j.runWrite("write-tree"); err == nil {
+		s.index = strings.TrimSpace(out)
+	} else if s.index, err = j.resolve(headTree); err != nil {
+		return s, err
+	} else {
+		s.conflicted = true
+	}
+
+	if scope != scopeIndex {
+		if err := j.snapshotRefs(&s); err != nil {
+			return s, err
+		}
+	}
+
+	if scope == scopeWork {
+		work := headTree
+		if s.conflicted {
+			work = s.index
+		} else if out, err := j.runWrite("stash", "create"); err != nil {
+			return s, err
+		} else if stash := strings.TrimSpace(out); stash != "" {
+			work = stash + "^{tree}"
+		}
+		if s.work, err = j.resolve(work); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// snapshotRefs records every branch and tag tip and the stash list.
+func (j *JournalService) snapshotRefs(s *snapshot) error {
+	out, err := j.run("for-each-ref", "--format=%(refname)%00%(objectname)", "refs/heads", "refs/tags", "refs/stash")
+	if err != nil {
+		return err
+	}
+	s.refs = make(map[string]string)
+	hasStash := false
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		ref, tip, ok := strings.Cut(line, "\x00")
+		switch {
+		case !ok:
+		case ref == "refs/stash":
+			hasStash = true
+		default:
+			s.refs[ref] = tip
+		}
+	}
+	if !hasStash {
+		return nil
+	}
+
+	out, err = j.run("log", "-g", "--format=%H%x00%gs", "refs/stash")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if hash, msg, ok := strings.Cut(line, "\x00"); ok {
+			s.stashes = append(s.stashes, stashRef{hash: hash, message: msg})
+		}
+	}
+	return nil
+}
+
+func (j *JournalService) resolve(rev string) (string, error) {
+	out, err := j.run("rev-parse", "--verify", rev)
+	return strings.TrimSpace(out), err
+}
+
+// inProgress names the merge, rebase, cherry-pick or revert waiting to be
+// continued, if any. The name is also the git command that aborts it.
+func (j *JournalService) inProgress() string {
+	switch {
+	case j.IsRebasing():
+		return "rebase"
+	case j.IsMerging():
+		return "merge"
+	case j.IsCherryPicking():
+		return "cherry-pick"
+	case j.IsReverting():
+		return "revert"
+	}
+	return ""
+}
+
+// treeChanges lists the paths that differ between two trees, optionally
+// limited to paths.
+func (j *JournalService) treeChanges(from, to string, paths ...string) ([]string, error) {
+	if from == to {
+		return nil, nil
+	}
+	args := []string{"diff-tree", "-r", "-z", "--name-only", "--no-renames", from, to}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	out, err := runGit(j.root, append(literalEnv, readEnv...), cmdTimeoutRead, args...)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			changed = append(changed, p)
+		}
+	}
+	return changed, nil
+}
+
+func (s snapshot) equal(o snapshot) bool {
+	return s.head == o.head && s.commit == o.commit && s.index == o.index &&
+		s.work == o.work && s.op == o.op &&
+		maps.Equal(s.refs, o.refs) && slices.Equal(s.stashes, o.stashes)
+}
+
+func describePaths(verb string, paths []string) string { return verb + " " + pathSummary(paths) }
+
+// pathSummary names a single path, or counts several.
+func pathSummary(paths []string) string {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	return fmt.Sprintf("%d files", len(paths))
+}
+
+// abbrev shortens full commit hashes in descriptions; other revisions
+// (branch names, HEAD~2) are kept as they are.
+func abbrev(rev string) string {
+	if len(rev) == 40 && strings.Trim(rev, "0123456789abcdef") == "" {
+		return shortHash(rev)
+	}
+	return rev
+}
+
+// shortRef strips the refs/heads/ or refs/tags/ prefix for messages.
+func shortRef(ref string) string {
+	if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		return name
+	}
+	return strings.TrimPrefix(ref, "refs/tags/")
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
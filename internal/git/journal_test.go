@@ -0,0 +1,244 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepo creates a repository whose one commit holds f with
+// content, isolated from the user's git configuration, and a CLIService
+// on it. git runs a git command in the repository.
+func newTestRepo(t *testing.T, content string) (s *CLIService, dir string, git func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, k := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(k+"_NAME", "t")
+		t.Setenv(k+"_EMAIL", "t@example.com")
+	}
+	dir = t.TempDir()
+	git = func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	git("init", "-q", "-b", "main")
+	writeFile(t, dir, "f", content)
+	git("add", "f")
+	git("commit", "-q", "-m", "init")
+
+	s, err := NewCLIService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, dir, git
+}
+
+// newJournalRepo is newTestRepo with a JournalService on the repository.
+func newJournalRepo(t *testing.T, content string) (*JournalService, string, func(args ...string) string) {
+	t.Helper()
+	s, dir, git := newTestRepo(t, content)
+	return NewJournalService(s, 10), dir, git
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJournalUndoRedoStage(t *testing.T) {
+	j, dir, git := newJournalRepo(t, "a\n")
+	writeFile(t, dir, "f", "b\n")
+
+	if err := j.Stage("f"); err != nil {
+		t.Fatal(err)
+	}
+	if got := git("show", ":f"); got != "b\n" {
+		t.Fatalf("index after stage = %q", got)
+	}
+	if _, err := j.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := git("show", ":f"); got != "a\n" {
+		t.Errorf("index after undo = %q, want %q", got, "a\n")
+	}
+	desc, err := j.Redo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc != "stage f" {
+		t.Errorf("Redo = %q, want %q", desc, "stage f")
+	}
+	if got := git("show", ":f"); got != "b\n" {
+		t.Errorf("index after redo = %q, want %q", got, "b\n")
+	}
+	if _, err := j.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("second Redo = %v, want ErrNothingToRedo", err)
+	}
+}
+
+func TestJournalUndoDiscard(t *testing.T) {
+	j, dir, git := newJournalRepo(t, "a\n")
+	writeFile(t, dir, "f", "edited\n")
+
+	if err := j.Discard("f"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, dir, "f"); got != "a\n" {
+		t.Fatalf("f after discard = %q", got)
+	}
+	if _, err := j.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, dir, "f"); got != "edited\n" {
+		t.Errorf("f after undo = %q, want the discarded edit back", got)
+	}
+	if got := git("show", ":f"); got != "a\n" {
+		t.Errorf("index after undo = %q, want it untouched", got)
+	}
+}
+
+func TestJournalUndoDeleteBranch(t *testing.T) {
+	j, _, git := newJournalRepo(t, "a\n")
+	git("branch", "topic")
+	tip := git("rev-parse", "topic")
+
+	if err := j.DeleteBranch("topic", false); err != nil {
+		t.Fatal(err)
+	}
+	desc, err := j.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc != "delete branch topic" {
+		t.Errorf("Undo = %q", desc)
+	}
+	if got := git("rev-parse", "topic"); got != tip {
+		t.Errorf("topic after undo = %s, want %s", got, tip)
+	}
+}
+
+func TestJournalUndoCommit(t *testing.T) {
+	j, dir, git := newJournalRepo(t, "a\n")
+	before := git("rev-parse", "HEAD")
+	writeFile(t, dir, "f", "b\n")
+	git("add", "f")
+
+	if err := j.Commit("second", CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := git("rev-parse", "HEAD"); got != before {
+		t.Errorf("HEAD after undo = %s, want %s", got, before)
+	}
+	if _, err := j.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := git("log", "-1", "--format=%s"); got != "second\n" {
+		t.Errorf("HEAD after redo is %q, want the commit again", got)
+	}
+}
+
+// TestJournalSlowWriteUnlocked runs a write while a slow one is under
+// way, which would deadlock if the slow write held the journal's lock.
+// Only the inner write is recorded: the slow one's snapshots include it.
+func TestJournalSlowWriteUnlocked(t *testing.T) {
+	j, dir, _ := newJournalRepo(t, "a\n")
+	err := j.recordSlow("slow", scopeWork, func() error {
+		writeFile(t, dir, "f", "b\n")
+		return j.Stage("f")
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc, err := j.Undo(); err != nil || desc != "stage f" {
+		t.Fatalf("Undo = %q, %v, want the stage", desc, err)
+	}
+	if _, err := j.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second Undo = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestJournalUndoRefusesChangedState(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(j *JournalService, dir string, git func(...string) string) error
+		change  func(dir string, git func(...string) string)
+		wantErr string
+	}{
+		{"file edited after a discard",
+			func(j *JournalService, dir string, _ func(...string) string) error {
+				writeFile(t, dir, "f", "edited\n")
+				return j.Discard("f")
+			},
+			func(dir string, _ func(...string) string) { writeFile(t, dir, "f", "later\n") },
+			"the files changed since (f)"},
+		{"index changed after staging",
+			func(j *JournalService, dir string, _ func(...string) string) error {
+				writeFile(t, dir, "f", "b\n")
+				return j.Stage("f")
+			},
+			func(dir string, git func(...string) string) {
+				writeFile(t, dir, "f", "c\n")
+				git("add", "f")
+			},
+			"the index changed since (f)"},
+		{"HEAD moved after a commit",
+			func(j *JournalService, dir string, git func(...string) string) error {
+				writeFile(t, dir, "f", "b\n")
+				git("add", "f")
+				return j.Commit("second", CommitOptions{})
+			},
+			func(_ string, git func(...string) string) { git("commit", "-q", "--allow-empty", "-m", "later") },
+			"HEAD has moved since"},
+		{"branch recreated after deleting it",
+			func(j *JournalService, _ string, git func(...string) string) error {
+				git("branch", "topic")
+				return j.DeleteBranch("topic", false)
+			},
+			func(_ string, git func(...string) string) { git("branch", "topic") },
+			"topic has been recreated since"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, dir, git := newJournalRepo(t, "a\n")
+			if err := tt.write(j, dir, git); err != nil {
+				t.Fatal(err)
+			}
+			tt.change(dir, git)
+			_, err := j.Undo()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Undo = %v, want an error containing %q", err, tt.wantErr)
+			}
+			// The refused entry is dropped.
+			if _, err := j.Undo(); !errors.Is(err, ErrNothingToUndo) {
+				t.Errorf("second Undo = %v, want ErrNothingToUndo", err)
+			}
+		})
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -186,7 +187,7 @@ func ParseStatusOutput(out string) *StatusResult {
 
 // ── Branch parsing ──────────────────────────────────────────────────────────
 
-// ParseBranchOutput parses `git branch -a --format=...`.
+// ParseBranchOutput parses `git branch -a --format=...` (see branchFormat).
 func ParseBranchOutput(out string) []Branch {
 	if len(out) == 0 {
 		return nil
@@ -205,7 +206,9 @@ func ParseBranchOutput(out string) []Branch {
 			Upstream:  strings.TrimSpace(parts[3]),
 			Subject:   strings.TrimSpace(parts[5]),
 		}
-		if ab := strings.TrimSpace(parts[4]); ab != "" && ab != "gone" {
+		if ab := strings.TrimSpace(parts[4]); ab == "[gone]" {
+			b.UpstreamGone = true
+		} else if ab != "" {
 			_, _ = fmt.Sscanf(ab, "[ahead %d, behind %d]", &b.Ahead, &b.Behind)
 			if b.Ahead == 0 {
 				_, _ = fmt.Sscanf(ab, "[ahead %d]", &b.Ahead)
@@ -214,15 +217,57 @@ func ParseBranchOutput(out string) []Branch {
 				_, _ = fmt.Sscanf(ab, "[behind %d]", &b.Behind)
 			}
 		}
-		b.IsRemote = strings.HasPrefix(b.Name, "remotes/")
-		if b.IsRemote {
-			b.Name = strings.TrimPrefix(b.Name, "remotes/")
+		// Full ref names tell local and remote-tracking branches apart.
+		if name, ok := strings.CutPrefix(b.Name, "refs/heads/"); ok {
+			b.Name = name
+		} else if name, ok := strings.CutPrefix(strings.TrimPrefix(b.Name, "refs/"), "remotes/"); ok {
+			b.Name = name
+			b.IsRemote = true
 		}
 		branches = append(branches, b)
 	}
 	return branches
 }
 
+// ── Tag parsing ─────────────────────────────────────────────────────────────
+
+// tagFormat is the for-each-ref format parsed by ParseTags. %(*objectname)
+// is the commit behind an annotated tag and empty for a lightweight one.
+const tagFormat = "--format=%(refname:short)%00%(objectname)%00%(*objectname)%00" +
+	"%(taggername)%00%(creatordate:unix)%00%(creatordate:relative)%00%(contents:subject)"
+
+// ParseTags parses `git for-each-ref refs/tags` output in tagFormat.
+func ParseTags(out string) []Tag {
+	if len(out) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	tags := make([]Tag, 0, len(lines))
+	for _, line := range lines {
+		parts := strings.SplitN(line, "\x00", 7)
+		if len(parts) < 7 {
+			continue
+		}
+		t := Tag{
+			Name:      parts[0],
+			Hash:      parts[1],
+			Annotated: parts[2] != "",
+			Tagger:    parts[3],
+			RelDate:   parts[5],
+			Message:   parts[6],
+		}
+		if t.Annotated {
+			t.Hash = parts[2]
+		}
+		t.ShortHash = t.Hash[:min(7, len(t.Hash))]
+		if ts, err := strconv.ParseInt(parts[4], 10, 64); err == nil {
+			t.Date = time.Unix(ts, 0)
+		}
+		tags = append(tags, t)
+	}
+	return tags
+}
+
 // ── Stash parsing ───────────────────────────────────────────────────────────
 
 // ParseStashList parses `git stash list`.
@@ -260,6 +305,45 @@ func ParseStashList(out string) []StashEntry {
 	return entries
 }
 
+// ── Reflog parsing ──────────────────────────────────────────────────────────
+
+// reflogFormat is passed to `git log -g --date=relative`, which makes %gd
+// read "ref@{2 hours ago}".
+const reflogFormat = "--format=%H%x00%h%x00%gd%x00%gs%x00%s"
+
+// ParseReflog parses `git log -g` output for ref, newest entry first.
+// Entries are numbered by position, matching ref@{n}.
+func ParseReflog(ref, out string) []ReflogEntry {
+	if len(out) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	entries := make([]ReflogEntry, 0, len(lines))
+	for _, line := range lines {
+		parts := strings.SplitN(line, "\x00", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		e := ReflogEntry{
+			Ref:       ref,
+			Index:     len(entries),
+			Hash:      parts[0],
+			ShortHash: parts[1],
+			Subject:   parts[4],
+		}
+		if open := strings.Index(parts[2], "@{"); open != -1 {
+			e.RelDate = strings.TrimSuffix(parts[2][open+2:], "}")
+		}
+		e.Action, e.Message, _ = strings.Cut(parts[3], ": ")
+		e.Op = e.Action
+		if i := strings.IndexAny(e.Op, " ("); i != -1 {
+			e.Op = e.Op[:i]
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
 // ── Remote parsing ──────────────────────────────────────────────────────────
 
 // ParseRemoteOutput parses `git remote -v`.
@@ -297,6 +381,32 @@ func ParseRemoteOutput(out string) []Remote {
 	return remotes
 }
 
+// progressRe matches a git progress line: "Receiving objects:  45%
+// (450/1000), 1.20 MiB | 600.00 KiB/s" or "Enumerating objects: 5, done.".
+var progressRe = regexp.MustCompile(`^([A-Z][A-Za-z ]*[a-z]):\s+(?:(\d+)% \((\d+)/(\d+)\)|(\d+))`)
+
+// ParseProgress parses one progress line from a network command's stderr,
+// with or without the "remote: " prefix the server's lines carry.
+func ParseProgress(line string) (Progress, bool) {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "remote:"))
+	m := progressRe.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	p := Progress{Phase: m[1], Percent: -1}
+	if m[2] != "" {
+		p.Percent, _ = strconv.Atoi(m[2])
+		p.Current, _ = strconv.Atoi(m[3])
+		p.Total, _ = strconv.Atoi(m[4])
+	} else {
+		p.Current, _ = strconv.Atoi(m[5])
+	}
+	if i := strings.Index(line, "| "); i >= 0 {
+		p.Rate = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(line[i+2:]), ", done."), ",")
+	}
+	return p, true
+}
+
 // ── Worktree parsing ────────────────────────────────────────────────────────
 
 // ParseWorktreeList parses `git worktree list --porcelain`.
@@ -381,3 +491,261 @@ func findGraphEnd(line string) int {
 	}
 	return len(line)
 }
+
+// ── Diff parsing ────────────────────────────────────────────────────────────
+
+// ParseNumstat parses `git diff --numstat -z`. Each record is
+// "added<TAB>deleted<TAB>path<NUL>", or for a rename
+// "added<TAB>deleted<TAB><NUL>old<NUL>new<NUL>". Binary files count "-".
+func ParseNumstat(out string) []DiffFileStat {
+	fields := strings.Split(strings.TrimRight(out, "\x00"), "\x00")
+	var stats []DiffFileStat
+	for i := 0; i < len(fields); i++ {
+		added, rest, ok := strings.Cut(fields[i], "\t")
+		if !ok {
+			continue
+		}
+		deleted, path, _ := strings.Cut(rest, "\t")
+		st := DiffFileStat{Path: path, Binary: added == "-"}
+		st.Added, _ = strconv.Atoi(added)
+		st.Deleted, _ = strconv.Atoi(deleted)
+		if path == "" && i+2 < len(fields) {
+			st.OldPath, st.Path = fields[i+1], fields[i+2]
+			i += 2
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// ParseDiff parses unified `git diff` output into per-file hunks.
+// Header lines are kept verbatim so partial patches can be rebuilt from
+// the model without re-deriving mode/index metadata.
+func ParseDiff(out string) []FileDiff {
+	if len(out) == 0 {
+		return nil
+	}
+	var files []FileDiff
+	var cur *FileDiff
+	var hunk *Hunk
+	oldLn, newLn := 0, 0
+
+	flushHunk := func() {
+		if cur != nil && hunk != nil {
+			cur.Hunks = append(cur.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if cur != nil {
+			files = append(files, *cur)
+		}
+		cur = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushFile()
+			cur = &FileDiff{Header: []string{line}}
+			if a, b, ok := splitDiffGitPaths(strings.TrimPrefix(line, "diff --git ")); ok {
+				cur.OldPath, cur.NewPath = a, b
+			}
+			continue
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			flushHunk()
+			h, ok := parseHunkHeader(line)
+			if !ok {
+				continue
+			}
+			hunk = &h
+			oldLn, newLn = h.OldStart, h.NewStart
+			continue
+		}
+
+		if hunk == nil {
+			cur.Header = append(cur.Header, line)
+			switch {
+			case strings.HasPrefix(line, "--- "):
+				cur.OldPath = trimDiffPath(strings.TrimPrefix(line, "--- "), "a/")
+			case strings.HasPrefix(line, "+++ "):
+				cur.NewPath = trimDiffPath(strings.TrimPrefix(line, "+++ "), "b/")
+			case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+				cur.Binary = true
+			}
+			continue
+		}
+
+		if line == "" {
+			// A bare empty line inside a hunk is a context line whose
+			// leading space was stripped by an editor or transport.
+			line = " "
+		}
+		dl := DiffLine{Kind: DiffLineKind(line[0]), Content: line[1:]}
+		switch dl.Kind {
+		case DiffLineAdded:
+			dl.NewLine = newLn
+			newLn++
+		case DiffLineRemoved:
+			dl.OldLine = oldLn
+			oldLn++
+		case DiffLineContext:
+			dl.OldLine, dl.NewLine = oldLn, newLn
+			oldLn++
+			newLn++
+		case DiffLineNoNewline:
+		default:
+			continue
+		}
+		hunk.Lines = append(hunk.Lines, dl)
+	}
+	flushFile()
+	return files
+}
+
+// parseHunkHeader parses "@@ -a,b +c,d @@ section".
+func parseHunkHeader(line string) (Hunk, bool) {
+	rest := strings.TrimPrefix(line, "@@")
+	end := strings.Index(rest, "@@")
+	if end < 0 {
+		return Hunk{}, false
+	}
+	h := Hunk{Section: strings.TrimSpace(rest[end+2:])}
+	for _, tok := range strings.Fields(rest[:end]) {
+		switch tok[0] {
+		case '-':
+			h.OldStart, h.OldCount = parseRangeToken(tok[1:])
+		case '+':
+			h.NewStart, h.NewCount = parseRangeToken(tok[1:])
+		}
+	}
+	return h, true
+}
+
+func parseRangeToken(tok string) (int, int) {
+	start, count := tok, "1"
+	if i := strings.IndexByte(tok, ','); i >= 0 {
+		start, count = tok[:i], tok[i+1:]
+	}
+	s, _ := strconv.Atoi(start)
+	c, _ := strconv.Atoi(count)
+	return s, c
+}
+
+// splitDiffGitPaths splits "a/foo b/foo" from a `diff --git` line. Paths
+// containing " b/" are ambiguous; the ---/+++ lines override when present.
+func splitDiffGitPaths(s string) (string, string, bool) {
+	i := strings.Index(s, " b/")
+	if i < 0 || !strings.HasPrefix(s, "a/") {
+		return "", "", false
+	}
+	return s[2:i], s[i+3:], true
+}
+
+func trimDiffPath(p, prefix string) string {
+	p = strings.TrimSuffix(p, "\t")
+	if p == "/dev/null" {
+		return p
+	}
+	return strings.TrimPrefix(p, prefix)
+}
+
+// ── Rebase todo parsing ─────────────────────────────────────────────────────
+
+// rebaseAbbrev maps git's single-letter todo abbreviations to actions.
+var rebaseAbbrev = map[string]RebaseAction{
+	"p": RebasePick, "r": RebaseReword, "e": RebaseEdit,
+	"s": RebaseSquash, "f": RebaseFixup, "d": RebaseDrop, "x": RebaseExec,
+}
+
+// ParseRebaseTodo parses a git-rebase-todo (or rebase-merge/done) file.
+// Comments, blank lines and commands the editor doesn't model (label,
+// reset, merge, break, update-ref) are skipped.
+func ParseRebaseTodo(out string) []RebaseTodoItem {
+	var items []RebaseTodoItem
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cmd, rest, _ := strings.Cut(line, " ")
+		action := RebaseAction(cmd)
+		if a, ok := rebaseAbbrev[cmd]; ok {
+			action = a
+		}
+		switch action {
+		case RebaseExec:
+			items = append(items, RebaseTodoItem{Action: action, Command: strings.TrimSpace(rest)})
+		case RebasePick, RebaseReword, RebaseEdit, RebaseSquash, RebaseFixup, RebaseDrop:
+			// fixup may carry -C/-c flags before the hash.
+			fields := strings.Fields(rest)
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+				fields = fields[1:]
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			item := RebaseTodoItem{Action: action, Hash: fields[0]}
+			if len(fields) > 1 {
+				item.Subject = strings.TrimPrefix(strings.Join(fields[1:], " "), "# ")
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ── Conflict parsing ────────────────────────────────────────────────────────
+
+// ParseUnmerged parses `git ls-files -u -z` output into one ConflictFile
+// per path, classified by which index stages (1 base, 2 ours, 3 theirs)
+// are present.
+func ParseUnmerged(out string) []ConflictFile {
+	stages := make(map[string]int) // path → bitmask of stages
+	var order []string
+	for _, rec := range strings.Split(out, "\x00") {
+		meta, path, ok := strings.Cut(rec, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 3 {
+			continue
+		}
+		stage, err := strconv.Atoi(fields[2])
+		if err != nil || stage < 1 || stage > 3 {
+			continue
+		}
+		if _, seen := stages[path]; !seen {
+			order = append(order, path)
+		}
+		stages[path] |= 1 << stage
+	}
+
+	const base, ours, theirs = 1 << 1, 1 << 2, 1 << 3
+	files := make([]ConflictFile, 0, len(order))
+	for _, path := range order {
+		var kind ConflictKind
+		switch stages[path] {
+		case ours | theirs:
+			kind = ConflictBothAdded
+		case base | theirs:
+			kind = ConflictDeletedByUs
+		case base | ours:
+			kind = ConflictDeletedByThem
+		case ours:
+			kind = ConflictAddedByUs
+		case theirs:
+			kind = ConflictAddedByThem
+		case base:
+			kind = ConflictBothDeleted
+		default:
+			kind = ConflictBothModified
+		}
+		files = append(files, ConflictFile{Path: path, Kind: kind})
+	}
+	return files
+}
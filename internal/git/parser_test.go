@@ -0,0 +1,157 @@
+package git
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []DiffFileStat
+	}{
+		{"empty", "", nil},
+		{"changes", "3\t1\ta.go\x000\t7\tdir/b.txt\x00",
+			[]DiffFileStat{{Path: "a.go", Added: 3, Deleted: 1}, {Path: "dir/b.txt", Deleted: 7}}},
+		{"binary", "-\t-\timg.png\x00",
+			[]DiffFileStat{{Path: "img.png", Binary: true}}},
+		{"rename", "2\t0\t\x00old name.go\x00new name.go\x001\t1\tc.go\x00",
+			[]DiffFileStat{{Path: "new name.go", OldPath: "old name.go", Added: 2}, {Path: "c.go", Added: 1, Deleted: 1}}},
+		{"binary rename", "-\t-\t\x00a.png\x00b.png\x00",
+			[]DiffFileStat{{Path: "b.png", OldPath: "a.png", Binary: true}}},
+		{"tab in a path", "1\t0\ta\tb\x00",
+			[]DiffFileStat{{Path: "a\tb", Added: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseNumstat(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNumstat = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReflog(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []ReflogEntry
+	}{
+		{"empty", "", nil},
+		{"entries", "c1\x00c1s\x00HEAD@{2 hours ago}\x00commit (amend): fix typo\x00fix typo\n" +
+			"b1\x00b1s\x00HEAD@{3 days ago}\x00checkout: moving from main to topic\x00add parser\n" +
+			"a1\x00a1s\x00HEAD@{4 days ago}\x00rebase (finish): returning to refs/heads/main\x00init\n",
+			[]ReflogEntry{
+				{Ref: "HEAD", Index: 0, Hash: "c1", ShortHash: "c1s", Op: "commit", Action: "commit (amend)",
+					Message: "fix typo", Subject: "fix typo", RelDate: "2 hours ago"},
+				{Ref: "HEAD", Index: 1, Hash: "b1", ShortHash: "b1s", Op: "checkout", Action: "checkout",
+					Message: "moving from main to topic", Subject: "add parser", RelDate: "3 days ago"},
+				{Ref: "HEAD", Index: 2, Hash: "a1", ShortHash: "a1s", Op: "rebase", Action: "rebase (finish)",
+					Message: "returning to refs/heads/main", Subject: "init", RelDate: "4 days ago"},
+			}},
+		{"branch created", "a1\x00a1s\x00HEAD@{now}\x00branch: Created from HEAD\x00init\n",
+			[]ReflogEntry{{Ref: "HEAD", Hash: "a1", ShortHash: "a1s", Op: "branch", Action: "branch",
+				Message: "Created from HEAD", Subject: "init", RelDate: "now"}}},
+		{"malformed line skipped", "garbage\nb1\x00b1s\x00HEAD@{now}\x00reset: moving to HEAD~1\x00s\n",
+			[]ReflogEntry{{Ref: "HEAD", Hash: "b1", ShortHash: "b1s", Op: "reset", Action: "reset",
+				Message: "moving to HEAD~1", Subject: "s", RelDate: "now"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseReflog("HEAD", tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReflog = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []Tag
+	}{
+		{"empty", "", nil},
+		{"annotated and lightweight",
+			"v1.1\x00aaaa1111tagobject\x00cccc2222commit\x00Ann\x001700000000\x002 days ago\x00Release 1.1\n" +
+				"wip\x00dddd3333commit\x00\x00\x001690000000\x004 months ago\x00half done\n",
+			[]Tag{
+				{Name: "v1.1", Hash: "cccc2222commit", ShortHash: "cccc222", Annotated: true, Tagger: "Ann",
+					Date: time.Unix(1700000000, 0), RelDate: "2 days ago", Message: "Release 1.1"},
+				{Name: "wip", Hash: "dddd3333commit", ShortHash: "dddd333",
+					Date: time.Unix(1690000000, 0), RelDate: "4 months ago", Message: "half done"},
+			}},
+		{"short hash, unparsable date", "t\x00abc\x00\x00\x00x\x00\x00a\tb\n",
+			[]Tag{{Name: "t", Hash: "abc", ShortHash: "abc", Message: "a\tb"}}},
+		{"malformed line skipped", "v1\x00abc\nv2\x00def1234567\x00\x00\x00\x00\x00\n",
+			[]Tag{{Name: "v2", Hash: "def1234567", ShortHash: "def1234"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTags(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTags = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		line string
+		want Progress
+		ok   bool
+	}{
+		{"remote: Enumerating objects: 5, done.", Progress{Phase: "Enumerating objects", Percent: -1, Current: 5}, true},
+		{"remote: Counting objects:  40% (2/5)", Progress{Phase: "Counting objects", Percent: 40, Current: 2, Total: 5}, true},
+		{"Compressing objects: 100% (3/3), done.", Progress{Phase: "Compressing objects", Percent: 100, Current: 3, Total: 3}, true},
+		{"Receiving objects:  45% (450/1000), 1.20 MiB | 600.00 KiB/s",
+			Progress{Phase: "Receiving objects", Percent: 45, Current: 450, Total: 1000, Rate: "600.00 KiB/s"}, true},
+		{"Receiving objects: 100% (1000/1000), 2.40 MiB | 1.20 MiB/s, done.",
+			Progress{Phase: "Receiving objects", Percent: 100, Current: 1000, Total: 1000, Rate: "1.20 MiB/s"}, true},
+		{"remote: Total 3 (delta 0), reused 0 (delta 0), pack-reused 0", Progress{}, false},
+		{"From http://127.0.0.1:8080/r", Progress{}, false},
+		{" * [new branch]      main       -> origin/main", Progress{}, false},
+		{"fatal: Authentication failed for 'http://127.0.0.1/r/'", Progress{}, false},
+		{"", Progress{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseProgress(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseProgress(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestProgressWriter feeds stderr in chunks that split lines, the way git
+// writes it: updates of one phase end in \r, the last one in \n.
+func TestProgressWriter(t *testing.T) {
+	stderr := "remote: Counting objects:  50% (1/2)\rremote: Counting objects: 100% (2/2), done.\n" +
+		"Receiving objects:  50% (1/2)\rReceiving objects: 100% (2/2), done.\n" +
+		"From http://127.0.0.1/r\n fatal: bad object\n"
+	var got []Progress
+	w := &progressWriter{progress: func(p Progress) { got = append(got, p) }}
+	for chunk := range slices.Chunk([]byte(stderr), 7) {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.flush()
+	want := []Progress{
+		{Phase: "Counting objects", Percent: 50, Current: 1, Total: 2},
+		{Phase: "Counting objects", Percent: 100, Current: 2, Total: 2},
+		{Phase: "Receiving objects", Percent: 50, Current: 1, Total: 2},
+		{Phase: "Receiving objects", Percent: 100, Current: 2, Total: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("progress = %+v, want %+v", got, want)
+	}
+	if want := []string{"From http://127.0.0.1/r", "fatal: bad object"}; !reflect.DeepEqual(w.messages, want) {
+		t.Errorf("messages = %q, want %q", w.messages, want)
+	}
+	if got := w.errorText(); got != "fatal: bad object" {
+		t.Errorf("errorText = %q", got)
+	}
+}
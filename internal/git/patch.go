@@ -0,0 +1,183 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineSelector reports whether line `line` of hunk `hunk` is part of the
+// selection a partial patch should carry.
+type LineSelector func(hunk, line int) bool
+
+// SelectHunk returns a LineSelector that selects every line of one hunk.
+func SelectHunk(hunk int) LineSelector {
+	return func(h, _ int) bool { return h == hunk }
+}
+
+// SelectLines returns a LineSelector for the inclusive line range
+// [from, to] within a single hunk.
+func SelectLines(hunk, from, to int) LineSelector {
+	if from > to {
+		from, to = to, from
+	}
+	return func(h, l int) bool { return h == hunk && l >= from && l <= to }
+}
+
+// BuildPatch produces a patch for the selected lines of f, suitable for
+// `git apply`. It works like `git add -p` line editing:
+//
+// Forward patches (staging) drop unselected additions and turn unselected
+// removals into context. Reverse patches (unstaging, discarding — applied
+// with --reverse) do the mirror image: unselected additions become
+// context and unselected removals are dropped. Hunk headers are recounted
+// so git never sees a corrupt patch.
+//
+// The second return value is false when the selection contains no changes.
+func BuildPatch(f *FileDiff, sel LineSelector, reverse bool) (string, bool) {
+	if f == nil || f.Binary || len(f.Hunks) == 0 {
+		return "", false
+	}
+
+	var body strings.Builder
+	delta := 0 // running (new - old) line offset of emitted hunks
+	emitted := false
+
+	for hi, h := range f.Hunks {
+		lines := make([]string, 0, len(h.Lines))
+		oldCount, newCount := 0, 0
+		changed := false
+		// dropped tracks whether the previous line was omitted, so its
+		// trailing "\ No newline" marker is omitted with it.
+		dropped := false
+		// A line without a newline must stay the last of its side. When
+		// an unselected removal kept as context is the old last line and
+		// additions follow, it is removed and added back with a newline
+		// (eofContext is its index). Reversed, a selected removal that was
+		// the last line gains a newline when unselected additions follow
+		// as context (eofMarker is the index of its marker).
+		converted, removed := false, false
+		eofContext, eofMarker := -1, -1
+
+		for li, l := range h.Lines {
+			selected := sel(hi, li)
+			wasConverted, wasRemoved := converted, removed
+			converted, removed = false, false
+			switch l.Kind {
+			case DiffLineContext:
+				lines = append(lines, " "+l.Content)
+				oldCount++
+				newCount++
+				dropped = false
+			case DiffLineAdded:
+				switch {
+				case selected:
+					if eofContext >= 0 {
+						kept := lines[eofContext][1:]
+						lines[eofContext] = "-" + kept
+						lines = append(lines, "+"+kept)
+						eofContext = -1
+					}
+					lines = append(lines, "+"+l.Content)
+					newCount++
+					changed = true
+					dropped = false
+				case reverse:
+					if eofMarker >= 0 {
+						lines = append(lines[:eofMarker], lines[eofMarker+1:]...)
+						eofMarker = -1
+					}
+					lines = append(lines, " "+l.Content)
+					oldCount++
+					newCount++
+					dropped = false
+				default:
+					dropped = true
+				}
+			case DiffLineRemoved:
+				switch {
+				case selected:
+					lines = append(lines, "-"+l.Content)
+					oldCount++
+					changed = true
+					dropped = false
+					removed = true
+				case !reverse:
+					lines = append(lines, " "+l.Content)
+					oldCount++
+					newCount++
+					dropped = false
+					converted = true
+				default:
+					dropped = true
+				}
+			case DiffLineNoNewline:
+				if dropped {
+					break
+				}
+				lines = append(lines, `\`+l.Content)
+				switch {
+				case wasConverted:
+					eofContext = len(lines) - 2
+				case wasRemoved && reverse:
+					eofMarker = len(lines) - 1
+				}
+			}
+		}
+
+		if !changed {
+			continue
+		}
+		emitted = true
+
+		// The side the patch keeps whole (old forward, new reversed)
+		// keeps its start; the other follows it, shifted by the hunks
+		// emitted before. An empty range starts at the line before it,
+		// which is where git places it when there is no context.
+		oldStart, newStart := h.OldStart, h.NewStart
+		if reverse {
+			oldStart = rangeStart(firstLine(newStart, newCount)-delta, oldCount)
+		} else {
+			newStart = rangeStart(firstLine(oldStart, oldCount)+delta, newCount)
+		}
+		delta += newCount - oldCount
+
+		fmt.Fprintf(&body, "@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)
+		if h.Section != "" {
+			body.WriteString(" " + h.Section)
+		}
+		body.WriteByte('\n')
+		for _, l := range lines {
+			body.WriteString(l)
+			body.WriteByte('\n')
+		}
+	}
+
+	if !emitted {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, h := range f.Header {
+		b.WriteString(h)
+		b.WriteByte('\n')
+	}
+	b.WriteString(body.String())
+	return b.String(), true
+}
+
+// firstLine returns the first line of a hunk range given as start,count:
+// an empty range's start is the line before it.
+func firstLine(start, count int) int {
+	if count == 0 {
+		return start + 1
+	}
+	return start
+}
+
+// rangeStart is the reverse of firstLine.
+func rangeStart(first, count int) int {
+	if count == 0 {
+		return first - 1
+	}
+	return first
+}
@@ -0,0 +1,217 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// twoChanges replaces b with B and d with D, in one hunk with context.
+const twoChanges = `diff --git a/f b/f
+index 1111111..2222222 100644
+--- a/f
++++ b/f
+@@ -1,4 +1,4 @@ func x
+ a
+-b
++B
+ c
+-d
++D
+`
+
+// zeroContext is twoChanges' shape as `git diff -U0` prints it: an
+// insertion after line 1 and a replacement of old line 5.
+const zeroContext = `diff --git a/f b/f
+--- a/f
++++ b/f
+@@ -1,0 +2,2 @@
++x
++y
+@@ -5 +7 @@
+-e
++E
+`
+
+// noNewline replaces a last line that has no newline.
+const noNewline = `diff --git a/f b/f
+--- a/f
++++ b/f
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++c
+\ No newline at end of file
+`
+
+const newFile = `diff --git a/f b/f
+new file mode 100644
+--- /dev/null
++++ b/f
+@@ -0,0 +1,2 @@
++a
++b
+`
+
+func TestBuildPatch(t *testing.T) {
+	header := func(diff string) string {
+		return diff[:strings.Index(diff, "@@")]
+	}
+	tests := []struct {
+		name    string
+		diff    string
+		sel     LineSelector
+		reverse bool
+		want    string // without the file header; "" when nothing is selected
+	}{
+		{"stage one change", twoChanges, SelectLines(0, 1, 2), false,
+			"@@ -1,4 +1,4 @@ func x\n a\n-b\n+B\n c\n d\n"},
+		{"stage a lone addition", twoChanges, SelectLines(0, 5, 5), false,
+			"@@ -1,4 +1,5 @@ func x\n a\n b\n c\n d\n+D\n"},
+		{"unstage one change", twoChanges, SelectLines(0, 1, 2), true,
+			"@@ -1,4 +1,4 @@ func x\n a\n-b\n+B\n c\n D\n"},
+		{"unstage a lone removal", twoChanges, SelectLines(0, 4, 4), true,
+			"@@ -1,5 +1,4 @@ func x\n a\n B\n c\n-d\n D\n"},
+		{"context only", twoChanges, SelectLines(0, 0, 0), false, ""},
+		{"whole diff", twoChanges, SelectHunk(0), false,
+			"@@ -1,4 +1,4 @@ func x\n a\n-b\n+B\n c\n-d\n+D\n"},
+
+		{"zero context: insertion", zeroContext, SelectHunk(0), false,
+			"@@ -1,0 +2,2 @@\n+x\n+y\n"},
+		{"zero context: later hunk alone", zeroContext, SelectHunk(1), false,
+			"@@ -5,1 +5,1 @@\n-e\n+E\n"},
+		{"zero context: both hunks", zeroContext, func(int, int) bool { return true }, false,
+			"@@ -1,0 +2,2 @@\n+x\n+y\n@@ -5,1 +7,1 @@\n-e\n+E\n"},
+		{"zero context: part of an insertion", zeroContext, SelectLines(0, 1, 1), false,
+			"@@ -1,0 +2,1 @@\n+y\n"},
+		{"zero context: unstage later hunk", zeroContext, SelectHunk(1), true,
+			"@@ -7,1 +7,1 @@\n-e\n+E\n"},
+		{"zero context: unstage a removal", zeroContext, SelectLines(1, 0, 0), true,
+			"@@ -7,2 +7,1 @@\n-e\n E\n"},
+
+		{"no newline: whole change", noNewline, SelectHunk(0), false,
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"},
+		{"no newline: stage the removal", noNewline, SelectLines(0, 1, 1), false,
+			"@@ -1,2 +1,1 @@\n a\n-b\n\\ No newline at end of file\n"},
+		{"no newline: stage the addition", noNewline, SelectLines(0, 3, 3), false,
+			"@@ -1,2 +1,3 @@\n a\n-b\n\\ No newline at end of file\n+b\n+c\n\\ No newline at end of file\n"},
+		{"no newline: unstage the removal", noNewline, SelectLines(0, 1, 1), true,
+			"@@ -1,3 +1,2 @@\n a\n-b\n c\n\\ No newline at end of file\n"},
+		{"no newline: unstage the addition", noNewline, SelectLines(0, 3, 3), true,
+			"@@ -1,1 +1,2 @@\n a\n+c\n\\ No newline at end of file\n"},
+
+		{"new file", newFile, SelectHunk(0), false, "@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"new file: one line", newFile, SelectLines(0, 1, 1), false, "@@ -0,0 +1,1 @@\n+b\n"},
+		{"new file: unstage one line", newFile, SelectLines(0, 0, 0), true, "@@ -1,1 +1,2 @@\n+a\n b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := ParseDiff(tt.diff)
+			if len(files) != 1 {
+				t.Fatalf("ParseDiff: %d files, want 1", len(files))
+			}
+			got, ok := BuildPatch(&files[0], tt.sel, tt.reverse)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("BuildPatch = %q, want no patch", got)
+				}
+				return
+			}
+			if want := header(tt.diff) + tt.want; !ok || got != want {
+				t.Errorf("BuildPatch = %q, %v\nwant %q", got, ok, want)
+			}
+		})
+	}
+}
+
+func TestBuildPatchBinary(t *testing.T) {
+	f := &FileDiff{Binary: true, Hunks: []Hunk{{}}}
+	if _, ok := BuildPatch(f, SelectHunk(0), false); ok {
+		t.Error("BuildPatch built a patch for a binary file")
+	}
+}
+
+// TestBuildPatchApplies stages (or, reversed, unstages) partial patches
+// with git itself and checks what reaches the index, since git is the
+// judge of a valid patch.
+func TestBuildPatchApplies(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tests := []struct {
+		name      string
+		old, new  string
+		sel       LineSelector
+		reverse   bool // unstage from a fully staged change
+		zero      bool // -U0, applied with --unidiff-zero
+		wantIndex string
+	}{
+		{"one of two changes", "a\nb\nc\nd\n", "a\nB\nc\nD\n", SelectLines(0, 1, 2), false, false, "a\nB\nc\nd\n"},
+		{"lone addition", "a\nb\n", "a\nb\nc\n", SelectLines(0, 2, 2), false, false, "a\nb\nc\n"},
+		{"insertion without context", "a\nb\nc\nd\ne\nf\n", "a\nx\ny\nb\nc\nd\nE\nf\n", SelectHunk(0), false, true,
+			"a\nx\ny\nb\nc\nd\ne\nf\n"},
+		{"replacement without context", "a\nb\nc\nd\ne\nf\n", "a\nx\ny\nb\nc\nd\nE\nf\n", SelectHunk(1), false, true,
+			"a\nb\nc\nd\nE\nf\n"},
+		{"addition after a last line without newline", "a\nb", "a\nc", SelectLines(0, 3, 3), false, false, "a\nb\nc"},
+		{"removal of a last line without newline", "a\nb", "a\nc", SelectLines(0, 1, 1), false, false, "a\n"},
+
+		{"unstage one of two changes", "a\nb\nc\nd\n", "a\nB\nc\nD\n", SelectLines(0, 1, 2), true, false, "a\nb\nc\nD\n"},
+		{"unstage a later hunk without context", "a\nb\nc\nd\ne\nf\n", "a\nx\ny\nb\nc\nd\nE\nf\n", SelectHunk(1), true, true,
+			"a\nx\ny\nb\nc\nd\ne\nf\n"},
+		{"unstage the removal of a last line without newline", "a\nb", "a\nc", SelectLines(0, 1, 1), true, false, "a\nb\nc"},
+		{"unstage the addition after a last line without newline", "a\nb", "a\nc", SelectLines(0, 3, 3), true, false, "a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			git := func(stdin string, args ...string) string {
+				t.Helper()
+				cmd := exec.Command("git", args...)
+				cmd.Dir = dir
+				cmd.Stdin = strings.NewReader(stdin)
+				cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+					"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+					"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+				out, err := cmd.CombinedOutput()
+				if err != nil {
+					t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+				}
+				return string(out)
+			}
+			write := func(content string) {
+				if err := os.WriteFile(filepath.Join(dir, "f"), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			git("", "init", "-q")
+			write(tt.old)
+			git("", "add", "f")
+			git("", "commit", "-q", "-m", "old")
+			write(tt.new)
+
+			diffArgs := []string{"diff", "--no-color"}
+			applyArgs := []string{"apply", "--cached"}
+			if tt.reverse {
+				git("", "add", "f")
+				diffArgs = append(diffArgs, "--cached")
+				applyArgs = append(applyArgs, "--reverse")
+			}
+			if tt.zero {
+				diffArgs = append(diffArgs, "-U0")
+				applyArgs = append(applyArgs, "--unidiff-zero")
+			}
+			files := ParseDiff(git("", diffArgs...))
+			patch, ok := BuildPatch(&files[0], tt.sel, tt.reverse)
+			if !ok {
+				t.Fatal("BuildPatch built no patch")
+			}
+			git(patch, append(applyArgs, "-")...)
+			if got := git("", "show", ":f"); got != tt.wantIndex {
+				t.Errorf("index has %q, want %q\npatch:\n%s", got, tt.wantIndex, patch)
+			}
+		})
+	}
+}
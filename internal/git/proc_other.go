@@ -0,0 +1,9 @@
+//go:build !unix
+
+package git
+
+import "os/exec"
+
+// killGroup leaves cmd as it is: without process groups only git itself
+// is killed, and its helpers exit when their pipes close.
+func killGroup(*exec.Cmd) {}
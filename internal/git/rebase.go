@@ -0,0 +1,147 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SequenceEditorCommand is the hidden zgv subcommand git invokes as
+// GIT_SEQUENCE_EDITOR. It replaces git's generated todo with the one
+// prepared in the TUI (see RunSequenceEditor).
+const SequenceEditorCommand = "__sequence-editor"
+
+// rebaseTodoFile is where the prepared todo waits for the shim, relative
+// to the .git directory.
+const rebaseTodoFile = "zgv-rebase-todo"
+
+// ErrInvalidTodo is returned when a todo list can't be handed to git.
+var ErrInvalidTodo = errors.New("invalid rebase todo")
+
+// ValidateRebaseTodo checks the constraints git would otherwise reject
+// half-way through the rebase.
+func ValidateRebaseTodo(todo []RebaseTodoItem) error {
+	picked := false
+	for _, item := range todo {
+		switch item.Action {
+		case RebaseSquash, RebaseFixup:
+			if !picked {
+				return fmt.Errorf("%w: cannot %s without a previous commit", ErrInvalidTodo, item.Action)
+			}
+		case RebaseExec:
+			if strings.TrimSpace(item.Command) == "" {
+				return fmt.Errorf("%w: empty exec command", ErrInvalidTodo)
+			}
+		case RebasePick, RebaseReword, RebaseEdit:
+			picked = true
+		}
+	}
+	return nil
+}
+
+// FormatRebaseTodo renders todo as a git-rebase-todo file. Reword items
+// with a Message become "pick" followed by an exec that amends the message
+// from a file under gitDir, so git never needs to open an editor.
+func FormatRebaseTodo(todo []RebaseTodoItem, gitDir string) (string, error) {
+	var b strings.Builder
+	for i, item := range todo {
+		switch {
+		case item.Action == RebaseExec:
+			fmt.Fprintf(&b, "exec %s\n", item.Command)
+		case item.Action == RebaseReword && item.Message != "":
+			msgPath := filepath.Join(gitDir, fmt.Sprintf("zgv-reword-%d.msg", i))
+			if err := os.WriteFile(msgPath, []byte(item.Message+"\n"), 0o644); err != nil {
+				return "", fmt.Errorf("writing reword message: %w", err)
+			}
+			fmt.Fprintf(&b, "pick %s %s\n", item.Hash, item.Subject)
+			fmt.Fprintf(&b, "exec git commit --amend --only --allow-empty --no-verify -F %s\n", shellQuote(msgPath))
+		default:
+			fmt.Fprintf(&b, "%s %s %s\n", item.Action, item.Hash, item.Subject)
+		}
+	}
+	return b.String(), nil
+}
+
+// removeRewordMessages deletes message files left by FormatRebaseTodo.
+// They must survive until the rebase finishes, so callers only clean up
+// once no rebase is in progress.
+func removeRewordMessages(gitDir string) {
+	matches, _ := filepath.Glob(filepath.Join(gitDir, "zgv-reword-*.msg"))
+	for _, m := range matches {
+		_ = os.Remove(m)
+	}
+}
+
+// sequenceEditorEnv returns the environment that makes git run zgv as its
+// sequence editor, and the (no-op) commit message editor so squash and
+// continue never block on an interactive editor.
+func sequenceEditorEnv(todoPath string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating zgv executable: %w", err)
+	}
+	editor := shellQuote(exe) + " " + SequenceEditorCommand + " " + shellQuote(todoPath)
+	return []string{"GIT_SEQUENCE_EDITOR=" + editor, "GIT_EDITOR=:"}, nil
+}
+
+// RunSequenceEditor is the body of the GIT_SEQUENCE_EDITOR shim: it moves
+// the prepared todo at src over git's todo file at dst.
+func RunSequenceEditor(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("reading prepared todo: %w", err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		return fmt.Errorf("writing rebase todo: %w", err)
+	}
+	_ = os.Remove(src)
+	return nil
+}
+
+// readRebaseProgress reads the state of a stopped rebase from gitDir.
+// Returns nil when no rebase is in progress.
+func readRebaseProgress(gitDir string) *RebaseProgress {
+	read := func(dir, name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	atoi := func(s string) int { n, _ := strconv.Atoi(s); return n }
+
+	if dir := filepath.Join(gitDir, "rebase-merge"); isDir(dir) {
+		return &RebaseProgress{
+			HeadName: read(dir, "head-name"),
+			Onto:     read(dir, "onto"),
+			Stopped:  read(dir, "stopped-sha"),
+			Step:     atoi(read(dir, "msgnum")),
+			Total:    atoi(read(dir, "end")),
+			Done:     ParseRebaseTodo(read(dir, "done")),
+			Todo:     ParseRebaseTodo(read(dir, "git-rebase-todo")),
+		}
+	}
+	if dir := filepath.Join(gitDir, "rebase-apply"); isDir(dir) {
+		return &RebaseProgress{
+			HeadName: read(dir, "head-name"),
+			Onto:     read(dir, "onto"),
+			Step:     atoi(read(dir, "next")),
+			Total:    atoi(read(dir, "last")),
+		}
+	}
+	return nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// shellQuote single-quotes s for the POSIX shell git uses to run editors
+// (Git for Windows ships one too).
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFormatRebaseTodo(t *testing.T) {
+	gitDir := t.TempDir()
+	msgPath := func(i int) string {
+		return shellQuote(filepath.Join(gitDir, fmt.Sprintf("zgv-reword-%d.msg", i)))
+	}
+	tests := []struct {
+		name string
+		todo []RebaseTodoItem
+		want string
+	}{
+		{"actions", []RebaseTodoItem{
+			{Action: RebasePick, Hash: "aaa", Subject: "first"},
+			{Action: RebaseSquash, Hash: "bbb", Subject: "second"},
+			{Action: RebaseFixup, Hash: "ccc", Subject: "third"},
+			{Action: RebaseEdit, Hash: "ddd", Subject: "fourth"},
+			{Action: RebaseDrop, Hash: "eee", Subject: "fifth"},
+		}, "pick aaa first\nsquash bbb second\nfixup ccc third\nedit ddd fourth\ndrop eee fifth\n"},
+		{"exec", []RebaseTodoItem{
+			{Action: RebasePick, Hash: "aaa", Subject: "first"},
+			{Action: RebaseExec, Command: "make test"},
+		}, "pick aaa first\nexec make test\n"},
+		{"reword without a message is left to git", []RebaseTodoItem{
+			{Action: RebaseReword, Hash: "aaa", Subject: "first"},
+		}, "reword aaa first\n"},
+		{"reword with a message amends from a file", []RebaseTodoItem{
+			{Action: RebasePick, Hash: "aaa", Subject: "first"},
+			{Action: RebaseReword, Hash: "bbb", Subject: "second", Message: "Second\n\nbody"},
+		}, "pick aaa first\npick bbb second\n" +
+			"exec git commit --amend --only --allow-empty --no-verify -F " + msgPath(1) + "\n"},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatRebaseTodo(tt.todo, gitDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatRebaseTodo =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	msg, err := os.ReadFile(filepath.Join(gitDir, "zgv-reword-1.msg"))
+	if err != nil || string(msg) != "Second\n\nbody\n" {
+		t.Errorf("reword message file = %q, %v", msg, err)
+	}
+}
+
+func TestFormatRebaseTodoRoundTrip(t *testing.T) {
+	todo := []RebaseTodoItem{
+		{Action: RebasePick, Hash: "aaa", Subject: "first"},
+		{Action: RebaseReword, Hash: "bbb", Subject: "second"},
+		{Action: RebaseFixup, Hash: "ccc", Subject: "fix: third"},
+		{Action: RebaseExec, Command: "go test ./..."},
+	}
+	out, err := FormatRebaseTodo(todo, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ParseRebaseTodo(out); !reflect.DeepEqual(got, todo) {
+		t.Errorf("ParseRebaseTodo(FormatRebaseTodo(todo)) = %+v, want %+v", got, todo)
+	}
+}
+
+func TestParseRebaseTodo(t *testing.T) {
+	in := "# comment\n\np aaa first\nr bbb second\nf -C ccc third\nx make\nlabel onto\nbreak\ns ddd # fourth\n"
+	want := []RebaseTodoItem{
+		{Action: RebasePick, Hash: "aaa", Subject: "first"},
+		{Action: RebaseReword, Hash: "bbb", Subject: "second"},
+		{Action: RebaseFixup, Hash: "ccc", Subject: "third"},
+		{Action: RebaseExec, Command: "make"},
+		{Action: RebaseSquash, Hash: "ddd", Subject: "fourth"},
+	}
+	if got := ParseRebaseTodo(in); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRebaseTodo = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateRebaseTodo(t *testing.T) {
+	tests := []struct {
+		name    string
+		todo    []RebaseTodoItem
+		wantErr bool
+	}{
+		{"pick then squash", []RebaseTodoItem{{Action: RebasePick}, {Action: RebaseSquash}}, false},
+		{"squash first", []RebaseTodoItem{{Action: RebaseSquash}, {Action: RebasePick}}, true},
+		{"fixup after a drop only", []RebaseTodoItem{{Action: RebaseDrop}, {Action: RebaseFixup}}, true},
+		{"empty exec", []RebaseTodoItem{{Action: RebasePick}, {Action: RebaseExec, Command: " "}}, true},
+		{"edit then fixup", []RebaseTodoItem{{Action: RebaseEdit}, {Action: RebaseFixup}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRebaseTodo(tt.todo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRebaseTodo = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTodo) {
+				t.Errorf("error %v is not ErrInvalidTodo", err)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package git
 
+import "context"
+
 // Service defines the contract for all Git operations.
 // Every TUI view depends on this interface, never on exec.Command directly.
 // This makes the application testable via mock implementations.
@@ -11,8 +13,12 @@ type Service interface {
 	IsClean() (bool, error)
 	IsMerging() bool
 	IsRebasing() bool
+	IsCherryPicking() bool
+	IsReverting() bool
 	AheadBehind() (ahead, behind int, err error)
+	BranchesAheadBehind(base string) (map[string][2]int, error)
 	Upstream() string
+	RefHash(ref string) string
 
 	// ── Status & staging ─────────────────────────────────────────────
 	Status() (*StatusResult, error)
@@ -21,25 +27,52 @@ type Service interface {
 	Unstage(paths ...string) error
 	UnstageAll() error
 	Discard(paths ...string) error
+	StagePatch(patch string) error
+	UnstagePatch(patch string) error
+	DiscardPatch(patch string) error
 
 	// ── Commits ──────────────────────────────────────────────────────
-	Commit(message string) error
-	CommitAmend(message string) error
+	Commit(message string, opts CommitOptions) error
+	CommitAmend(message string, opts CommitOptions) error
+	CommitSigning() (Signing, error)
+	CommitFixup(target string, squash bool, message string) error
+	Reword(hash, message string) error
+	CommitTemplate() (string, error)
 	Log(limit int, args ...string) ([]Commit, error)
 	LogGraph(limit int) ([]GraphEntry, error)
-	Show(hash string) (*Commit, string, error)
+	CommitFiles(hash string) ([]DiffFileStat, error)
+	CommitFile(hash, path, oldPath string) (string, error)
+	Reflog(ref string, limit int) ([]ReflogEntry, error)
+	Reset(ref string, mode ResetMode) error
 
 	// ── Diff ─────────────────────────────────────────────────────────
 	Diff(staged bool, path string) (string, error)
 	DiffRange(from, to string) (string, error)
+	DiffRangeStat(from, to string) (string, error)
+	DiffRangeFiles(rng string) ([]DiffFileStat, error)
+	DiffRangeFile(rng, path, oldPath string) (string, error)
+	DiffStat(staged bool, path string) (string, error)
+	DiffFiles(staged bool) ([]DiffFileStat, error)
+	DiffFile(staged bool, path, oldPath string) (string, error)
 
 	// ── Branches ─────────────────────────────────────────────────────
 	Branches() ([]Branch, error)
-	CreateBranch(name string) error
+	CreateBranch(name, start string) error
 	SwitchBranch(name string) error
+	CheckoutDetached(rev string) error
+	CheckoutTracking(name, remoteBranch string) error
 	DeleteBranch(name string, force bool) error
 	MergeBranch(name string) error
 	RenameBranch(oldName, newName string) error
+	SetUpstream(branch, upstream string) error
+	UnsetUpstream(branch string) error
+
+	// ── Tags ─────────────────────────────────────────────────────────
+	Tags() ([]Tag, error)
+	CreateTag(name, ref, message string, annotated, signed bool) error
+	DeleteTag(name string) error
+	PushTag(ctx context.Context, remote, name string, progress ProgressFunc) error
+	DeleteRemoteTag(ctx context.Context, remote, name string, progress ProgressFunc) error
 
 	// ── Stash ────────────────────────────────────────────────────────
 	StashList() ([]StashEntry, error)
@@ -48,12 +81,20 @@ type Service interface {
 	StashApply(index int) error
 	StashDrop(index int) error
 	StashShow(index int) (string, error)
+	StashStat(index int) (string, error)
 
 	// ── Remotes ──────────────────────────────────────────────────────
 	Remotes() ([]Remote, error)
-	Fetch(remote string) error
-	Pull(remote, branch string) error
-	Push(remote, branch string, force bool) error
+	RemoteBranches(remote string) ([]Branch, error)
+	Fetch(ctx context.Context, remote string, progress ProgressFunc) error
+	Pull(ctx context.Context, remote, branch string, opts PullOptions, progress ProgressFunc) error
+	PullDefault() PullMode
+	Push(ctx context.Context, opts PushOptions, progress ProgressFunc) error
+	AddRemote(name, url string) error
+	RemoveRemote(name string) error
+	RenameRemote(oldName, newName string) error
+	SetRemoteURL(name, url string, push bool) error
+	PruneRemote(ctx context.Context, name string) error
 
 	// ── Worktrees ────────────────────────────────────────────────────
 	WorktreeList() ([]Worktree, error)
@@ -61,9 +102,19 @@ type Service interface {
 	WorktreeRemove(path string) error
 
 	// ── Rebase ───────────────────────────────────────────────────────
-	RebaseInteractive(onto string) error
+	RebaseInteractive(onto string, todo []RebaseTodoItem) error
+	RebaseAutosquash(onto string) error
 	RebaseContinue() error
+	RebaseSkip() error
 	RebaseAbort() error
+	RebaseProgress() (*RebaseProgress, error)
+
+	// ── Cherry-pick & revert ─────────────────────────────────────────
+	CherryPick(hashes []string, opts PickOptions) error
+	Revert(hashes []string, opts PickOptions) error
+	SequencerContinue() error
+	SequencerSkip() error
+	SequencerAbort() error
 
 	// ── Bisect ───────────────────────────────────────────────────────
 	BisectStart(bad, good string) error
@@ -74,5 +125,13 @@ type Service interface {
 
 	// ── Conflict resolution ──────────────────────────────────────────
 	ConflictFiles() ([]string, error)
+	ConflictDetails() ([]ConflictFile, error)
+	ConflictContent(path string) (string, error)
 	MarkResolved(path string) error
+	ResolveConflict(path, content string) error
+	ResolveWithSide(path string, side ConflictSide) error
+
+	// ── Undo journal ─────────────────────────────────────────────────
+	Undo() (string, error)
+	Redo() (string, error)
 }
@@ -1,6 +1,10 @@
 package git
 
-import "time"
+import (
+	"fmt"
+	"io"
+	"time"
+)
 
 // StatusCode represents a single-character Git status indicator.
 type StatusCode byte
@@ -103,12 +107,90 @@ type Commit struct {
 	Refs        []Ref
 }
 
+// CommitOptions tune Commit and CommitAmend.
+type CommitOptions struct {
+	SignOff  bool // --signoff: add a Signed-off-by trailer
+	Sign     bool // -S, or --no-gpg-sign when false, overriding commit.gpgsign
+	NoVerify bool // --no-verify: skip the pre-commit and commit-msg hooks
+
+	// Output receives git's, the hooks' and the signer's output as it is
+	// written; nil discards it. Stdin, when set, lets them prompt (for a
+	// passphrase, say) instead of reading end-of-file.
+	Output io.Writer
+	Stdin  io.Reader
+}
+
+// PushOptions say what Push sends where.
+type PushOptions struct {
+	Remote string
+	Branch string // local branch to push
+	Target string // branch name on the remote; "" means Branch
+
+	SetUpstream bool // --set-upstream: make Target Branch's upstream
+	Tags        bool // --tags: push all tags as well
+	NoVerify    bool // --no-verify: skip the pre-push hook
+
+	// ForceWithLease overwrites Target only if it is still at Expect (a
+	// commit hash). An empty Expect leaves the check to git, which
+	// compares with the remote-tracking branch.
+	ForceWithLease bool
+	Expect         string
+}
+
+// Signing is the repository's commit signing setup.
+type Signing struct {
+	Enabled bool   // commit.gpgsign
+	Format  string // gpg.format: openpgp, x509 or ssh
+	Key     string // user.signingkey; empty lets the signer pick
+}
+
 // GraphEntry pairs a commit with its ASCII graph decoration.
 type GraphEntry struct {
 	Graph  string  // e.g. "* ", "| * "
 	Commit *Commit // nil for graph-only lines (merge lines, etc.)
 }
 
+// ReflogEntry is one movement of a ref, as recorded in its reflog.
+type ReflogEntry struct {
+	Ref       string // whose reflog this is, e.g. "HEAD" or "main"
+	Index     int    // n in ref@{n}; 0 is the newest
+	Hash      string // where the ref moved to
+	ShortHash string
+	Op        string // kind of operation: "commit", "checkout", "rebase", "reset", ...
+	Action    string // the full action, e.g. "commit (amend)" or "rebase (pick)"
+	Message   string // what git recorded after the action
+	Subject   string // subject of the commit at Hash
+	RelDate   string // when the ref moved, e.g. "2 hours ago"
+}
+
+// Selector returns the entry's reflog selector, e.g. "HEAD@{3}".
+func (e ReflogEntry) Selector() string { return fmt.Sprintf("%s@{%d}", e.Ref, e.Index) }
+
+// ResetMode is a `git reset` mode.
+type ResetMode string
+
+const (
+	ResetSoft  ResetMode = "soft"  // move the branch; keep the index and files
+	ResetMixed ResetMode = "mixed" // also reset the index
+	ResetHard  ResetMode = "hard"  // also reset the files, discarding changes
+	ResetKeep  ResetMode = "keep"  // like hard, but refuse to touch changed files
+)
+
+// PullMode is how Pull integrates the fetched branch.
+type PullMode string
+
+const (
+	PullMerge  PullMode = "merge"   // --no-rebase: merge, fast-forwarding when possible
+	PullRebase PullMode = "rebase"  // --rebase: replay local commits on top
+	PullFFOnly PullMode = "ff-only" // --ff-only: refuse unless a fast-forward
+)
+
+// PullOptions tune Pull.
+type PullOptions struct {
+	Mode      PullMode
+	Autostash bool // --autostash: stash local changes around the pull
+}
+
 // Branch represents a local or remote branch.
 type Branch struct {
 	Name      string
@@ -117,8 +199,25 @@ type Branch struct {
 	Upstream  string
 	Hash      string
 	Subject   string
-	Ahead     int
-	Behind    int
+	Ahead     int // commits Upstream doesn't have
+	Behind    int // commits on Upstream the branch doesn't have
+
+	// UpstreamGone is set when Upstream was deleted on the remote (and
+	// pruned locally).
+	UpstreamGone bool
+}
+
+// Tag represents a tag. Tagger is empty for lightweight tags, whose Date
+// and Message come from the tagged commit instead.
+type Tag struct {
+	Name      string
+	Hash      string // the tagged commit
+	ShortHash string
+	Annotated bool
+	Tagger    string
+	Date      time.Time
+	RelDate   string
+	Message   string // first line of the tag message (or commit subject)
 }
 
 // StashEntry represents a single stash entry.
@@ -135,6 +234,19 @@ type Remote struct {
 	PushURL  string
 }
 
+// Progress is one progress report from a network command, e.g.
+// "Receiving objects: 45% (450/1000), 1.20 MiB | 600.00 KiB/s".
+type Progress struct {
+	Phase   string // "Counting objects", "Receiving objects", ...
+	Percent int    // -1 when the phase has no known total
+	Current int
+	Total   int
+	Rate    string // throughput, when git reports it
+}
+
+// ProgressFunc receives progress as a network command runs.
+type ProgressFunc func(Progress)
+
 // Worktree represents a linked working tree.
 type Worktree struct {
 	Path   string
@@ -142,3 +254,215 @@ type Worktree struct {
 	Branch string
 	Bare   bool
 }
+
+// DiffLineKind classifies a single line inside a diff hunk.
+type DiffLineKind byte
+
+// Diff line kinds, using the unified-diff prefix characters.
+const (
+	DiffLineContext   DiffLineKind = ' '
+	DiffLineAdded     DiffLineKind = '+'
+	DiffLineRemoved   DiffLineKind = '-'
+	DiffLineNoNewline DiffLineKind = '\\' // "\ No newline at end of file"
+)
+
+// DiffLine is one line of a hunk. OldLine/NewLine are 0 when the line
+// does not exist on that side.
+type DiffLine struct {
+	Kind    DiffLineKind
+	Content string // without the leading prefix character
+	OldLine int
+	NewLine int
+}
+
+// IsChange reports whether the line is an addition or a removal.
+func (l DiffLine) IsChange() bool {
+	return l.Kind == DiffLineAdded || l.Kind == DiffLineRemoved
+}
+
+// Hunk is a single @@ section of a file diff.
+type Hunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	Section  string // trailing text after the second "@@" (function context)
+	Lines    []DiffLine
+}
+
+// FileDiff is the parsed diff of one file.
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Header  []string // raw header lines: "diff --git", "index", "---", "+++", ...
+	Hunks   []Hunk
+	Binary  bool
+}
+
+// Path returns the most meaningful path for display (new path unless the
+// file was deleted).
+func (f *FileDiff) Path() string {
+	if f.NewPath != "" && f.NewPath != "/dev/null" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// DiffFileStat is one file's line counts in a diff (`git diff --numstat`).
+type DiffFileStat struct {
+	Path    string
+	OldPath string // set when the file was renamed or copied
+	Added   int
+	Deleted int
+	Binary  bool // git reports no line counts for binary files
+}
+
+// RebaseAction is a git-rebase-todo command.
+type RebaseAction string
+
+// Rebase todo commands supported by the todo editor.
+const (
+	RebasePick   RebaseAction = "pick"
+	RebaseReword RebaseAction = "reword"
+	RebaseEdit   RebaseAction = "edit"
+	RebaseSquash RebaseAction = "squash"
+	RebaseFixup  RebaseAction = "fixup"
+	RebaseDrop   RebaseAction = "drop"
+	RebaseExec   RebaseAction = "exec"
+)
+
+// RebaseTodoItem is one line of a rebase todo list.
+type RebaseTodoItem struct {
+	Action  RebaseAction
+	Hash    string // empty for exec
+	Subject string // display only
+	Command string // exec only
+	Message string // reword only: replacement commit message
+}
+
+// RebaseProgress is the live state of a stopped interactive rebase, read
+// from .git/rebase-merge.
+type RebaseProgress struct {
+	HeadName string // branch being rebased, e.g. "refs/heads/feature"
+	Onto     string // commit being rebased onto
+	Stopped  string // commit the rebase stopped at, if any
+	Step     int    // msgnum: 1-based index of the current step
+	Total    int    // end: number of steps
+	Done     []RebaseTodoItem
+	Todo     []RebaseTodoItem
+}
+
+// PickOptions tune a cherry-pick or revert.
+type PickOptions struct {
+	RecordOrigin bool // -x: note the original commit (cherry-pick only)
+	NoCommit     bool // --no-commit: apply the changes without committing
+	Mainline     int  // -m: parent to diff merges against (0 for none)
+}
+
+// ConflictSide selects one side of a conflicted merge.
+type ConflictSide int
+
+// Sides of a conflict, matching the index stages git records.
+const (
+	ConflictOurs   ConflictSide = 2
+	ConflictTheirs ConflictSide = 3
+)
+
+// ConflictKind classifies an unmerged path by which index stages exist.
+type ConflictKind int
+
+// Unmerged path kinds, as reported by `git status`.
+const (
+	ConflictBothModified ConflictKind = iota
+	ConflictBothAdded
+	ConflictDeletedByUs
+	ConflictDeletedByThem
+	ConflictAddedByUs
+	ConflictAddedByThem
+	ConflictBothDeleted
+)
+
+// String returns a short, human-readable description of the conflict.
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictBothAdded:
+		return "both added"
+	case ConflictDeletedByUs:
+		return "deleted by us"
+	case ConflictDeletedByThem:
+		return "deleted by them"
+	case ConflictAddedByUs:
+		return "added by us"
+	case ConflictAddedByThem:
+		return "added by them"
+	case ConflictBothDeleted:
+		return "both deleted"
+	default:
+		return "both modified"
+	}
+}
+
+// ConflictFile is an unmerged path with enough detail to pick a
+// resolution strategy.
+type ConflictFile struct {
+	Path   string
+	Kind   ConflictKind
+	Binary bool // working copy has no text markers to edit
+}
+
+// HasSide reports whether the given side still has a version of the file.
+func (f ConflictFile) HasSide(side ConflictSide) bool {
+	switch f.Kind {
+	case ConflictBothDeleted:
+		return false
+	case ConflictDeletedByUs, ConflictAddedByThem:
+		return side == ConflictTheirs
+	case ConflictDeletedByThem, ConflictAddedByUs:
+		return side == ConflictOurs
+	}
+	return true
+}
+
+// IsDeleteModify reports whether one side deleted the file.
+func (f ConflictFile) IsDeleteModify() bool {
+	return f.Kind == ConflictDeletedByUs || f.Kind == ConflictDeletedByThem
+}
+
+// ConflictChoice is how a single conflict block is resolved.
+type ConflictChoice int
+
+// Resolutions for a conflict block.
+const (
+	ChoiceUnresolved ConflictChoice = iota
+	ChoiceOurs
+	ChoiceTheirs
+	ChoiceBoth // ours followed by theirs
+	ChoiceBase
+)
+
+// ConflictBlock is one <<<<<<< … >>>>>>> region. Line slices keep their
+// line terminators so a resolution reproduces the file byte-for-byte.
+type ConflictBlock struct {
+	Ours        []string
+	Base        []string
+	Theirs      []string
+	HasBase     bool     // diff3/zdiff3 style with a ||||||| section
+	Markers     []string // the raw marker lines, in order
+	OursLabel   string
+	BaseLabel   string
+	TheirsLabel string
+}
+
+// ConflictSegment is either plain text shared by both sides or a
+// conflict block.
+type ConflictSegment struct {
+	Text     []string
+	Conflict *ConflictBlock
+}
+
+// ConflictDoc is a conflicted file split into segments.
+type ConflictDoc struct {
+	Segments []ConflictSegment
+	// Blocks indexes the conflict segments in file order.
+	Blocks []*ConflictBlock
+}
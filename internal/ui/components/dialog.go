@@ -20,6 +20,7 @@ type DialogResult struct {
 	Confirmed bool
 	Value     string
 	Tag       string // arbitrary tag to identify which dialog this was
+	Remember  bool   // "don't ask again" was ticked (confirm dialogs only)
 }
 
 // Dialog is a modal confirmation or input dialog.
@@ -32,6 +33,10 @@ type Dialog struct {
 	focused int // 0 = yes/input, 1 = no
 	styles  ui.Styles
 	visible bool
+
+	// Optional "don't ask again" checkbox for confirm dialogs.
+	rememberLabel string
+	remember      bool
 }
 
 // NewConfirmDialog creates a Yes/No confirmation dialog.
@@ -46,6 +51,13 @@ func NewConfirmDialog(styles ui.Styles, title, message, tag string) Dialog {
 	}
 }
 
+// WithRemember adds a checkbox (toggled with space) that is reported back
+// as DialogResult.Remember, e.g. "Don't ask again this session".
+func (d Dialog) WithRemember(label string) Dialog {
+	d.rememberLabel = label
+	return d
+}
+
 // NewInputDialog creates a text input dialog.
 func NewInputDialog(styles ui.Styles, title, placeholder, tag string) Dialog {
 	ti := textinput.New()
@@ -63,6 +75,26 @@ func NewInputDialog(styles ui.Styles, title, placeholder, tag string) Dialog {
 	}
 }
 
+// WithMessage shows message between an input dialog's title and its input.
+func (d Dialog) WithMessage(message string) Dialog {
+	d.Message = message
+	return d
+}
+
+// WithValue prefills an input dialog, e.g. with the value being edited.
+func (d Dialog) WithValue(value string) Dialog {
+	d.input.SetValue(value)
+	d.input.CursorEnd()
+	return d
+}
+
+// WithMask hides what is typed into an input dialog, for passwords.
+func (d Dialog) WithMask() Dialog {
+	d.input.EchoMode = textinput.EchoPassword
+	d.input.EchoCharacter = '•'
+	return d
+}
+
 // Visible returns whether the dialog is showing.
 func (d Dialog) Visible() bool { return d.visible }
 
@@ -85,14 +117,23 @@ func (d Dialog) Update(msg tea.Msg) (Dialog, tea.Cmd) {
 					return DialogResult{Confirmed: true, Value: d.input.Value(), Tag: d.Tag}
 				}
 			}
-			return d, func() tea.Msg {
-				return DialogResult{Confirmed: d.focused == 0, Tag: d.Tag}
-			}
+			return d, d.confirmResult(d.focused == 0)
 
 		case "tab", "left", "right", "h", "l":
 			if d.Kind == DialogConfirm {
 				d.focused = 1 - d.focused
 			}
+
+		case "y", "n":
+			if d.Kind == DialogConfirm {
+				d.visible = false
+				return d, d.confirmResult(keyMsg.String() == "y")
+			}
+
+		case " ":
+			if d.Kind == DialogConfirm && d.rememberLabel != "" {
+				d.remember = !d.remember
+			}
 		}
 	}
 
@@ -104,6 +145,12 @@ func (d Dialog) Update(msg tea.Msg) (Dialog, tea.Cmd) {
 	return d, nil
 }
 
+func (d Dialog) confirmResult(confirmed bool) tea.Cmd {
+	return func() tea.Msg {
+		return DialogResult{Confirmed: confirmed, Tag: d.Tag, Remember: confirmed && d.remember}
+	}
+}
+
 // View renders the dialog.
 func (d Dialog) View() string {
 	if !d.visible {
@@ -129,8 +176,20 @@ func (d Dialog) View() string {
 		}
 		buttons := lipgloss.JoinHorizontal(lipgloss.Top, yes, "  ", no)
 		content = title + "\n\n" + message + "\n\n" + buttons
+		if d.rememberLabel != "" {
+			box := "[ ] "
+			if d.remember {
+				box = "[x] "
+			}
+			content += "\n\n" + lipgloss.NewStyle().Foreground(t.TextMuted).
+				Render(box+d.rememberLabel+" (space)")
+		}
 	} else {
-		content = title + "\n\n" + d.input.View()
+		content = title + "\n\n"
+		if d.Message != "" {
+			content += lipgloss.NewStyle().Foreground(t.TextMuted).Render(d.Message) + "\n\n"
+		}
+		content += d.input.View()
 	}
 
 	return lipgloss.NewStyle().
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -31,7 +32,7 @@ func RenderHelp(styles ui.Styles, title string, sections map[string][]HelpEntry,
 	descStyle := lipgloss.NewStyle().Foreground(t.Text)
 
 	// Deterministic order from a predefined list.
-	order := []string{"Navigation", "Tabs", "Status", "Staging", "Diff", "Branches", "Stash", "Remotes", "Rebase", "Bisect", "General"}
+	order := []string{"Navigation", "Tabs", "Status", "Staging", "Log", "Diff", "Branches", "Stash", "Remotes", "Tags", "Rebase", "Conflicts", "Worktrees", "Bisect", "Reflog", "General"}
 	for _, section := range order {
 		entries, ok := sections[section]
 		if !ok || len(entries) == 0 {
@@ -57,38 +58,43 @@ func RenderHelp(styles ui.Styles, title string, sections map[string][]HelpEntry,
 	return ui.PlaceCentre(width, height, overlay)
 }
 
-// GlobalHelpEntries returns the help entries for global keybindings.
-func GlobalHelpEntries() map[string][]HelpEntry {
+// GlobalHelpEntries returns the help entries for the global and
+// navigation keybindings, showing the keys as currently bound.
+func GlobalHelpEntries(global, nav keys.Set) map[string][]HelpEntry {
 	return map[string][]HelpEntry{
 		"Navigation": {
-			{Key: "j / ↓", Desc: "Move down"},
-			{Key: "k / ↑", Desc: "Move up"},
-			{Key: "g / Home", Desc: "Go to top"},
-			{Key: "G / End", Desc: "Go to bottom"},
-			{Key: "pgup / ctrl+u", Desc: "Page up"},
-			{Key: "pgdn / ctrl+d", Desc: "Page down"},
-			{Key: "enter", Desc: "Confirm / expand"},
-			{Key: "esc", Desc: "Back / cancel"},
+			{Key: nav.Help("down"), Desc: "Move down"},
+			{Key: nav.Help("up"), Desc: "Move up"},
+			{Key: nav.Help("top"), Desc: "Go to top"},
+			{Key: nav.Help("bottom"), Desc: "Go to bottom"},
+			{Key: nav.Help("page_up"), Desc: "Page up"},
+			{Key: nav.Help("page_down"), Desc: "Page down"},
+			{Key: nav.Help("back"), Desc: "Back / cancel"},
 		},
 		"Tabs": {
-			{Key: "tab", Desc: "Next tab"},
-			{Key: "shift+tab", Desc: "Previous tab"},
+			{Key: global.Help("next_tab"), Desc: "Next tab"},
+			{Key: global.Help("prev_tab"), Desc: "Previous tab"},
 			{Key: "scroll on bar", Desc: "Cycle tabs (mouse)"},
-			{Key: "alt+s", Desc: "Status"},
-			{Key: "alt+d", Desc: "Diff"},
-			{Key: "alt+l", Desc: "Log"},
-			{Key: "alt+b", Desc: "Branches"},
-			{Key: "alt+m", Desc: "Remotes"},
-			{Key: "alt+t", Desc: "Stash"},
-			{Key: "alt+e", Desc: "Rebase"},
-			{Key: "alt+x", Desc: "Conflicts"},
-			{Key: "alt+w", Desc: "Worktrees"},
-			{Key: "alt+i", Desc: "Bisect"},
+			{Key: global.Help("tab_status"), Desc: "Status"},
+			{Key: global.Help("tab_diff"), Desc: "Diff"},
+			{Key: global.Help("tab_log"), Desc: "Log"},
+			{Key: global.Help("tab_branches"), Desc: "Branches"},
+			{Key: global.Help("tab_remotes"), Desc: "Remotes"},
+			{Key: global.Help("tab_stash"), Desc: "Stash"},
+			{Key: global.Help("tab_tags"), Desc: "Tags"},
+			{Key: global.Help("tab_rebase"), Desc: "Rebase"},
+			{Key: global.Help("tab_conflicts"), Desc: "Conflicts"},
+			{Key: global.Help("tab_worktrees"), Desc: "Worktrees"},
+			{Key: global.Help("tab_bisect"), Desc: "Bisect"},
+			{Key: global.Help("tab_reflog"), Desc: "Reflog"},
 		},
 		"General": {
-			{Key: "r", Desc: "Refresh data"},
-			{Key: "?", Desc: "Toggle this help"},
-			{Key: "q / ctrl+c", Desc: "Quit"},
+			{Key: global.Help("refresh"), Desc: "Refresh data"},
+			{Key: global.Help("undo"), Desc: "Undo last change"},
+			{Key: global.Help("redo"), Desc: "Redo"},
+			{Key: global.Help("cancel_operation"), Desc: "Cancel the running fetch / pull / push"},
+			{Key: global.Help("help"), Desc: "Toggle this help"},
+			{Key: global.Help("quit"), Desc: "Quit"},
 		},
 	}
 }
@@ -30,10 +30,19 @@ func isGitDiffHeaderSBS(line string) bool {
 }
 
 // RenderSideBySideDiff renders a unified diff in side-by-side format with
-// line numbers, gutter indicators, and clean styling.
+// line numbers, gutter indicators, and clean styling. Removed lines face
+// the added lines that replace them, with the words that changed
+// emphasised, and code is coloured by language when styles.Syntax is set.
 func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string {
+	out, _ := RenderSideBySideDiffHunks(styles, diff, totalWidth)
+	return out
+}
+
+// RenderSideBySideDiffHunks is RenderSideBySideDiff that also returns the
+// output line of each hunk, for jumping between hunks.
+func RenderSideBySideDiffHunks(styles ui.Styles, diff string, totalWidth int) (string, []int) {
 	if diff == "" {
-		return styles.Muted.Render("No diff content")
+		return styles.Muted.Render("No diff content"), nil
 	}
 
 	const lnW = 4
@@ -51,12 +60,22 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 	}
 
 	lines := strings.Split(diff, "\n")
+	emph := ui.DiffEmphasis(lines)
+	lang := ""
 	var leftLines, rightLines []string
+	var hunks []int
+	// pair is the row where the next added line goes, next to a removed
+	// line of the run just before it; -1 outside such a run.
+	pair, afterAdded := -1, false
 
 	inHeader := true
 	oldLine, newLine := 0, 0
 
-	for _, line := range lines {
+	for i, line := range lines {
+		if line == "" || !strings.ContainsRune("-+\\", rune(line[0])) {
+			pair = -1
+		}
+
 		// Section titles.
 		if strings.HasPrefix(line, "===") {
 			styled := styles.Title.Render(truncateTo(line, panelW))
@@ -68,6 +87,7 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 		// Git metadata header.
 		if strings.HasPrefix(line, "diff --git ") {
 			inHeader = true
+			lang = styles.Syntax.DiffLanguage(line)
 			continue
 		}
 
@@ -104,6 +124,7 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 					}
 				}
 			}
+			hunks = append(hunks, len(leftLines))
 			spacer := styles.DiffSeparator.Render(lnBlank + "│ ···")
 			leftLines = append(leftLines, spacer)
 			rightLines = append(rightLines, spacer)
@@ -116,7 +137,10 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 			ln := fmt.Sprintf(lnFmt, oldLine)
 			left := styles.DiffRemovedLineNum.Render(ln) +
 				styles.DiffRemovedGutter.Render("│") +
-				styles.DiffRemoved.Render(padTo(truncateTo(" "+content, contentW), contentW))
+				codeCell(content, styles.Syntax.Line(lang, content), emph[i], contentW, styles.DiffRemoved, styles.DiffRemovedEmph)
+			if pair < 0 || afterAdded {
+				pair, afterAdded = len(leftLines), false
+			}
 			leftLines = append(leftLines, left)
 			rightLines = append(rightLines, padTo("", panelW))
 			oldLine++
@@ -126,9 +150,16 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 			ln := fmt.Sprintf(lnFmt, newLine)
 			right := styles.DiffAddedLineNum.Render(ln) +
 				styles.DiffAddedGutter.Render("│") +
-				styles.DiffAdded.Render(padTo(truncateTo(" "+content, contentW), contentW))
-			leftLines = append(leftLines, padTo("", panelW))
-			rightLines = append(rightLines, right)
+				codeCell(content, styles.Syntax.Line(lang, content), emph[i], contentW, styles.DiffAdded, styles.DiffAddedEmph)
+			afterAdded = true
+			if pair >= 0 && pair < len(rightLines) {
+				rightLines[pair] = right
+				pair++
+			} else {
+				pair = -1
+				leftLines = append(leftLines, padTo("", panelW))
+				rightLines = append(rightLines, right)
+			}
 			newLine++
 
 		default:
@@ -146,10 +177,14 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 				newLine++
 			}
 			sep := lipgloss.NewStyle().Foreground(styles.Theme.Border).Render("│")
-			left := styles.DiffContextLineNum.Render(oldLn) + sep +
-				styles.DiffContext.Render(padTo(truncateTo(" "+line, contentW), contentW))
-			right := styles.DiffContextLineNum.Render(newLn) + sep +
-				styles.DiffContext.Render(padTo(truncateTo(" "+line, contentW), contentW))
+			content := strings.TrimPrefix(line, " ")
+			var syntax []ui.Span
+			if !strings.HasPrefix(line, "\\") { // "\ No newline at end of file"
+				syntax = styles.Syntax.Line(lang, content)
+			}
+			cell := codeCell(content, syntax, nil, contentW, styles.DiffContext, styles.DiffContext)
+			left := styles.DiffContextLineNum.Render(oldLn) + sep + cell
+			right := styles.DiffContextLineNum.Render(newLn) + sep + cell
 			leftLines = append(leftLines, left)
 			rightLines = append(rightLines, right)
 		}
@@ -172,7 +207,67 @@ func RenderSideBySideDiff(styles ui.Styles, diff string, totalWidth int) string
 		b.WriteString(l + centerSep + r + "\n")
 	}
 
-	return b.String()
+	return b.String(), hunks
+}
+
+// codeCell renders a line's content as a cell of width columns, with its
+// syntax spans coloured and ranges of it in emph.
+func codeCell(content string, syntax []ui.Span, ranges []ui.Range, width int, base, emph lipgloss.Style) string {
+	// lipgloss widens tabs when rendering, after the cell was measured.
+	if strings.Contains(content, "\t") {
+		content, syntax, ranges = expandTabs(content, syntax, ranges)
+	}
+	cell := truncateTo(" "+content, width)
+	// Colours and emphasis stop where the text was cut for the "…".
+	visible := len(cell)
+	if cell != " "+content {
+		visible -= len("…")
+	}
+	shift := func(r ui.Range) (ui.Range, bool) {
+		return ui.Range{Start: r.Start + 1, End: min(r.End+1, visible)}, r.Start+1 < visible
+	}
+	spans := make([]ui.Span, 0, len(syntax))
+	for _, sp := range syntax {
+		if r, ok := shift(sp.Range); ok {
+			spans = append(spans, ui.Span{Range: r, Color: sp.Color})
+		}
+	}
+	shifted := make([]ui.Range, 0, len(ranges))
+	for _, r := range ranges {
+		if r, ok := shift(r); ok {
+			shifted = append(shifted, r)
+		}
+	}
+	return ui.RenderDiffLine(cell, spans, shifted, base, emph) +
+		base.Render(strings.Repeat(" ", max(width-lipgloss.Width(cell), 0)))
+}
+
+// expandTabs replaces the tabs in s with four spaces, moving spans and
+// ranges to match.
+func expandTabs(s string, syntax []ui.Span, ranges []ui.Range) (string, []ui.Span, []ui.Range) {
+	var b strings.Builder
+	at := make([]int, len(s)+1) // at[i] is where byte i of s ends up
+	for i := 0; i < len(s); i++ {
+		at[i] = b.Len()
+		if s[i] == '\t' {
+			b.WriteString("    ")
+		} else {
+			b.WriteByte(s[i])
+		}
+	}
+	at[len(s)] = b.Len()
+	move := func(r ui.Range) ui.Range {
+		return ui.Range{Start: at[min(r.Start, len(s))], End: at[min(r.End, len(s))]}
+	}
+	spans := make([]ui.Span, len(syntax))
+	for i, sp := range syntax {
+		spans[i] = ui.Span{Range: move(sp.Range), Color: sp.Color}
+	}
+	moved := make([]ui.Range, len(ranges))
+	for i, r := range ranges {
+		moved[i] = move(r)
+	}
+	return b.String(), spans, moved
 }
 
 func parseHunkRangeSBS(tok string) (int, int) {
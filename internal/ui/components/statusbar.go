@@ -11,15 +11,18 @@ import (
 
 // StatusBarData carries the info displayed in the bottom status bar.
 type StatusBarData struct {
-	Branch   string
-	Ahead    int
-	Behind   int
-	Clean    bool
-	Merging  bool
-	Rebasing bool
-	Message  string // transient info/error message
-	IsError  bool
-	RepoRoot string
+	Branch        string
+	Ahead         int
+	Behind        int
+	Clean         bool
+	Merging       bool
+	Rebasing      bool
+	CherryPicking bool
+	Reverting     bool
+	Progress      string // running network operation, e.g. "push origin: Writing objects 45%"
+	Message       string // transient info/error message
+	IsError       bool
+	RepoRoot      string
 }
 
 // RenderStatusBar renders the bottom status bar with clear visual sections
@@ -55,31 +58,34 @@ func RenderStatusBar(styles ui.Styles, data StatusBarData, width int) string {
 	}
 
 	// State.
+	badge := lipgloss.NewStyle().
+		Foreground(t.TextInverse).
+		Background(t.Warning).
+		Bold(true).
+		Padding(0, 1)
 	var stateSection string
 	switch {
 	case data.Merging:
-		badge := lipgloss.NewStyle().
-			Foreground(t.TextInverse).
-			Background(t.Warning).
-			Bold(true).
-			Padding(0, 1).
-			Render("MERGING")
-		stateSection = sep + badge
+		stateSection = sep + badge.Render("MERGING")
 	case data.Rebasing:
-		badge := lipgloss.NewStyle().
-			Foreground(t.TextInverse).
-			Background(t.Warning).
-			Bold(true).
-			Padding(0, 1).
-			Render("REBASING")
-		stateSection = sep + badge
+		stateSection = sep + badge.Render("REBASING")
+	case data.CherryPicking:
+		stateSection = sep + badge.Render("CHERRY-PICKING")
+	case data.Reverting:
+		stateSection = sep + badge.Render("REVERTING")
 	case data.Clean:
 		stateSection = sep + lipgloss.NewStyle().Foreground(t.Success).Render("✓ clean")
 	default:
 		stateSection = sep + lipgloss.NewStyle().Foreground(t.Modified).Render("● modified")
 	}
 
-	left := branchSection + syncSection + stateSection
+	// Progress of a running fetch/pull/push.
+	var progressSection string
+	if data.Progress != "" {
+		progressSection = sep + lipgloss.NewStyle().Foreground(t.Info).Render("⟳ "+data.Progress)
+	}
+
+	left := branchSection + syncSection + stateSection + progressSection
 
 	// ── Right section ────────────────────────────────────────────
 
@@ -89,7 +95,10 @@ func RenderStatusBar(styles ui.Styles, data StatusBarData, width int) string {
 		if data.IsError {
 			fg = t.Error
 		}
-		right = lipgloss.NewStyle().Foreground(fg).Render(data.Message) + " "
+		// Git's multi-line errors go on one line, cut to fit.
+		msg := strings.Join(strings.Fields(data.Message), " ")
+		msg = ui.Truncate(msg, max(width-lipgloss.Width(left)-2, 10))
+		right = lipgloss.NewStyle().Foreground(fg).Render(msg) + " "
 	} else if width >= 60 && data.RepoRoot != "" {
 		repoName := filepath.Base(data.RepoRoot)
 		right = lipgloss.NewStyle().Foreground(t.TextSubtle).Render(repoName) + " "
@@ -0,0 +1,89 @@
+// Package keys resolves key presses to configured actions.
+package keys
+
+import (
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Set is the bindings of one keymap scope plus the scopes it inherits.
+// Views switch on Set.Action instead of raw key strings so every action
+// can be rebound from config.
+type Set struct {
+	actions map[string][]string // action → keys (own scope first)
+	byKey   map[string]string   // key → action (own scope first)
+}
+
+// New builds the Set for scope from km, falling back to the defaults when
+// km is nil (e.g. a Config not produced by config.Load).
+func New(km config.Keymap, scope string) Set {
+	if km == nil {
+		km = config.DefaultKeymap()
+	}
+	s := Set{actions: make(map[string][]string), byKey: make(map[string]string)}
+	scopes := []string{scope}
+	for _, ks := range config.KeyScopes {
+		if ks.Name == scope {
+			scopes = append(scopes, ks.Inherits...)
+		}
+	}
+	for _, name := range scopes {
+		for action, ks := range km[name] {
+			if _, taken := s.actions[action]; !taken {
+				s.actions[action] = ks
+			}
+			for _, k := range ks {
+				if _, taken := s.byKey[k]; !taken {
+					s.byKey[k] = action
+				}
+			}
+		}
+	}
+	return s
+}
+
+// Action returns the action bound to msg, or "" if none is.
+func (s Set) Action(msg tea.KeyMsg) string { return s.byKey[msg.String()] }
+
+// Keys returns the keys bound to action.
+func (s Set) Keys(action string) []string { return s.actions[action] }
+
+// Help renders the keys bound to action for help text, e.g. "d / enter".
+func (s Set) Help(action string) string {
+	ks := s.actions[action]
+	if len(ks) == 0 {
+		return "(unbound)"
+	}
+	names := make([]string, len(ks))
+	for i, k := range ks {
+		names[i] = config.DisplayKey(k)
+	}
+	return strings.Join(names, " / ")
+}
+
+// First returns the primary key for action, for compact hints.
+func (s Set) First(action string) string {
+	if ks := s.actions[action]; len(ks) > 0 {
+		return config.DisplayKey(ks[0])
+	}
+	return "-"
+}
+
+// Binding returns a bubbles key.Binding for action.
+func (s Set) Binding(action, desc string) key.Binding {
+	ks := s.actions[action]
+	return key.NewBinding(key.WithKeys(ks...), key.WithHelp(s.First(action), desc))
+}
+
+// Hints renders an inline hint line from action/description pairs, e.g.
+// Hints("new", "new", "delete", "delete") → "n new  D delete".
+func (s Set) Hints(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, s.First(pairs[i])+" "+pairs[i+1])
+	}
+	return strings.Join(parts, "  ")
+}
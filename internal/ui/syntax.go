@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Span colours the byte range of a line holding one syntax token.
+type Span struct {
+	Range
+	Color lipgloss.Color
+}
+
+// maxHighlightCache bounds the lines a Highlighter remembers; when full
+// it starts over rather than tracking which lines are oldest.
+const maxHighlightCache = 20000
+
+// Highlighter colours source lines by language, with the colours of a
+// Theme. Lines are lexed one at a time, since a diff shows fragments of a
+// file, so a line inside a multi-line comment or string is coloured as
+// code. Results are cached: the same lines are rendered again whenever a
+// diff is reloaded or re-laid out. A nil *Highlighter highlights nothing,
+// which is how highlighting is turned off.
+type Highlighter struct {
+	theme Theme
+
+	mu     sync.Mutex
+	lexers map[string]chroma.Lexer // by file name; nil when unknown
+	byName map[string]chroma.Lexer // by Language
+	cache  map[highlightKey][]Span
+}
+
+type highlightKey struct{ lang, line string }
+
+// NewHighlighter returns a Highlighter using t's syntax colours.
+func NewHighlighter(t Theme) *Highlighter {
+	return &Highlighter{
+		theme:  t,
+		lexers: make(map[string]chroma.Lexer),
+		byName: make(map[string]chroma.Lexer),
+		cache:  make(map[highlightKey][]Span),
+	}
+}
+
+// Language returns the language of the file at p, judged by its name, or
+// "" if it is unknown or h is nil.
+func (h *Highlighter) Language(p string) string {
+	if h == nil || p == "" || p == "/dev/null" {
+		return ""
+	}
+	name := path.Base(p)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lexer, ok := h.lexers[name]
+	if !ok {
+		// Match tries every lexer's patterns, hence the cache.
+		if lexer = lexers.Match(name); lexer != nil {
+			lexer = chroma.Coalesce(lexer)
+		}
+		h.lexers[name] = lexer
+	}
+	if lexer == nil {
+		return ""
+	}
+	lang := lexer.Config().Name
+	h.byName[lang] = lexer
+	return lang
+}
+
+// DiffLanguage returns the language of the file a `diff --git a/… b/…`
+// line introduces. Only the name after the last " b/" is used: a path
+// containing " b/" is ambiguous, but it still ends in the file's name.
+func (h *Highlighter) DiffLanguage(line string) string {
+	i := strings.LastIndex(line, " b/")
+	if i < 0 {
+		return ""
+	}
+	return h.Language(line[i+3:])
+}
+
+// Line returns the coloured tokens of line in lang (see Language). Tokens
+// the theme has no colour for, such as plain names, get no span.
+func (h *Highlighter) Line(lang, line string) []Span {
+	if h == nil || lang == "" || strings.TrimSpace(line) == "" {
+		return nil
+	}
+	key := highlightKey{lang, line}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if spans, ok := h.cache[key]; ok {
+		return spans
+	}
+	spans := h.lex(h.byName[lang], line)
+	if len(h.cache) >= maxHighlightCache {
+		h.cache = make(map[highlightKey][]Span)
+	}
+	h.cache[key] = spans
+	return spans
+}
+
+func (h *Highlighter) lex(lexer chroma.Lexer, line string) []Span {
+	if lexer == nil {
+		return nil
+	}
+	it, err := lexer.Tokenise(nil, line)
+	if err != nil {
+		return nil
+	}
+	var spans []Span
+	pos := 0
+	for tok := it(); tok != chroma.EOF && pos < len(line); tok = it() {
+		end := min(pos+len(tok.Value), len(line))
+		if c := h.color(tok.Type); c != "" {
+			spans = append(spans, Span{Range{pos, end}, c})
+		}
+		pos = end
+	}
+	return spans
+}
+
+// color maps a token type onto the theme's syntax colours.
+func (h *Highlighter) color(tt chroma.TokenType) lipgloss.Color {
+	t := h.theme
+	switch {
+	case tt == chroma.CommentPreproc, tt == chroma.CommentPreprocFile:
+		return t.SyntaxKeyword
+	case tt.InCategory(chroma.Comment):
+		return t.SyntaxComment
+	case tt == chroma.KeywordType, tt == chroma.NameClass, tt == chroma.NameBuiltin:
+		return t.SyntaxType
+	case tt.InCategory(chroma.Keyword), tt == chroma.OperatorWord:
+		return t.SyntaxKeyword
+	case tt == chroma.NameFunction, tt == chroma.NameFunctionMagic, tt == chroma.NameDecorator:
+		return t.SyntaxFunction
+	case tt.InSubCategory(chroma.LiteralString):
+		return t.SyntaxString
+	case tt.InSubCategory(chroma.LiteralNumber), tt == chroma.NameConstant, tt == chroma.KeywordConstant:
+		return t.SyntaxNumber
+	case tt.InCategory(chroma.Operator):
+		return t.SyntaxOperator
+	}
+	return ""
+}
+
+// RenderDiffLine renders a line of code in base, with its syntax spans
+// in their colours and the emphasised ranges in emph. The syntax colours
+// replace only the foreground, so the backgrounds of base and emph (an
+// added or removed line's tint) still show.
+func RenderDiffLine(s string, syntax []Span, ranges []Range, base, emph lipgloss.Style) string {
+	if len(syntax) == 0 && len(ranges) == 0 {
+		return base.Render(s)
+	}
+	// Cut s wherever a span or range starts or ends.
+	cuts := []int{len(s)}
+	for _, sp := range syntax {
+		cuts = append(cuts, sp.Start, sp.End)
+	}
+	for _, r := range ranges {
+		cuts = append(cuts, r.Start, r.End)
+	}
+	sort.Ints(cuts)
+	var b strings.Builder
+	pos, si, ri := 0, 0, 0
+	for _, end := range cuts {
+		if end <= pos || end > len(s) {
+			continue
+		}
+		for si < len(syntax) && syntax[si].End <= pos {
+			si++
+		}
+		for ri < len(ranges) && ranges[ri].End <= pos {
+			ri++
+		}
+		style := base
+		if ri < len(ranges) && ranges[ri].Start <= pos {
+			style = emph
+		}
+		if si < len(syntax) && syntax[si].Start <= pos {
+			style = style.Foreground(syntax[si].Color)
+		}
+		b.WriteString(style.Render(s[pos:end]))
+		pos = end
+	}
+	return b.String()
+}
@@ -39,6 +39,27 @@ type Theme struct {
 	Remote      lipgloss.Color
 	Stash       lipgloss.Color
 
+	// Diff line backgrounds and line-number colours. The emphasis
+	// backgrounds mark the words that changed within a line.
+	DiffAddedBg         lipgloss.Color
+	DiffAddedEmphBg     lipgloss.Color
+	DiffAddedGutterBg   lipgloss.Color
+	DiffAddedLineNum    lipgloss.Color
+	DiffRemovedBg       lipgloss.Color
+	DiffRemovedEmphBg   lipgloss.Color
+	DiffRemovedGutterBg lipgloss.Color
+	DiffRemovedLineNum  lipgloss.Color
+
+	// Syntax highlighting in diffs. Tokens without a colour of their own
+	// keep the colour of their line.
+	SyntaxKeyword  lipgloss.Color
+	SyntaxType     lipgloss.Color
+	SyntaxFunction lipgloss.Color
+	SyntaxString   lipgloss.Color
+	SyntaxNumber   lipgloss.Color
+	SyntaxComment  lipgloss.Color
+	SyntaxOperator lipgloss.Color
+
 	GraphColors []lipgloss.Color
 }
 
@@ -79,6 +100,23 @@ func DarkTheme() Theme {
 		Remote:      lipgloss.Color("#f38ba8"),
 		Stash:       lipgloss.Color("#fab387"),
 
+		DiffAddedBg:         lipgloss.Color("#1a3a2a"),
+		DiffAddedEmphBg:     lipgloss.Color("#2e6b45"),
+		DiffAddedGutterBg:   lipgloss.Color("#264d35"),
+		DiffAddedLineNum:    lipgloss.Color("#6dba82"),
+		DiffRemovedBg:       lipgloss.Color("#3a1a1a"),
+		DiffRemovedEmphBg:   lipgloss.Color("#5c1f28"),
+		DiffRemovedGutterBg: lipgloss.Color("#4d2626"),
+		DiffRemovedLineNum:  lipgloss.Color("#c76d7e"),
+
+		SyntaxKeyword:  lipgloss.Color("#cba6f7"),
+		SyntaxType:     lipgloss.Color("#f9e2af"),
+		SyntaxFunction: lipgloss.Color("#89b4fa"),
+		SyntaxString:   lipgloss.Color("#a6e3a1"),
+		SyntaxNumber:   lipgloss.Color("#fab387"),
+		SyntaxComment:  lipgloss.Color("#7f849c"),
+		SyntaxOperator: lipgloss.Color("#89dceb"),
+
 		GraphColors: []lipgloss.Color{
 			"#89b4fa", "#a6e3a1", "#f5c2e7", "#f9e2af",
 			"#89dceb", "#fab387", "#cba6f7", "#f38ba8",
@@ -86,6 +124,68 @@ func DarkTheme() Theme {
 	}
 }
 
+// LightTheme returns a light palette (Catppuccin Latte) for light
+// terminal backgrounds.
+func LightTheme() Theme {
+	return Theme{
+		Bg:            lipgloss.Color("#eff1f5"),
+		Surface:       lipgloss.Color("#e6e9ef"),
+		SurfaceHover:  lipgloss.Color("#dce0e8"),
+		Border:        lipgloss.Color("#bcc0cc"),
+		BorderFocused: lipgloss.Color("#7287fd"),
+
+		Text:        lipgloss.Color("#4c4f69"),
+		TextMuted:   lipgloss.Color("#6c6f85"),
+		TextSubtle:  lipgloss.Color("#9ca0b0"),
+		TextInverse: lipgloss.Color("#eff1f5"),
+
+		Primary:   lipgloss.Color("#1e66f5"),
+		Secondary: lipgloss.Color("#7287fd"),
+		Accent:    lipgloss.Color("#ea76cb"),
+
+		Added:     lipgloss.Color("#40a02b"),
+		Modified:  lipgloss.Color("#df8e1d"),
+		Deleted:   lipgloss.Color("#d20f39"),
+		Renamed:   lipgloss.Color("#04a5e5"),
+		Conflict:  lipgloss.Color("#fe640b"),
+		Untracked: lipgloss.Color("#6c6f85"),
+
+		Success: lipgloss.Color("#40a02b"),
+		Warning: lipgloss.Color("#df8e1d"),
+		Error:   lipgloss.Color("#d20f39"),
+		Info:    lipgloss.Color("#1e66f5"),
+
+		CommitHash:  lipgloss.Color("#df8e1d"),
+		BranchLocal: lipgloss.Color("#40a02b"),
+		BranchHead:  lipgloss.Color("#1e66f5"),
+		Tag:         lipgloss.Color("#ea76cb"),
+		Remote:      lipgloss.Color("#d20f39"),
+		Stash:       lipgloss.Color("#fe640b"),
+
+		DiffAddedBg:         lipgloss.Color("#dcf2d6"),
+		DiffAddedEmphBg:     lipgloss.Color("#a9dd9b"),
+		DiffAddedGutterBg:   lipgloss.Color("#c3e8b9"),
+		DiffAddedLineNum:    lipgloss.Color("#5c9a4c"),
+		DiffRemovedBg:       lipgloss.Color("#f8dbe0"),
+		DiffRemovedEmphBg:   lipgloss.Color("#efadb9"),
+		DiffRemovedGutterBg: lipgloss.Color("#f2c2cb"),
+		DiffRemovedLineNum:  lipgloss.Color("#b5566a"),
+
+		SyntaxKeyword:  lipgloss.Color("#8839ef"),
+		SyntaxType:     lipgloss.Color("#df8e1d"),
+		SyntaxFunction: lipgloss.Color("#1e66f5"),
+		SyntaxString:   lipgloss.Color("#40a02b"),
+		SyntaxNumber:   lipgloss.Color("#fe640b"),
+		SyntaxComment:  lipgloss.Color("#8c8fa1"),
+		SyntaxOperator: lipgloss.Color("#04a5e5"),
+
+		GraphColors: []lipgloss.Color{
+			"#1e66f5", "#40a02b", "#ea76cb", "#df8e1d",
+			"#04a5e5", "#fe640b", "#8839ef", "#d20f39",
+		},
+	}
+}
+
 // Styles holds pre-computed lipgloss styles derived from a Theme.
 type Styles struct {
 	Theme Theme
@@ -128,9 +228,11 @@ type Styles struct {
 
 	// Diff
 	DiffAdded          lipgloss.Style
+	DiffAddedEmph      lipgloss.Style
 	DiffAddedGutter    lipgloss.Style
 	DiffAddedLineNum   lipgloss.Style
 	DiffRemoved        lipgloss.Style
+	DiffRemovedEmph    lipgloss.Style
 	DiffRemovedGutter  lipgloss.Style
 	DiffRemovedLineNum lipgloss.Style
 	DiffContext        lipgloss.Style
@@ -139,6 +241,8 @@ type Styles struct {
 	DiffHunkHeader     lipgloss.Style
 	DiffLineNum        lipgloss.Style
 	DiffSeparator      lipgloss.Style
+	// Syntax highlights code in diffs; nil when highlighting is off.
+	Syntax *Highlighter
 
 	// Commit / refs
 	CommitHash lipgloss.Style
@@ -194,18 +298,16 @@ func NewStyles(t Theme) Styles {
 	s.FileUntracked = lipgloss.NewStyle().Foreground(t.Untracked)
 
 	// Added lines: green text on a subtle green-tinted background.
-	addedBg := lipgloss.Color("#1a3a2a")
-	addedGutterBg := lipgloss.Color("#264d35")
-	s.DiffAdded = lipgloss.NewStyle().Foreground(t.Added).Background(addedBg)
-	s.DiffAddedGutter = lipgloss.NewStyle().Foreground(t.Added).Background(addedGutterBg).Bold(true)
-	s.DiffAddedLineNum = lipgloss.NewStyle().Foreground(lipgloss.Color("#6dba82")).Background(addedBg)
+	s.DiffAdded = lipgloss.NewStyle().Foreground(t.Added).Background(t.DiffAddedBg)
+	s.DiffAddedEmph = lipgloss.NewStyle().Foreground(t.Added).Background(t.DiffAddedEmphBg).Bold(true)
+	s.DiffAddedGutter = lipgloss.NewStyle().Foreground(t.Added).Background(t.DiffAddedGutterBg).Bold(true)
+	s.DiffAddedLineNum = lipgloss.NewStyle().Foreground(t.DiffAddedLineNum).Background(t.DiffAddedBg)
 
 	// Removed lines: red text on a subtle red-tinted background.
-	removedBg := lipgloss.Color("#3a1a1a")
-	removedGutterBg := lipgloss.Color("#4d2626")
-	s.DiffRemoved = lipgloss.NewStyle().Foreground(t.Deleted).Background(removedBg)
-	s.DiffRemovedGutter = lipgloss.NewStyle().Foreground(t.Deleted).Background(removedGutterBg).Bold(true)
-	s.DiffRemovedLineNum = lipgloss.NewStyle().Foreground(lipgloss.Color("#c76d7e")).Background(removedBg)
+	s.DiffRemoved = lipgloss.NewStyle().Foreground(t.Deleted).Background(t.DiffRemovedBg)
+	s.DiffRemovedEmph = lipgloss.NewStyle().Foreground(t.Deleted).Background(t.DiffRemovedEmphBg).Bold(true)
+	s.DiffRemovedGutter = lipgloss.NewStyle().Foreground(t.Deleted).Background(t.DiffRemovedGutterBg).Bold(true)
+	s.DiffRemovedLineNum = lipgloss.NewStyle().Foreground(t.DiffRemovedLineNum).Background(t.DiffRemovedBg)
 
 	// Context lines: dimmed text, no background.
 	s.DiffContext = lipgloss.NewStyle().Foreground(t.TextMuted)
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// Built-in theme names accepted by the `theme` config option.
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// BuiltinTheme returns the named built-in theme.
+func BuiltinTheme(name string) (Theme, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ThemeDark:
+		return DarkTheme(), true
+	case ThemeLight:
+		return LightTheme(), true
+	}
+	return Theme{}, false
+}
+
+// LoadTheme resolves the `theme` config option: a built-in name or a path
+// to a YAML/TOML/JSON file. Theme files start from `base` (a built-in
+// name, dark by default) and override individual colours by snake_case
+// field name, e.g.
+//
+//	base: light
+//	primary: "#d20f39"
+//	graph_colors: ["#1e66f5", "#40a02b"]
+func LoadTheme(name string) (Theme, error) {
+	if t, ok := BuiltinTheme(name); ok {
+		return t, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(expandHome(name))
+	if err := v.ReadInConfig(); err != nil {
+		return Theme{}, fmt.Errorf("theme %q is not %s, %s or a readable theme file: %w",
+			name, ThemeDark, ThemeLight, err)
+	}
+
+	base := v.GetString("base")
+	t, ok := BuiltinTheme(base)
+	if !ok {
+		return Theme{}, fmt.Errorf("theme file %s: unknown base theme %q", name, base)
+	}
+
+	fields := t.colorFields()
+	var problems []string
+	for _, key := range v.AllKeys() {
+		switch key {
+		case "base":
+			continue
+		case "graph_colors":
+			var colors []lipgloss.Color
+			for _, c := range v.GetStringSlice(key) {
+				if !isColor(c) {
+					problems = append(problems, fmt.Sprintf("graph_colors: invalid colour %q", c))
+					continue
+				}
+				colors = append(colors, lipgloss.Color(c))
+			}
+			if len(colors) > 0 {
+				t.GraphColors = colors
+			}
+			continue
+		}
+		field, ok := fields[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown colour %q", key))
+			continue
+		}
+		c := v.GetString(key)
+		if !isColor(c) {
+			problems = append(problems, fmt.Sprintf("%s: invalid colour %q", key, c))
+			continue
+		}
+		*field = lipgloss.Color(c)
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return Theme{}, fmt.Errorf("theme file %s: %s", name, strings.Join(problems, "; "))
+	}
+	return t, nil
+}
+
+// colorFields maps the snake_case names used in theme files to the
+// theme's colour fields.
+func (t *Theme) colorFields() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"bg":                     &t.Bg,
+		"surface":                &t.Surface,
+		"surface_hover":          &t.SurfaceHover,
+		"border":                 &t.Border,
+		"border_focused":         &t.BorderFocused,
+		"text":                   &t.Text,
+		"text_muted":             &t.TextMuted,
+		"text_subtle":            &t.TextSubtle,
+		"text_inverse":           &t.TextInverse,
+		"primary":                &t.Primary,
+		"secondary":              &t.Secondary,
+		"accent":                 &t.Accent,
+		"added":                  &t.Added,
+		"modified":               &t.Modified,
+		"deleted":                &t.Deleted,
+		"renamed":                &t.Renamed,
+		"conflict":               &t.Conflict,
+		"untracked":              &t.Untracked,
+		"success":                &t.Success,
+		"warning":                &t.Warning,
+		"error":                  &t.Error,
+		"info":                   &t.Info,
+		"commit_hash":            &t.CommitHash,
+		"branch_local":           &t.BranchLocal,
+		"branch_head":            &t.BranchHead,
+		"tag":                    &t.Tag,
+		"remote":                 &t.Remote,
+		"stash":                  &t.Stash,
+		"diff_added_bg":          &t.DiffAddedBg,
+		"diff_added_emph_bg":     &t.DiffAddedEmphBg,
+		"diff_added_gutter_bg":   &t.DiffAddedGutterBg,
+		"diff_added_line_num":    &t.DiffAddedLineNum,
+		"diff_removed_bg":        &t.DiffRemovedBg,
+		"diff_removed_emph_bg":   &t.DiffRemovedEmphBg,
+		"diff_removed_gutter_bg": &t.DiffRemovedGutterBg,
+		"diff_removed_line_num":  &t.DiffRemovedLineNum,
+		"syntax_keyword":         &t.SyntaxKeyword,
+		"syntax_type":            &t.SyntaxType,
+		"syntax_function":        &t.SyntaxFunction,
+		"syntax_string":          &t.SyntaxString,
+		"syntax_number":          &t.SyntaxNumber,
+		"syntax_comment":         &t.SyntaxComment,
+		"syntax_operator":        &t.SyntaxOperator,
+	}
+}
+
+// colorPattern accepts hex colours (#rgb, #rrggbb) and ANSI colour
+// numbers (0-255), the forms lipgloss understands.
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|[0-9]{1,3})$`)
+
+func isColor(s string) bool { return colorPattern.MatchString(strings.TrimSpace(s)) }
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -4,9 +4,11 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,6 +19,7 @@ import (
 type BisectView struct {
 	gitSvc    git.Service
 	styles    ui.Styles
+	keys      keys.Set
 	width     int
 	height    int
 	active    bool // whether bisect is in progress
@@ -31,7 +34,7 @@ type BisectView struct {
 type bisectLogMsg struct{ log string }
 
 // NewBisectView creates a new BisectView.
-func NewBisectView(gitSvc git.Service, styles ui.Styles) *BisectView {
+func NewBisectView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *BisectView {
 	bad := textinput.New()
 	bad.Placeholder = "bad commit (e.g. HEAD)"
 	bad.CharLimit = 100
@@ -45,6 +48,7 @@ func NewBisectView(gitSvc git.Service, styles ui.Styles) *BisectView {
 	return &BisectView{
 		gitSvc:    gitSvc,
 		styles:    styles,
+		keys:      keys.New(cfg.Keymap, "bisect"),
 		badInput:  bad,
 		goodInput: good,
 		logVP:     viewport.New(0, 0),
@@ -91,8 +95,8 @@ func (v *BisectView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 }
 
 func (v *BisectView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "b": // Start bisect
+	switch v.keys.Action(msg) {
+	case "start":
 		if !v.active {
 			v.inputMode = true
 			v.inputStep = 0
@@ -101,15 +105,15 @@ func (v *BisectView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
 			v.badInput.Focus()
 			return v, v.badInput.Focus()
 		}
-	case "g": // Good
+	case "good":
 		if v.active {
 			return v, v.bisectGood()
 		}
-	case "B": // Bad
+	case "bad":
 		if v.active {
 			return v, v.bisectBad()
 		}
-	case "R": // Reset
+	case "reset":
 		if v.active {
 			return v, v.bisectReset()
 		}
@@ -209,9 +213,9 @@ func (v *BisectView) View() string {
 	if v.active {
 		b.WriteString(lipgloss.NewStyle().Foreground(t.Warning).Bold(true).
 			Render("  BISECT IN PROGRESS") + "\n\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "g", "mark current as good") + "\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "B", "mark current as bad") + "\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "R", "reset bisect") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("good"), "mark current as good") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("bad"), "mark current as bad") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("reset"), "reset bisect") + "\n")
 
 		if v.log != "" {
 			b.WriteString("\n  " + v.styles.Subtitle.Render("Bisect Log:") + "\n")
@@ -219,7 +223,7 @@ func (v *BisectView) View() string {
 		}
 	} else {
 		b.WriteString("  " + v.styles.Body.Render("No bisect in progress.") + "\n\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "b", "start bisect") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("start"), "start bisect") + "\n")
 	}
 
 	return b.String()
@@ -228,13 +232,13 @@ func (v *BisectView) View() string {
 func (v *BisectView) ShortHelp() []components.HelpEntry {
 	if v.active {
 		return []components.HelpEntry{
-			{Key: "g", Desc: "Mark good"},
-			{Key: "B", Desc: "Mark bad"},
-			{Key: "R", Desc: "Reset bisect"},
+			{Key: v.keys.Help("good"), Desc: "Mark good"},
+			{Key: v.keys.Help("bad"), Desc: "Mark bad"},
+			{Key: v.keys.Help("reset"), Desc: "Reset bisect"},
 		}
 	}
 	return []components.HelpEntry{
-		{Key: "b", Desc: "Start bisect"},
+		{Key: v.keys.Help("start"), Desc: "Start bisect"},
 	}
 }
 
@@ -1,13 +1,16 @@
 package views
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -17,6 +20,8 @@ import (
 type BranchView struct {
 	gitSvc   git.Service
 	styles   ui.Styles
+	cfg      *config.Config
+	keys     keys.Set
 	width    int
 	height   int
 	branches []git.Branch
@@ -27,8 +32,26 @@ type BranchView struct {
 	inputKind branchInputKind
 	input     textinput.Model
 	renameSrc string
+
+	// Reset prompt for moving the current branch to the selected one.
+	reset resetForm
+
+	// base holds each branch's ahead/behind against cfg.BranchBase, keyed
+	// by branchRef; baseMissing is set when that ref doesn't exist.
+	base        map[string]aheadBehind
+	baseMissing bool
+
+	// Upstream picker (for the branch upstreamOf), and the push form for
+	// push-and-track.
+	picker     refPicker
+	upstreamOf string
+	pushForm   pushForm
 }
 
+// aheadBehind counts the commits a branch has that another ref doesn't,
+// and the reverse.
+type aheadBehind struct{ ahead, behind int }
+
 type branchInputKind int
 
 const (
@@ -36,14 +59,35 @@ const (
 	branchInputRename
 )
 
-type branchResultMsg struct{ branches []git.Branch }
+type (
+	branchResultMsg struct {
+		branches    []git.Branch
+		base        map[string]aheadBehind
+		baseMissing bool
+	}
+	branchOpDoneMsg struct {
+		info string
+		err  error
+	}
+)
+
+// branchPickUpstream tags the upstream picker.
+const branchPickUpstream = "branch-upstream"
 
 // NewBranchView creates a new BranchView.
-func NewBranchView(gitSvc git.Service, styles ui.Styles) *BranchView {
+func NewBranchView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *BranchView {
 	ti := textinput.New()
 	ti.CharLimit = 100
 	ti.Width = 40
-	return &BranchView{gitSvc: gitSvc, styles: styles, input: ti}
+	return &BranchView{
+		gitSvc:   gitSvc,
+		styles:   styles,
+		cfg:      cfg,
+		keys:     keys.New(cfg.Keymap, "branches"),
+		input:    ti,
+		picker:   newRefPicker(),
+		pushForm: newPushForm(cfg),
+	}
 }
 
 func (v *BranchView) Init() tea.Cmd { return v.refresh() }
@@ -56,18 +100,70 @@ func (v *BranchView) refresh() tea.Cmd {
 		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
-		return branchResultMsg{branches: branches}
+		msg := branchResultMsg{branches: branches}
+		msg.base, msg.baseMissing, err = v.compareBase(branches)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return msg
 	}
 }
 
+// compareBase counts every branch's commits ahead of and behind
+// cfg.BranchBase. missing reports a base that doesn't resolve.
+func (v *BranchView) compareBase(branches []git.Branch) (counts map[string]aheadBehind, missing bool, err error) {
+	base := v.cfg.BranchBase
+	if base == "" {
+		return nil, false, nil
+	}
+	if v.gitSvc.RefHash(base) == "" {
+		return nil, true, nil
+	}
+	all, err := v.gitSvc.BranchesAheadBehind(base)
+	if err != nil {
+		return nil, false, err
+	}
+	counts = map[string]aheadBehind{}
+	for _, b := range branches {
+		if ab, ok := all[branchRef(b)]; ok {
+			counts[branchRef(b)] = aheadBehind{ab[0], ab[1]}
+		}
+	}
+	return counts, false, nil
+}
+
+// branchRef returns b's full ref name, which can't be mistaken for a tag
+// or for a local branch named like a remote one.
+func branchRef(b git.Branch) string {
+	if b.IsRemote {
+		return "refs/remotes/" + b.Name
+	}
+	return "refs/heads/" + b.Name
+}
+
 func (v *BranchView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 	switch msg := msg.(type) {
 	case branchResultMsg:
 		v.branches = msg.branches
+		v.base, v.baseMissing = msg.base, msg.baseMissing
 		if v.cursor >= len(v.branches) && len(v.branches) > 0 {
 			v.cursor = len(v.branches) - 1
 		}
 		return v, nil
+	case resetOpDoneMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+	case branchOpDoneMsg:
+		if msg.err != nil {
+			return v, common.CmdErr(msg.err)
+		}
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+	case refPickedMsg:
+		if msg.tag == branchPickUpstream {
+			return v, v.setUpstream(v.upstreamOf, msg.ref)
+		}
+		return v, nil
+	case pushSubmitMsg:
+		return v, askPush(v.gitSvc, v.cfg, msg.opts, v.push)
 	case common.RefreshMsg:
 		return v, v.refresh()
 	case tea.MouseMsg:
@@ -77,6 +173,15 @@ func (v *BranchView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 		if v.inputMode {
 			return v.updateInput(msg)
 		}
+		if v.reset.open {
+			return v, v.reset.Update(v.gitSvc, v.cfg, msg)
+		}
+		if v.picker.open {
+			return v, v.picker.Update(msg)
+		}
+		if v.pushForm.open {
+			return v, v.pushForm.Update(msg)
+		}
 		return v.updateNormal(msg)
 	}
 	return v, nil
@@ -93,7 +198,7 @@ func (v *BranchView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 			v.cursor++
 		}
 	case tea.MouseButtonLeft:
-		if msg.Action != tea.MouseActionPress || v.inputMode {
+		if msg.Action != tea.MouseActionPress || v.InputCapture() {
 			break
 		}
 		// Content starts at Y=2, header is 2 lines ("Branches (N)" + blank).
@@ -106,33 +211,33 @@ func (v *BranchView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 }
 
 func (v *BranchView) updateNormal(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "j", "down":
+	switch v.keys.Action(msg) {
+	case "down":
 		if v.cursor < len(v.branches)-1 {
 			v.cursor++
 		}
-	case "k", "up":
+	case "up":
 		if v.cursor > 0 {
 			v.cursor--
 		}
-	case "g", "home":
+	case "top":
 		v.cursor = 0
-	case "G", "end":
+	case "bottom":
 		if len(v.branches) > 0 {
 			v.cursor = len(v.branches) - 1
 		}
-	case "enter": // Switch
+	case "checkout":
 		if b, ok := v.currentBranch(); ok && !b.IsCurrent {
 			return v, v.switchBranch(b.Name)
 		}
-	case "n": // New branch
+	case "new":
 		v.inputMode = true
 		v.inputKind = branchInputCreate
 		v.input.Placeholder = "new-branch-name"
 		v.input.Reset()
 		v.input.Focus()
 		return v, v.input.Focus()
-	case "R": // Rename
+	case "rename":
 		if b, ok := v.currentBranch(); ok && !b.IsRemote {
 			v.inputMode = true
 			v.inputKind = branchInputRename
@@ -142,18 +247,89 @@ func (v *BranchView) updateNormal(msg tea.KeyMsg) (common.View, tea.Cmd) {
 			v.input.Focus()
 			return v, v.input.Focus()
 		}
-	case "D": // Delete
+	case "delete":
 		if b, ok := v.currentBranch(); ok && !b.IsCurrent && !b.IsRemote {
-			return v, v.deleteBranch(b.Name)
+			return v, v.confirmDelete(b.Name)
 		}
-	case "m": // Merge
+	case "merge":
 		if b, ok := v.currentBranch(); ok && !b.IsCurrent {
 			return v, v.mergeBranch(b.Name)
 		}
+	case "reset":
+		if b, ok := v.currentBranch(); ok && !b.IsCurrent {
+			ref := b.Name
+			if b.IsRemote {
+				ref = branchRef(b)
+			}
+			v.reset.Open(ref, b.Name)
+		}
+	case "copy_name":
+		if b, ok := v.currentBranch(); ok {
+			return v, copyText(v.cfg, b.Name, b.Name)
+		}
+	case "set_upstream":
+		if b, ok := v.currentBranch(); ok && !b.IsRemote {
+			var refs []string
+			for _, r := range v.branches {
+				if r.IsRemote {
+					refs = append(refs, r.Name)
+				}
+			}
+			if len(refs) == 0 {
+				return v, common.CmdErr(fmt.Errorf("no remote branches to track; fetch a remote first"))
+			}
+			v.upstreamOf = b.Name
+			return v, v.picker.Open("Upstream of "+b.Name, branchPickUpstream, refs, b.Upstream)
+		}
+	case "unset_upstream":
+		if b, ok := v.currentBranch(); ok && !b.IsRemote && b.Upstream != "" {
+			return v, v.branchOp(b.Name+" no longer tracks "+b.Upstream, func() error {
+				return v.gitSvc.UnsetUpstream(b.Name)
+			})
+		}
+	case "push_track":
+		if b, ok := v.currentBranch(); ok && !b.IsRemote {
+			remotes, err := v.gitSvc.Remotes()
+			if err != nil {
+				return v, common.CmdErr(err)
+			}
+			if len(remotes) == 0 {
+				return v, common.CmdErr(fmt.Errorf("no remotes configured; add one in the Remotes view"))
+			}
+			// Start on the upstream's remote, else origin, else the first.
+			remote := remotes[0].Name
+			for _, r := range remotes {
+				if r.Name == "origin" || strings.HasPrefix(b.Upstream, r.Name+"/") {
+					remote = r.Name
+				}
+			}
+			return v, v.pushForm.Open(remotes, remote, b.Name, b.Upstream)
+		}
 	}
 	return v, nil
 }
 
+// branchOp runs a quick branch operation, reported as info on success.
+func (v *BranchView) branchOp(info string, op func() error) tea.Cmd {
+	return func() tea.Msg { return branchOpDoneMsg{info: info, err: op()} }
+}
+
+func (v *BranchView) setUpstream(branch, upstream string) tea.Cmd {
+	return v.branchOp(branch+" now tracks "+upstream, func() error {
+		return v.gitSvc.SetUpstream(branch, upstream)
+	})
+}
+
+func (v *BranchView) push(opts git.PushOptions) tea.Cmd {
+	info := fmt.Sprintf("Pushed %s to %s/%s", opts.Branch, opts.Remote, opts.Target)
+	if opts.SetUpstream {
+		info += " and set it as upstream"
+	}
+	return runOperation("push "+opts.Remote, func(ctx context.Context, progress git.ProgressFunc) error {
+		return v.gitSvc.Push(ctx, opts, progress)
+	}, func(err error) tea.Msg { return branchOpDoneMsg{info: info, err: err} })
+}
+
 func (v *BranchView) updateInput(msg tea.KeyMsg) (common.View, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
@@ -191,16 +367,48 @@ func (v *BranchView) switchBranch(name string) tea.Cmd {
 
 func (v *BranchView) createBranch(name string) tea.Cmd {
 	return func() tea.Msg {
-		if err := v.gitSvc.CreateBranch(name); err != nil {
+		if err := v.gitSvc.CreateBranch(name, ""); err != nil {
 			return common.ErrMsg{Err: err}
 		}
 		return common.CmdRefresh()
 	}
 }
 
-func (v *BranchView) deleteBranch(name string) tea.Cmd {
+// confirmDelete asks before deleting a branch, listing the commits that
+// no other branch or remote-tracking branch contains. Once the user has
+// seen them the delete is forced; without a prompt git's own unmerged
+// check stays in place.
+func (v *BranchView) confirmDelete(name string) tea.Cmd {
+	return confirm(v.cfg, v.deleteBranch(name, false), func() (common.ConfirmMsg, error) {
+		lost, err := v.gitSvc.Log(maxUnmergedShown, name, "--not", "--exclude="+name, "--branches", "--remotes")
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		msg := common.ConfirmMsg{
+			Kind:      confirmDeleteBranch,
+			Title:     "Delete branch " + name + "?",
+			Detail:    "Every commit on " + name + " is also on another branch.",
+			OnConfirm: v.deleteBranch(name, true),
+		}
+		if len(lost) > 0 {
+			count := fmt.Sprint(len(lost))
+			if len(lost) == maxUnmergedShown {
+				count += "+"
+			}
+			msg.Kind = confirmDeleteUnmerged
+			msg.Detail = count + " unmerged commit(s) exist only on " + name + " and will be lost:\n\n" +
+				confirmPreview(commitLines(lost))
+		}
+		return msg, nil
+	})
+}
+
+// maxUnmergedShown caps the unmerged-commit lookup for the delete prompt.
+const maxUnmergedShown = 100
+
+func (v *BranchView) deleteBranch(name string, force bool) tea.Cmd {
 	return func() tea.Msg {
-		if err := v.gitSvc.DeleteBranch(name, false); err != nil {
+		if err := v.gitSvc.DeleteBranch(name, force); err != nil {
 			return common.ErrMsg{Err: err}
 		}
 		return common.CmdRefresh()
@@ -229,6 +437,15 @@ func (v *BranchView) View() string {
 	if v.inputMode {
 		return v.viewInput()
 	}
+	if v.reset.open {
+		return v.reset.View(v.styles)
+	}
+	if v.picker.open {
+		return v.picker.View(v.styles)
+	}
+	if v.pushForm.open {
+		return v.pushForm.View(v.styles)
+	}
 	return v.viewList()
 }
 
@@ -241,7 +458,14 @@ func (v *BranchView) viewList() string {
 
 	var b strings.Builder
 	b.WriteString(lipgloss.NewStyle().Foreground(t.Primary).Bold(true).
-		Render(fmt.Sprintf("  Branches (%d)", len(v.branches))) + "\n\n")
+		Render(fmt.Sprintf("  Branches (%d)", len(v.branches))))
+	switch {
+	case v.baseMissing:
+		b.WriteString(lipgloss.NewStyle().Foreground(t.Warning).Render("  branch_base " + v.cfg.BranchBase + " not found"))
+	case v.base != nil:
+		b.WriteString(v.styles.Muted.Render("  base: ahead/behind " + v.cfg.BranchBase))
+	}
+	b.WriteString("\n\n")
 
 	for i, br := range v.branches {
 		line := v.renderBranchLine(br)
@@ -252,7 +476,8 @@ func (v *BranchView) viewList() string {
 		}
 	}
 
-	b.WriteString("\n" + v.styles.Muted.Render("  enter switch  n new  R rename  D delete  m merge"))
+	b.WriteString("\n" + v.styles.Muted.Render("  "+v.keys.Hints("checkout", "switch", "new", "new", "rename", "rename", "delete", "delete", "merge", "merge", "reset", "reset to",
+		"set_upstream", "upstream", "push_track", "push")))
 	return b.String()
 }
 
@@ -273,11 +498,17 @@ func (v *BranchView) renderBranchLine(br git.Branch) string {
 
 	if br.Upstream != "" {
 		track := br.Upstream
-		if br.Ahead > 0 || br.Behind > 0 {
+		switch {
+		case br.UpstreamGone:
+			track += " [gone]"
+		case br.Ahead > 0 || br.Behind > 0:
 			track += fmt.Sprintf(" [+%d/-%d]", br.Ahead, br.Behind)
 		}
 		parts = append(parts, v.styles.Muted.Render(track))
 	}
+	if ab, ok := v.base[branchRef(br)]; ok {
+		parts = append(parts, v.styles.Muted.Render(fmt.Sprintf("base [+%d/-%d]", ab.ahead, ab.behind)))
+	}
 
 	parts = append(parts, v.styles.Muted.Render(ui.Truncate(br.Subject, 40)))
 
@@ -307,12 +538,19 @@ func (v *BranchView) currentBranch() (git.Branch, bool) {
 
 func (v *BranchView) ShortHelp() []components.HelpEntry {
 	return []components.HelpEntry{
-		{Key: "enter", Desc: "Switch branch"},
-		{Key: "n", Desc: "New branch"},
-		{Key: "R", Desc: "Rename branch"},
-		{Key: "D", Desc: "Delete branch"},
-		{Key: "m", Desc: "Merge into current"},
+		{Key: v.keys.Help("checkout"), Desc: "Switch branch"},
+		{Key: v.keys.Help("new"), Desc: "New branch"},
+		{Key: v.keys.Help("rename"), Desc: "Rename branch"},
+		{Key: v.keys.Help("delete"), Desc: "Delete branch"},
+		{Key: v.keys.Help("merge"), Desc: "Merge into current"},
+		{Key: v.keys.Help("reset"), Desc: "Reset current branch to this one"},
+		{Key: v.keys.Help("set_upstream"), Desc: "Set upstream (pick a remote branch)"},
+		{Key: v.keys.Help("unset_upstream"), Desc: "Unset upstream"},
+		{Key: v.keys.Help("push_track"), Desc: "Push and track on a remote"},
+		{Key: v.keys.Help("copy_name"), Desc: "Copy branch name"},
 	}
 }
 
-func (v *BranchView) InputCapture() bool { return v.inputMode }
+func (v *BranchView) InputCapture() bool {
+	return v.inputMode || v.reset.open || v.picker.open || v.pushForm.open
+}
@@ -0,0 +1,105 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/clipboard"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// copyText copies text to the clipboard and reports it as "Copied what".
+func copyText(cfg *config.Config, what, text string) tea.Cmd {
+	return func() tea.Msg {
+		if text == "" {
+			return common.ErrMsg{Err: fmt.Errorf("nothing to copy")}
+		}
+		if err := clipboard.Copy(cfg.Clipboard, text); err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("copying %s: %w", what, err)}
+		}
+		return common.InfoMsg{Text: "Copied " + what}
+	}
+}
+
+// copyChoice is an entry of a copyMenu: the "copy_menu" keymap action
+// copies text, reported as what.
+type copyChoice struct {
+	action string
+	what   string
+	text   string
+}
+
+// copyMenu asks which of several texts to copy, e.g. a commit's hash,
+// full hash or subject.
+type copyMenu struct {
+	keys    keys.Set
+	open    bool
+	title   string
+	choices []copyChoice
+	cursor  int
+}
+
+func newCopyMenu(cfg *config.Config) copyMenu {
+	return copyMenu{keys: keys.New(cfg.Keymap, "copy_menu")}
+}
+
+// Open shows choices titled title.
+func (m *copyMenu) Open(title string, choices []copyChoice) {
+	m.open = true
+	m.title, m.choices = title, choices
+	m.cursor = 0
+}
+
+// Update handles a key while the menu is open. Picking an entry closes
+// the menu and copies it.
+func (m *copyMenu) Update(cfg *config.Config, msg tea.KeyMsg) tea.Cmd {
+	switch action := m.keys.Action(msg); action {
+	case "cancel":
+		m.open = false
+	case "prev":
+		m.cursor = (m.cursor + len(m.choices) - 1) % len(m.choices)
+	case "next":
+		m.cursor = (m.cursor + 1) % len(m.choices)
+	case "submit":
+		m.open = false
+		c := m.choices[m.cursor]
+		return copyText(cfg, c.what, c.text)
+	default:
+		for _, c := range m.choices {
+			if c.action == action {
+				m.open = false
+				return copyText(cfg, c.what, c.text)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *copyMenu) View(styles ui.Styles) string {
+	t := styles.Theme
+	lines := []string{
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  " + m.title),
+		"",
+	}
+	for i, c := range m.choices {
+		line := fmt.Sprintf("%s  %-10s %s", m.keys.First(c.action), c.what, ui.Truncate(firstLine(c.text), 60))
+		if i == m.cursor {
+			lines = append(lines, styles.ListSelected.Render("▸ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "", styles.Muted.Render("  "+m.keys.First("prev")+"/"+m.keys.Hints("next", "choose", "submit", "copy", "cancel", "cancel")))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
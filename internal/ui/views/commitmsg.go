@@ -0,0 +1,164 @@
+package views
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ── Linting ─────────────────────────────────────────────────────────────────
+
+// conventionalRe matches a conventional-commit subject:
+// "type(scope)!: description", with the scope and "!" optional.
+var conventionalRe = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?!?: \S`)
+
+// lintCommit checks message against the configured rules and returns one
+// warning per problem. Subjects git writes itself (merges, reverts,
+// fixup!/squash!/amend!) are exempt from the conventional-commit checks.
+func lintCommit(cfg *config.Config, message string) []string {
+	lines := strings.Split(cleanupMessage(message), "\n")
+	subject := lines[0]
+	if subject == "" {
+		return nil
+	}
+	var warnings []string
+	if n := len([]rune(subject)); cfg.CommitSubjectMax > 0 && n > cfg.CommitSubjectMax {
+		warnings = append(warnings, fmt.Sprintf("subject is %d characters (limit %d)", n, cfg.CommitSubjectMax))
+	}
+	if cfg.CommitBlankLine && len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		warnings = append(warnings, "line 2 should be blank, separating the subject from the body")
+	}
+	if len(cfg.CommitTypes) == 0 || generatedSubject(subject) {
+		return warnings
+	}
+	m := conventionalRe.FindStringSubmatch(subject)
+	switch {
+	case m == nil:
+		warnings = append(warnings, `subject should read "type(scope): description"`)
+	case !slices.Contains(cfg.CommitTypes, m[1]):
+		warnings = append(warnings, fmt.Sprintf("unknown type %q (use %s)", m[1], strings.Join(cfg.CommitTypes, ", ")))
+	case m[2] != "" && len(cfg.CommitScopes) > 0 && !slices.Contains(cfg.CommitScopes, m[2]):
+		warnings = append(warnings, fmt.Sprintf("unknown scope %q (use %s)", m[2], strings.Join(cfg.CommitScopes, ", ")))
+	}
+	return warnings
+}
+
+// generatedSubject reports whether git (or an autosquash workflow) wrote
+// the subject.
+func generatedSubject(subject string) bool {
+	for _, p := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupMessage drops comment lines and surrounding blank lines, as git
+// does for a message written in an editor, so a commit.template's
+// instructions don't end up in the commit.
+func cleanupMessage(message string) string {
+	var kept []string
+	for _, l := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(l, "#") {
+			kept = append(kept, strings.TrimRight(l, " \t\r"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// commitMessage returns c's full message, as git stores it.
+func commitMessage(c git.Commit) string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// ── History ─────────────────────────────────────────────────────────────────
+
+// commitHistory is the list of recently submitted commit messages, newest
+// first, kept in the state directory so it survives restarts. A message
+// is saved before the commit runs, so one rejected by a hook isn't lost.
+type commitHistory struct {
+	path    string
+	limit   int
+	entries []string
+}
+
+func loadCommitHistory(limit int) *commitHistory {
+	h := &commitHistory{path: filepath.Join(config.StateDirectory(), "commit_history.json"), limit: limit}
+	if limit <= 0 {
+		return h
+	}
+	// A missing or unreadable history just starts empty.
+	if data, err := os.ReadFile(h.path); err == nil {
+		_ = json.Unmarshal(data, &h.entries)
+	}
+	if len(h.entries) > limit {
+		h.entries = h.entries[:limit]
+	}
+	return h
+}
+
+// Add puts message at the front (moving it if already present) and saves.
+func (h *commitHistory) Add(message string) error {
+	if h.limit <= 0 || message == "" {
+		return nil
+	}
+	h.entries = slices.DeleteFunc(h.entries, func(e string) bool { return e == message })
+	h.entries = slices.Insert(h.entries, 0, message)
+	if len(h.entries) > h.limit {
+		h.entries = h.entries[:h.limit]
+	}
+	data, err := json.MarshalIndent(h.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("saving commit history: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0o600); err != nil {
+		return fmt.Errorf("saving commit history: %w", err)
+	}
+	return nil
+}
+
+// ── External editor ─────────────────────────────────────────────────────────
+
+type commitEditedMsg struct{ message string }
+
+// editCommitMessage suspends the TUI and opens message in the editor, in
+// the git dir's COMMIT_EDITMSG so editors pick their commit-message mode.
+// The edited text comes back as a commitEditedMsg.
+func editCommitMessage(editor, gitDir, message string) tea.Cmd {
+	path := filepath.Join(gitDir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(message), 0o644); err != nil {
+		return common.CmdErr(err)
+	}
+	cmd, err := editorCmd(editor, path)
+	if err != nil {
+		return common.CmdErr(err)
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return common.ErrMsg{Err: err}
+		}
+		return commitEditedMsg{message: strings.TrimRight(string(data), "\n")}
+	})
+}
@@ -0,0 +1,159 @@
+package views
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── Running a commit ────────────────────────────────────────────────────────
+
+// commitFunc runs the commit (or amend) with the given options.
+type commitFunc func(opts git.CommitOptions) error
+
+// commitOutputMsg carries output written while a commit runs; ch delivers
+// the next message.
+type commitOutputMsg struct {
+	chunk string
+	ch    <-chan tea.Msg
+}
+
+// commitDoneMsg ends a commit. output is what a commit run in the terminal
+// printed; streamed output has already arrived as commitOutputMsgs.
+type commitDoneMsg struct {
+	output string
+	err    error
+}
+
+// chanWriter forwards each write to its channel as a commitOutputMsg.
+type chanWriter chan tea.Msg
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- commitOutputMsg{chunk: string(p), ch: w}
+	return len(p), nil
+}
+
+// streamCommit runs commit in the background, delivering its output as it
+// is written and then a commitDoneMsg.
+func streamCommit(commit commitFunc, opts git.CommitOptions) tea.Cmd {
+	ch := make(chan tea.Msg, 64)
+	opts.Output = chanWriter(ch)
+	go func() {
+		err := commit(opts)
+		ch <- commitDoneMsg{err: err}
+	}()
+	return waitCommitOutput(ch)
+}
+
+func waitCommitOutput(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg { return <-ch }
+}
+
+// terminalCommit runs a commit with the terminal attached while the TUI is
+// suspended, so a signer can ask for a passphrase (pinentry, ssh-keygen).
+// Its output is kept for the output panel.
+type terminalCommit struct {
+	commit commitFunc
+	opts   git.CommitOptions
+	header string
+	stdout io.Writer
+	output bytes.Buffer
+}
+
+func (c *terminalCommit) SetStdin(r io.Reader)  { c.opts.Stdin = r }
+func (c *terminalCommit) SetStdout(w io.Writer) { c.stdout = w }
+func (c *terminalCommit) SetStderr(io.Writer)   {}
+
+func (c *terminalCommit) Run() error {
+	fmt.Fprintln(c.stdout, c.header)
+	c.opts.Output = io.MultiWriter(c.stdout, &c.output)
+	return c.commit(c.opts)
+}
+
+// execCommit runs commit in the terminal (see terminalCommit).
+func execCommit(commit commitFunc, opts git.CommitOptions, header string) tea.Cmd {
+	tc := &terminalCommit{commit: commit, opts: opts, header: header}
+	return tea.Exec(tc, func(err error) tea.Msg {
+		return commitDoneMsg{output: tc.output.String(), err: err}
+	})
+}
+
+// ── Output panel ────────────────────────────────────────────────────────────
+
+// outputPanel shows what a commit's hooks and signer printed. It opens
+// when the first output arrives, or when the commit fails, and stays
+// until dismissed.
+type outputPanel struct {
+	open    bool
+	running bool
+	failed  bool
+	title   string
+	text    strings.Builder
+	vp      viewport.Model
+}
+
+func newOutputPanel() outputPanel {
+	return outputPanel{vp: viewport.New(0, 0)}
+}
+
+// Start clears the panel for a new run, titled title.
+func (p *outputPanel) Start(title string) {
+	p.open, p.running, p.failed = false, true, false
+	p.title = title
+	p.text.Reset()
+	p.vp.SetContent("")
+}
+
+// Append adds output, following it to the bottom.
+func (p *outputPanel) Append(chunk string) {
+	chunk = strings.ReplaceAll(chunk, "\r\n", "\n")
+	chunk = strings.ReplaceAll(chunk, "\r", "\n")
+	if chunk == "" {
+		return
+	}
+	p.open = true
+	p.text.WriteString(chunk)
+	p.vp.SetContent(p.text.String())
+	p.vp.GotoBottom()
+}
+
+// Finish ends the run. A failure keeps the output (or at least the error)
+// on screen.
+func (p *outputPanel) Finish(err error) {
+	p.running = false
+	if err == nil {
+		return
+	}
+	p.failed = true
+	if p.text.Len() > 0 && !strings.HasSuffix(p.text.String(), "\n") {
+		p.text.WriteString("\n")
+	}
+	p.Append(err.Error() + "\n")
+}
+
+func (p *outputPanel) Close() { p.open = false }
+
+func (p *outputPanel) SetSize(w, h int) {
+	p.vp.Width = max(w-2, 1)
+	p.vp.Height = max(h-4, 1)
+}
+
+func (p *outputPanel) View(styles ui.Styles, hint string) string {
+	t := styles.Theme
+	status := styles.Muted.Render("  running…")
+	switch {
+	case p.failed:
+		status = lipgloss.NewStyle().Foreground(t.Error).Bold(true).Render("  failed")
+	case !p.running:
+		status = lipgloss.NewStyle().Foreground(t.Success).Render("  done")
+	}
+	title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render(" "+p.title) + status
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", " "+p.vp.View(), "", styles.Muted.Render(" "+hint))
+}
@@ -0,0 +1,220 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── Comparing refs ──────────────────────────────────────────────────────────
+
+// comparison is the Diff view's compare mode: the files and commits that
+// differ between two refs.
+type comparison struct {
+	from, to string
+	// threeDot compares to with the merge base of from and to (from...to,
+	// the changes a merge of to would bring) instead of with from itself.
+	threeDot bool
+	files    []git.DiffFileStat
+	onlyFrom []git.Commit // commits on from that to doesn't have
+	onlyTo   []git.Commit // and the reverse
+	browser  diffBrowser
+}
+
+// rng is the comparison as a git revision range.
+func (c *comparison) rng() string {
+	if c.threeDot {
+		return c.from + "..." + c.to
+	}
+	return c.from + ".." + c.to
+}
+
+type (
+	// diffCompareMsg asks the Diff view to compare two refs, e.g. the two
+	// commits marked in the Log view.
+	diffCompareMsg   struct{ from, to string }
+	compareRefsMsg   struct{ refs []string }
+	compareFileMsg   struct{ diff string }
+	compareResultMsg struct {
+		from, to string
+		threeDot bool
+		files    []git.DiffFileStat
+		onlyFrom []git.Commit
+		onlyTo   []git.Commit
+	}
+)
+
+// Picker tags for choosing the two sides.
+const (
+	diffPickFrom = "compare-from"
+	diffPickTo   = "compare-to"
+)
+
+// maxCompareCommits caps the commit lookups for each side; at most
+// compareCommitsShown of them are listed.
+const (
+	maxCompareCommits   = 100
+	compareCommitsShown = 5
+)
+
+// loadCompareRefs lists what can be compared: HEAD, branches, tags and
+// stashes.
+func (v *DiffView) loadCompareRefs() tea.Cmd {
+	return func() tea.Msg {
+		refs := []string{"HEAD"}
+		branches, err := v.gitSvc.Branches()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		for _, b := range branches {
+			refs = append(refs, b.Name)
+		}
+		tags, err := v.gitSvc.Tags()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		for _, t := range tags {
+			refs = append(refs, t.Name)
+		}
+		stashes, err := v.gitSvc.StashList()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		for _, s := range stashes {
+			refs = append(refs, fmt.Sprintf("stash@{%d}", s.Index))
+		}
+		return compareRefsMsg{refs: refs}
+	}
+}
+
+// loadCompare loads the files changed between from and to and the commits
+// unique to each side.
+func (v *DiffView) loadCompare(from, to string, threeDot bool) tea.Cmd {
+	return func() tea.Msg {
+		c := comparison{from: from, to: to, threeDot: threeDot}
+		files, err := v.gitSvc.DiffRangeFiles(c.rng())
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		onlyFrom, err := v.gitSvc.Log(maxCompareCommits, from, "--not", to)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		onlyTo, err := v.gitSvc.Log(maxCompareCommits, to, "--not", from)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return compareResultMsg{from: from, to: to, threeDot: threeDot, files: files, onlyFrom: onlyFrom, onlyTo: onlyTo}
+	}
+}
+
+// updateCompareMsg handles the compare mode's messages; ok is false for
+// any other message.
+func (v *DiffView) updateCompareMsg(msg tea.Msg) (cmd tea.Cmd, ok bool) {
+	switch msg := msg.(type) {
+	case diffCompareMsg:
+		return v.loadCompare(msg.from, msg.to, false), true
+
+	case compareRefsMsg:
+		v.compareRefs = msg.refs
+		return v.picker.Open("Compare from", diffPickFrom, msg.refs, ""), true
+
+	case refPickedMsg:
+		switch msg.tag {
+		case diffPickFrom:
+			v.compareFrom = msg.ref
+			return v.picker.Open("Compare "+msg.ref+" with", diffPickTo, v.compareRefs, "HEAD"), true
+		case diffPickTo:
+			return v.loadCompare(v.compareFrom, msg.ref, false), true
+		}
+		return nil, true
+
+	case compareResultMsg:
+		c := v.compare
+		if c == nil {
+			c = &comparison{browser: newDiffBrowser(v.styles, keys.New(v.cfg.Keymap, "diff_browser"), v.sideBySide)}
+		}
+		c.from, c.to, c.threeDot = msg.from, msg.to, msg.threeDot
+		c.files, c.onlyFrom, c.onlyTo = msg.files, msg.onlyFrom, msg.onlyTo
+		v.compare = c
+		// The header's height depends on the commits listed.
+		_, h := v.compareLayout()
+		c.browser.SetSize(v.width, h)
+		rng := c.rng()
+		return c.browser.SetSections([]diffSection{{files: c.files,
+			load: func(f git.DiffFileStat) (string, error) { return v.gitSvc.DiffRangeFile(rng, f.Path, f.OldPath) }}}), true
+	}
+	return nil, false
+}
+
+// updateCompareKey handles the keys that only apply in compare mode; ok
+// is false for other keys.
+func (v *DiffView) updateCompareKey(msg tea.KeyMsg) (cmd tea.Cmd, ok bool) {
+	c := v.compare
+	switch v.keys.Action(msg) {
+	case "toggle_range":
+		return v.loadCompare(c.from, c.to, !c.threeDot), true
+	case "swap":
+		return v.loadCompare(c.to, c.from, c.threeDot), true
+	}
+	return nil, false
+}
+
+// compareLayout returns the compare header, cut short on a short
+// terminal, and the height left for the browser under it.
+func (v *DiffView) compareLayout() ([]string, int) {
+	// The last line holds the hints.
+	return fitHeader(v.compareHeader(), v.height-1)
+}
+
+// compareHeader renders the title and the commits unique to each side.
+func (v *DiffView) compareHeader() []string {
+	c := v.compare
+	t := v.styles.Theme
+	mode := "changes from " + c.from + " to " + c.to
+	if c.threeDot {
+		mode = "changes on " + c.to + " since it forked from " + c.from
+	}
+	lines := []string{
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  Compare "+c.rng()) +
+			"  " + v.styles.Muted.Render(mode),
+		"",
+	}
+	side := func(commits []git.Commit, ref string) {
+		count := countCompareCommits(commits)
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Primary).
+			Render(fmt.Sprintf("  %s commit(s) only on %s", count, ref)))
+		for _, cm := range commits[:min(len(commits), compareCommitsShown)] {
+			lines = append(lines, "    "+v.styles.CommitHash.Render(cm.ShortHash)+" "+cm.Subject)
+		}
+		if len(commits) > compareCommitsShown {
+			lines = append(lines, v.styles.Muted.Render("    …"))
+		}
+	}
+	side(c.onlyTo, c.to)
+	side(c.onlyFrom, c.from)
+
+	added, deleted := 0, 0
+	for _, f := range c.files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	lines = append(lines, "", lipgloss.NewStyle().Foreground(t.Primary).
+		Render(fmt.Sprintf("  %d file(s) changed", len(c.files)))+"  "+
+		v.styles.DiffAdded.Render(fmt.Sprintf("+%d", added))+" "+
+		v.styles.DiffRemoved.Render(fmt.Sprintf("-%d", deleted)))
+	return lines
+}
+
+// countCompareCommits formats the size of a commit list capped at
+// maxCompareCommits.
+func countCompareCommits(commits []git.Commit) string {
+	if len(commits) == maxCompareCommits {
+		return fmt.Sprint(len(commits)) + "+"
+	}
+	return fmt.Sprint(len(commits))
+}
@@ -0,0 +1,82 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Confirmation kinds. The user can silence one kind for the session from
+// the dialog.
+const (
+	confirmDiscard        = "discard"
+	confirmDeleteBranch   = "delete-branch"
+	confirmDeleteUnmerged = "delete-unmerged-branch"
+	confirmDropStash      = "drop-stash"
+	confirmPush           = "push"
+	confirmReset          = "reset"
+	confirmDeleteTag      = "delete-tag"
+	confirmDeleteRemote   = "delete-remote-tag"
+	confirmRemoveRemote   = "remove-remote"
+)
+
+const (
+	confirmMaxLines = 10 // preview lines shown in the dialog
+	confirmMaxWidth = 48 // dialog content width
+)
+
+// confirm routes a destructive action through the app's confirm dialog.
+// build runs in the background to describe what would be lost. When
+// confirmations are turned off, direct runs straight away instead (it may
+// be more cautious than build's action, e.g. `branch -d` over `-D`).
+func confirm(cfg *config.Config, direct tea.Cmd, build func() (common.ConfirmMsg, error)) tea.Cmd {
+	if !cfg.ConfirmDestructive {
+		return direct
+	}
+	return func() tea.Msg {
+		msg, err := build()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return msg
+	}
+}
+
+// confirmPreview fits command output (a diffstat, a commit list) into the
+// dialog, noting how many lines were left out.
+func confirmPreview(lines []string) string {
+	var b strings.Builder
+	for i, l := range lines {
+		if i == confirmMaxLines {
+			fmt.Fprintf(&b, "… %d more", len(lines)-i)
+			break
+		}
+		b.WriteString(ui.Truncate(strings.TrimRight(l, " "), confirmMaxWidth) + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// commitLines renders commits as "<short hash> <subject>" lines.
+func commitLines(commits []git.Commit) []string {
+	lines := make([]string, len(commits))
+	for i, c := range commits {
+		lines[i] = c.ShortHash + " " + c.Subject
+	}
+	return lines
+}
+
+// splitLines splits command output into non-empty lines.
+func splitLines(out string) []string {
+	var lines []string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
@@ -5,34 +5,56 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// ConflictView helps resolve merge conflicts.
+// ConflictView helps resolve merge conflicts: whole-file resolution from
+// the list, and a three-way block editor for text conflicts.
 type ConflictView struct {
 	gitSvc   git.Service
 	styles   ui.Styles
+	keys     keys.Set // file list
+	mergeKs  keys.Set // merge editor
 	width    int
 	height   int
-	files    []string
+	files    []git.ConflictFile
 	cursor   int
 	diffVP   viewport.Model
 	showDiff bool
+
+	// Merge editor
+	merging   bool
+	mergePath string
+	doc       *git.ConflictDoc
+	choices   []git.ConflictChoice // parallel to doc.Blocks
+	block     int                  // current block index
+	previewVP viewport.Model
 }
 
 type (
-	conflictFilesMsg struct{ files []string }
-	conflictDiffMsg  struct{ diff string }
+	conflictFilesMsg   struct{ files []git.ConflictFile }
+	conflictDiffMsg    struct{ diff string }
+	conflictContentMsg struct {
+		path    string
+		content string
+	}
 )
 
 // NewConflictView creates a new ConflictView.
-func NewConflictView(gitSvc git.Service, styles ui.Styles) *ConflictView {
-	return &ConflictView{gitSvc: gitSvc, styles: styles}
+func NewConflictView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *ConflictView {
+	return &ConflictView{
+		gitSvc:  gitSvc,
+		styles:  styles,
+		keys:    keys.New(cfg.Keymap, "conflicts"),
+		mergeKs: keys.New(cfg.Keymap, "conflicts_merge"),
+	}
 }
 
 func (v *ConflictView) Init() tea.Cmd { return v.refresh() }
@@ -42,11 +64,15 @@ func (v *ConflictView) SetSize(w, h int) {
 	v.height = h
 	v.diffVP.Width = w / 2
 	v.diffVP.Height = h - 2
+	if v.merging {
+		v.resizePreview()
+		v.renderPreview()
+	}
 }
 
 func (v *ConflictView) refresh() tea.Cmd {
 	return func() tea.Msg {
-		files, err := v.gitSvc.ConflictFiles()
+		files, err := v.gitSvc.ConflictDetails()
 		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
@@ -69,39 +95,371 @@ func (v *ConflictView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 		v.diffVP.SetContent(renderDiffColored(v.styles, msg.diff))
 		return v, nil
 
+	case conflictContentMsg:
+		doc := git.ParseConflicts(msg.content)
+		if len(doc.Blocks) == 0 {
+			return v, common.CmdInfo(msg.path + " has no conflict markers — press m to mark it resolved")
+		}
+		v.merging = true
+		v.mergePath = msg.path
+		v.doc = doc
+		v.choices = make([]git.ConflictChoice, len(doc.Blocks))
+		v.block = 0
+		v.showDiff = false
+		v.resizePreview()
+		v.renderPreview()
+		return v, nil
+
 	case common.RefreshMsg:
 		return v, v.refresh()
 
 	case tea.KeyMsg:
+		if v.merging {
+			return v.updateMerge(msg)
+		}
 		return v.handleKey(msg)
 	}
 	return v, nil
 }
 
 func (v *ConflictView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "j", "down":
+	switch v.keys.Action(msg) {
+	case "down":
 		if v.cursor < len(v.files)-1 {
 			v.cursor++
 		}
-	case "k", "up":
+	case "up":
 		if v.cursor > 0 {
 			v.cursor--
 		}
-	case "m": // Mark resolved
-		if v.cursor < len(v.files) {
-			return v, v.markResolved(v.files[v.cursor])
+	case "mark_resolved":
+		if f := v.selected(); f != nil {
+			return v, v.markResolved(f.Path)
+		}
+	case "take_ours": // whole file
+		if f := v.selected(); f != nil {
+			return v, v.resolveWithSide(f.Path, git.ConflictOurs)
+		}
+	case "take_theirs": // whole file
+		if f := v.selected(); f != nil {
+			return v, v.resolveWithSide(f.Path, git.ConflictTheirs)
+		}
+	case "merge":
+		f := v.selected()
+		if f == nil {
+			return v, nil
 		}
-	case "d", "enter": // Show diff
-		if v.cursor < len(v.files) {
-			return v, v.showConflictDiff(v.files[v.cursor])
+		switch {
+		case f.Binary:
+			return v, common.CmdInfo(fmt.Sprintf("Binary conflict — take a whole side with %s (ours) or %s (theirs)",
+				v.keys.First("take_ours"), v.keys.First("take_theirs")))
+		case !f.HasSide(git.ConflictOurs) || !f.HasSide(git.ConflictTheirs):
+			return v, common.CmdInfo(deleteModifyHint(*f, v.keys))
 		}
-	case "esc":
+		return v, v.openMerge(f.Path)
+	case "diff":
+		if f := v.selected(); f != nil {
+			return v, v.showConflictDiff(f.Path)
+		}
+	case "back":
 		v.showDiff = false
 	}
 	return v, nil
 }
 
+// deleteModifyHint explains what take_ours/take_theirs do for a file one
+// side deleted.
+func deleteModifyHint(f git.ConflictFile, ks keys.Set) string {
+	o, t := ks.First("take_ours"), ks.First("take_theirs")
+	switch {
+	case !f.HasSide(git.ConflictOurs) && !f.HasSide(git.ConflictTheirs):
+		return fmt.Sprintf("Deleted on both sides — %s/%s removes it, %s keeps the working copy",
+			o, t, ks.First("mark_resolved"))
+	case !f.HasSide(git.ConflictOurs):
+		return fmt.Sprintf("Deleted by us — %s deletes the file, %s keeps their version", o, t)
+	default:
+		return fmt.Sprintf("Deleted by them — %s keeps our version, %s deletes the file", o, t)
+	}
+}
+
+func (v *ConflictView) selected() *git.ConflictFile {
+	if v.cursor < 0 || v.cursor >= len(v.files) {
+		return nil
+	}
+	return &v.files[v.cursor]
+}
+
+// ── Merge editor ────────────────────────────────────────────────────────────
+
+func (v *ConflictView) updateMerge(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch v.mergeKs.Action(msg) {
+	case "next_block":
+		v.jumpBlock(1)
+	case "prev_block":
+		v.jumpBlock(-1)
+	case "ours":
+		v.choose(git.ChoiceOurs)
+	case "theirs":
+		v.choose(git.ChoiceTheirs)
+	case "both":
+		v.choose(git.ChoiceBoth)
+	case "base":
+		if v.doc.Blocks[v.block].HasBase {
+			v.choose(git.ChoiceBase)
+		}
+	case "unresolve":
+		v.choices[v.block] = git.ChoiceUnresolved
+		v.renderPreview()
+	case "all_ours":
+		v.chooseAll(git.ChoiceOurs)
+	case "all_theirs":
+		v.chooseAll(git.ChoiceTheirs)
+	case "down":
+		v.previewVP.ScrollDown(1)
+	case "up":
+		v.previewVP.ScrollUp(1)
+	case "page_down":
+		v.previewVP.HalfPageDown()
+	case "page_up":
+		v.previewVP.HalfPageUp()
+	case "write": // write the resolution and mark resolved
+		if n := v.unresolved(); n > 0 {
+			return v, common.CmdErr(fmt.Errorf("%d conflict block(s) still unresolved", n))
+		}
+		path, content := v.mergePath, v.doc.Resolve(v.choices)
+		v.closeMerge()
+		return v, v.resolveConflict(path, content)
+	case "cancel", "back":
+		v.closeMerge()
+	}
+	return v, nil
+}
+
+func (v *ConflictView) choose(c git.ConflictChoice) {
+	v.choices[v.block] = c
+	// Advance to the next unresolved block so a file can be resolved
+	// with a run of single keys.
+	for i := v.block + 1; i < len(v.choices); i++ {
+		if v.choices[i] == git.ChoiceUnresolved {
+			v.block = i
+			break
+		}
+	}
+	v.renderPreview()
+}
+
+func (v *ConflictView) chooseAll(c git.ConflictChoice) {
+	for i := range v.choices {
+		v.choices[i] = c
+	}
+	v.renderPreview()
+}
+
+func (v *ConflictView) jumpBlock(delta int) {
+	v.block = max(0, min(v.block+delta, len(v.doc.Blocks)-1))
+	v.renderPreview()
+}
+
+func (v *ConflictView) unresolved() int {
+	n := 0
+	for _, c := range v.choices {
+		if c == git.ChoiceUnresolved {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *ConflictView) closeMerge() {
+	v.merging = false
+	v.doc = nil
+	v.choices = nil
+	v.mergePath = ""
+}
+
+func (v *ConflictView) resizePreview() {
+	// Panel border (2) and padding (2) surround the viewport.
+	w := max(v.width-v.sidesWidth()-4, 10)
+	h := v.mergeBodyHeight()
+	if v.previewVP.Width == 0 {
+		v.previewVP = viewport.New(w, h)
+		return
+	}
+	v.previewVP.Width = w
+	v.previewVP.Height = h
+}
+
+// mergeBodyHeight leaves room for the header, spacer, panel border and
+// key hints.
+func (v *ConflictView) mergeBodyHeight() int { return max(v.height-5, 3) }
+
+// sidesWidth is the width of the left pane that shows the current block's
+// ours/base/theirs sections.
+func (v *ConflictView) sidesWidth() int { return v.width * 2 / 5 }
+
+// renderPreview renders the file with the current choices applied and
+// scrolls so the current block is in view.
+func (v *ConflictView) renderPreview() {
+	t := v.styles.Theme
+	gutter := func(c lipgloss.Color, current bool) string {
+		mark := "│ "
+		if current {
+			mark = "▌ "
+		}
+		return lipgloss.NewStyle().Foreground(c).Render(mark)
+	}
+
+	var lines []string
+	blockStart := 0
+	bi := 0
+	lang := v.styles.Syntax.Language(v.mergePath)
+	for _, seg := range v.doc.Segments {
+		if seg.Conflict == nil {
+			for _, l := range seg.Text {
+				lines = append(lines, "  "+v.renderCode(lang, l, v.styles.DiffContext))
+			}
+			continue
+		}
+		current := bi == v.block
+		if current {
+			blockStart = len(lines)
+		}
+		choice := v.choices[bi]
+		if choice == git.ChoiceUnresolved {
+			lines = append(lines, v.renderUnresolved(seg.Conflict, current)...)
+		} else {
+			g := gutter(t.Success, current)
+			for _, l := range seg.Conflict.Lines(choice) {
+				lines = append(lines, g+v.renderCode(lang, l, v.styles.Body))
+			}
+			if len(seg.Conflict.Lines(choice)) == 0 {
+				lines = append(lines, g+v.styles.Muted.Italic(true).Render("(empty)"))
+			}
+		}
+		bi++
+	}
+
+	v.previewVP.SetContent(strings.Join(lines, "\n"))
+	v.previewVP.SetYOffset(max(blockStart-3, 0))
+}
+
+// renderUnresolved renders a block with its markers, colouring each side.
+func (v *ConflictView) renderUnresolved(b *git.ConflictBlock, current bool) []string {
+	t := v.styles.Theme
+	marker := lipgloss.NewStyle().Foreground(t.Conflict).Bold(true)
+	mark := "  "
+	if current {
+		mark = marker.Render("▌ ")
+	}
+	var out []string
+	lang := v.styles.Syntax.Language(v.mergePath)
+	side := func(label string, ls []string, style lipgloss.Style) {
+		out = append(out, mark+marker.Render(label))
+		for _, l := range ls {
+			out = append(out, mark+v.renderCode(lang, l, style))
+		}
+	}
+	side(trimEOL(b.Markers[0]), b.Ours, v.styles.DiffAdded)
+	m := 1
+	if b.HasBase {
+		side(trimEOL(b.Markers[m]), b.Base, v.styles.Muted)
+		m++
+	}
+	side(trimEOL(b.Markers[m]), b.Theirs, v.styles.DiffRemoved)
+	out = append(out, mark+marker.Render(trimEOL(b.Markers[m+1])))
+	return out
+}
+
+// renderCode renders a line of the merged file in style, coloured as
+// lang.
+func (v *ConflictView) renderCode(lang, line string, style lipgloss.Style) string {
+	line = expandTabs(trimEOL(line))
+	return ui.RenderDiffLine(line, v.styles.Syntax.Line(lang, line), nil, style, style)
+}
+
+// viewSides renders the current block's alternatives side by side with
+// the key that picks each one.
+func (v *ConflictView) viewSides(width, height int) string {
+	t := v.styles.Theme
+	b := v.doc.Blocks[v.block]
+	choice := v.choices[v.block]
+
+	var sb strings.Builder
+	section := func(key, name, label string, ls []string, color lipgloss.Color, picked bool) {
+		head := lipgloss.NewStyle().Foreground(color).Bold(true).Render(name)
+		if label != "" {
+			head += v.styles.Muted.Render(" (" + ui.Truncate(label, max(width-len(name)-12, 8)) + ")")
+		}
+		if picked {
+			head += lipgloss.NewStyle().Foreground(t.Success).Render(" ✓")
+		}
+		sb.WriteString(ui.RenderKeyValue(v.styles, key, head) + "\n")
+		if len(ls) == 0 {
+			sb.WriteString(v.styles.Muted.Italic(true).Render("  (empty)") + "\n")
+		}
+		for _, l := range ls {
+			sb.WriteString(lipgloss.NewStyle().Foreground(color).
+				Render("  "+ui.Truncate(expandTabs(trimEOL(l)), max(width-4, 8))) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	mk := v.mergeKs
+	section(mk.First("ours"), "Ours", b.OursLabel, b.Ours, t.Added, choice == git.ChoiceOurs)
+	if b.HasBase {
+		section(mk.First("base"), "Base", b.BaseLabel, b.Base, t.TextMuted, choice == git.ChoiceBase)
+	}
+	section(mk.First("theirs"), "Theirs", b.TheirsLabel, b.Theirs, t.Deleted, choice == git.ChoiceTheirs)
+	both := "both (ours, then theirs)"
+	if choice == git.ChoiceBoth {
+		both += lipgloss.NewStyle().Foreground(t.Success).Render(" ✓")
+	}
+	sb.WriteString(ui.RenderKeyValue(v.styles, mk.First("both"), both) + "\n")
+
+	return lipgloss.NewStyle().Width(width).Height(height).MaxHeight(height).Render(sb.String())
+}
+
+func (v *ConflictView) viewMerge() string {
+	t := v.styles.Theme
+	status := fmt.Sprintf("block %d/%d", v.block+1, len(v.doc.Blocks))
+	if n := v.unresolved(); n > 0 {
+		status += fmt.Sprintf(" · %d unresolved", n)
+	} else {
+		status += " · all resolved — " + v.mergeKs.First("write") + " to write"
+	}
+	header := lipgloss.NewStyle().Foreground(t.Conflict).Bold(true).Render("  Merge "+v.mergePath) +
+		"  " + v.styles.Muted.Render(status)
+
+	bodyH := v.mergeBodyHeight()
+	left := v.viewSides(v.sidesWidth(), bodyH+2)
+	right := v.styles.Panel.Width(v.previewVP.Width + 2).Height(bodyH).Render(v.previewVP.View())
+	mk := v.mergeKs
+	hint := v.styles.Muted.Render("  " + mk.First("next_block") + "/" + mk.First("prev_block") + " block  " +
+		mk.Hints("ours", "ours", "theirs", "theirs", "both", "both", "base", "base", "unresolve", "undo") + "  " +
+		mk.First("all_ours") + "/" + mk.First("all_theirs") + " all  " +
+		mk.Hints("write", "write", "back", "cancel"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, "",
+		lipgloss.JoinHorizontal(lipgloss.Top, left, right), hint)
+}
+
+func trimEOL(s string) string { return strings.TrimRight(s, "\r\n") }
+
+// expandTabs keeps tab-indented source aligned inside fixed-width panes.
+func expandTabs(s string) string { return strings.ReplaceAll(s, "\t", "    ") }
+
+// ── Commands ────────────────────────────────────────────────────────────────
+
+func (v *ConflictView) openMerge(path string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := v.gitSvc.ConflictContent(path)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return conflictContentMsg{path: path, content: content}
+	}
+}
+
 func (v *ConflictView) markResolved(path string) tea.Cmd {
 	return func() tea.Msg {
 		if err := v.gitSvc.MarkResolved(path); err != nil {
@@ -111,6 +469,24 @@ func (v *ConflictView) markResolved(path string) tea.Cmd {
 	}
 }
 
+func (v *ConflictView) resolveConflict(path, content string) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.ResolveConflict(path, content); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.CmdRefresh()
+	}
+}
+
+func (v *ConflictView) resolveWithSide(path string, side git.ConflictSide) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.ResolveWithSide(path, side); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.CmdRefresh()
+	}
+}
+
 func (v *ConflictView) showConflictDiff(path string) tea.Cmd {
 	return func() tea.Msg {
 		diff, err := v.gitSvc.Diff(false, path)
@@ -122,6 +498,10 @@ func (v *ConflictView) showConflictDiff(path string) tea.Cmd {
 }
 
 func (v *ConflictView) View() string {
+	if v.merging {
+		return v.viewMerge()
+	}
+
 	t := v.styles.Theme
 	if len(v.files) == 0 {
 		return ui.PlaceCentre(v.width, v.height,
@@ -134,7 +514,11 @@ func (v *ConflictView) View() string {
 
 	for i, f := range v.files {
 		icon := lipgloss.NewStyle().Foreground(t.Conflict).Render("[U] ")
-		line := icon + v.styles.FileConflict.Render(f)
+		kind := f.Kind.String()
+		if f.Binary {
+			kind += ", binary"
+		}
+		line := icon + v.styles.FileConflict.Render(f.Path) + v.styles.Muted.Render("  "+kind)
 		if i == v.cursor {
 			b.WriteString(v.styles.ListSelected.Render("▸ "+line) + "\n")
 		} else {
@@ -142,7 +526,8 @@ func (v *ConflictView) View() string {
 		}
 	}
 
-	b.WriteString("\n" + v.styles.Muted.Render("  m mark resolved  d/enter show diff"))
+	b.WriteString("\n" + v.styles.Muted.Render("  "+v.keys.Hints("merge", "merge", "take_ours", "take ours", "take_theirs", "take theirs",
+		"mark_resolved", "mark resolved", "diff", "show diff")))
 
 	left := b.String()
 	if v.showDiff {
@@ -154,10 +539,28 @@ func (v *ConflictView) View() string {
 }
 
 func (v *ConflictView) ShortHelp() []components.HelpEntry {
+	if v.merging {
+		mk := v.mergeKs
+		return []components.HelpEntry{
+			{Key: mk.First("next_block") + " / " + mk.First("prev_block"), Desc: "Next / previous block"},
+			{Key: mk.Help("ours"), Desc: "Take ours"},
+			{Key: mk.Help("theirs"), Desc: "Take theirs"},
+			{Key: mk.Help("both"), Desc: "Take both"},
+			{Key: mk.Help("base"), Desc: "Take base (diff3)"},
+			{Key: mk.Help("unresolve"), Desc: "Unresolve block"},
+			{Key: mk.First("all_ours") + " / " + mk.First("all_theirs"), Desc: "Take ours / theirs for all blocks"},
+			{Key: mk.First("down") + " / " + mk.First("up"), Desc: "Scroll preview"},
+			{Key: mk.Help("write"), Desc: "Write file and mark resolved"},
+			{Key: mk.First("back") + " / " + mk.Help("cancel"), Desc: "Cancel merge"},
+		}
+	}
 	return []components.HelpEntry{
-		{Key: "m", Desc: "Mark resolved"},
-		{Key: "d / enter", Desc: "Show diff"},
+		{Key: v.keys.Help("merge"), Desc: "Open merge editor"},
+		{Key: v.keys.Help("take_ours"), Desc: "Take ours (whole file)"},
+		{Key: v.keys.Help("take_theirs"), Desc: "Take theirs (whole file)"},
+		{Key: v.keys.Help("mark_resolved"), Desc: "Mark resolved"},
+		{Key: v.keys.Help("diff"), Desc: "Show diff"},
 	}
 }
 
-func (v *ConflictView) InputCapture() bool { return false }
+func (v *ConflictView) InputCapture() bool { return v.merging }
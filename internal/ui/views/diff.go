@@ -6,32 +6,45 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
-	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// DiffView shows the diff for all changes or a selected file.
+// DiffView shows the working tree's staged and unstaged changes a file at
+// a time, or in compare mode the changes between two refs.
 type DiffView struct {
 	gitSvc     git.Service
 	styles     ui.Styles
+	cfg        *config.Config
+	keys       keys.Set
 	width      int
 	height     int
-	vp         viewport.Model
 	loaded     bool
-	rawDiff    string
+	browser    diffBrowser
 	sideBySide bool
+
+	// Compare mode (nil when off), and the picker for its two refs.
+	compare     *comparison
+	picker      refPicker
+	compareRefs []string
+	compareFrom string
 }
 
 // NewDiffView creates a new DiffView.
-func NewDiffView(gitSvc git.Service, styles ui.Styles) *DiffView {
+func NewDiffView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *DiffView {
 	return &DiffView{
-		gitSvc: gitSvc,
-		styles: styles,
-		vp:     viewport.New(0, 0),
+		gitSvc:     gitSvc,
+		styles:     styles,
+		cfg:        cfg,
+		keys:       keys.New(cfg.Keymap, "diff"),
+		browser:    newDiffBrowser(styles, keys.New(cfg.Keymap, "diff_browser"), cfg.SideBySideDiff),
+		sideBySide: cfg.SideBySideDiff,
+		picker:     newRefPicker(),
 	}
 }
 
@@ -40,83 +53,123 @@ func (v *DiffView) Init() tea.Cmd { return v.refresh() }
 func (v *DiffView) SetSize(w, h int) {
 	v.width = w
 	v.height = h
-	v.vp.Width = w
-	v.vp.Height = h - 2
+	// The last line holds the hints.
+	v.browser.SetSize(w, h-1)
+	if c := v.compare; c != nil {
+		_, bh := v.compareLayout()
+		c.browser.SetSize(w, bh)
+	}
 }
 
-type diffResultMsg struct{ diff string }
+type diffFilesMsg struct{ staged, unstaged []git.DiffFileStat }
 
 func (v *DiffView) refresh() tea.Cmd {
 	return func() tea.Msg {
-		unstaged, err := v.gitSvc.Diff(false, "")
+		staged, err := v.gitSvc.DiffFiles(true)
 		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
-		staged, err := v.gitSvc.Diff(true, "")
+		unstaged, err := v.gitSvc.DiffFiles(false)
 		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
-		combined := ""
-		if staged != "" {
-			combined += "=== STAGED CHANGES ===\n\n" + staged + "\n"
-		}
-		if unstaged != "" {
-			combined += "=== UNSTAGED CHANGES ===\n\n" + unstaged
-		}
-		if combined == "" {
-			combined = "No changes"
+		return diffFilesMsg{staged: staged, unstaged: unstaged}
+	}
+}
+
+// worktreeSections lists the staged and the unstaged files; each file's
+// diff is loaded when it is selected.
+func (v *DiffView) worktreeSections(msg diffFilesMsg) []diffSection {
+	var sections []diffSection
+	for _, s := range []struct {
+		label  string
+		staged bool
+		files  []git.DiffFileStat
+	}{{"Staged", true, msg.staged}, {"Unstaged", false, msg.unstaged}} {
+		if len(s.files) == 0 {
+			continue
 		}
-		return diffResultMsg{diff: combined}
+		staged := s.staged
+		sections = append(sections, diffSection{label: s.label, files: s.files,
+			load: func(f git.DiffFileStat) (string, error) { return v.gitSvc.DiffFile(staged, f.Path, f.OldPath) }})
 	}
+	return sections
+}
+
+// activeBrowser is the comparison's browser in compare mode and the
+// working tree's otherwise.
+func (v *DiffView) activeBrowser() *diffBrowser {
+	if v.compare != nil {
+		return &v.compare.browser
+	}
+	return &v.browser
 }
 
 func (v *DiffView) Update(msg tea.Msg) (common.View, tea.Cmd) {
+	if cmd, ok := v.updateCompareMsg(msg); ok {
+		return v, cmd
+	}
 	switch msg := msg.(type) {
-	case diffResultMsg:
+	case diffFilesMsg:
 		v.loaded = true
-		v.rawDiff = msg.diff
-		v.renderDiff()
-		v.vp.GotoTop()
-		return v, nil
+		return v, v.browser.SetSections(v.worktreeSections(msg))
 
-	case common.RefreshMsg:
-		return v, v.refresh()
+	case diffBrowserLoadedMsg:
+		if cmd, ok := v.browser.Update(msg); ok || v.compare == nil {
+			return v, cmd
+		}
+		cmd, _ := v.compare.browser.Update(msg)
+		return v, cmd
 
-	case tea.MouseMsg:
-		switch msg.Button {
-		case tea.MouseButtonWheelUp:
-			v.vp.ScrollUp(3)
-			return v, nil
-		case tea.MouseButtonWheelDown:
-			v.vp.ScrollDown(3)
-			return v, nil
+	case common.RefreshMsg:
+		if c := v.compare; c != nil {
+			return v, tea.Batch(v.refresh(), v.loadCompare(c.from, c.to, c.threeDot))
 		}
+		return v, v.refresh()
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
+		if v.picker.open {
+			return v, v.picker.Update(msg)
+		}
+		b := v.activeBrowser()
+		switch v.keys.Action(msg) {
+		case "compare":
+			return v, v.loadCompareRefs()
+		case "refresh":
 			return v, v.refresh()
-		case "v": // Toggle side-by-side
+		case "toggle_side_by_side":
 			v.sideBySide = !v.sideBySide
-			v.renderDiff()
+			v.browser.SetSideBySide(v.sideBySide)
+			if v.compare != nil {
+				v.compare.browser.SetSideBySide(v.sideBySide)
+			}
 			return v, nil
+		case "copy_diff":
+			if f, diff, ok := b.Current(); ok {
+				return v, copyText(v.cfg, "diff of "+f.Path, diff)
+			}
+			return v, nil
+		}
+		if v.compare != nil {
+			if cmd, ok := v.updateCompareKey(msg); ok {
+				return v, cmd
+			}
 		}
+		cmd, ok := b.Update(msg)
+		if !ok && v.compare != nil && v.keys.Action(msg) == "back" {
+			v.compare = nil
+		}
+		return v, cmd
 	}
 
-	var cmd tea.Cmd
-	v.vp, cmd = v.vp.Update(msg)
+	cmd, _ := v.activeBrowser().Update(msg)
 	return v, cmd
 }
 
-func (v *DiffView) renderDiff() {
-	if v.sideBySide {
-		v.vp.SetContent(components.RenderSideBySideDiff(v.styles, v.rawDiff, v.width))
-	} else {
-		v.vp.SetContent(renderDiffColored(v.styles, v.rawDiff))
-	}
-}
-
 func (v *DiffView) View() string {
+	if v.picker.open {
+		return v.picker.View(v.styles)
+	}
 	if !v.loaded {
 		return ui.PlaceCentre(v.width, v.height,
 			lipgloss.NewStyle().Foreground(v.styles.Theme.TextMuted).Render("Loading diff..."))
@@ -126,20 +179,41 @@ func (v *DiffView) View() string {
 	if v.sideBySide {
 		mode = "side-by-side"
 	}
-	hint := v.styles.Muted.Render("  [" + mode + "]  v toggle mode  r refresh")
-	return v.vp.View() + "\n" + hint
+	b := v.activeBrowser()
+	hints := "  [" + mode + "]  " + b.keys.Hints("next_file", "next file", "switch_pane", "files/diff") +
+		"  " + v.keys.Hints("toggle_side_by_side", "toggle mode")
+	// A wrapped hint line would push the browser up.
+	hint := lipgloss.NewStyle().MaxWidth(v.width)
+	if v.compare != nil {
+		hints += "  " + v.keys.Hints("toggle_range", "../...", "swap", "swap", "compare", "other refs", "back", "leave")
+		header, _ := v.compareLayout()
+		return strings.Join(append(header, b.View(), hint.Render(v.styles.Muted.Render(hints))), "\n")
+	}
+	hints += "  " + b.keys.Hints("next_hunk", "next hunk") + "  " +
+		v.keys.Hints("copy_diff", "copy", "compare", "compare refs", "refresh", "refresh")
+	return b.View() + "\n" + hint.Render(v.styles.Muted.Render(hints))
 }
 
 func (v *DiffView) ShortHelp() []components.HelpEntry {
+	b := v.browser.keys
 	return []components.HelpEntry{
-		{Key: "↑/↓", Desc: "Scroll"},
-		{Key: "ctrl+d/u", Desc: "Page down/up"},
-		{Key: "v", Desc: "Toggle side-by-side"},
-		{Key: "r", Desc: "Refresh"},
+		{Key: v.keys.First("up") + "/" + v.keys.First("down"), Desc: "Move through the files / scroll the diff"},
+		{Key: v.keys.First("page_down") + "/" + v.keys.First("page_up"), Desc: "Scroll the diff"},
+		{Key: b.Help("next_file") + ", " + b.Help("prev_file"), Desc: "Next / previous file"},
+		{Key: b.Help("next_hunk") + ", " + b.Help("prev_hunk"), Desc: "Next / previous hunk"},
+		{Key: b.Help("open"), Desc: "Fold a directory / focus the file's diff"},
+		{Key: b.Help("switch_pane"), Desc: "Switch between the files and the diff"},
+		{Key: v.keys.Help("toggle_side_by_side"), Desc: "Toggle side-by-side"},
+		{Key: v.keys.Help("copy_diff"), Desc: "Copy the file's diff"},
+		{Key: v.keys.Help("refresh"), Desc: "Refresh"},
+		{Key: v.keys.Help("compare"), Desc: "Compare two refs"},
+		{Key: v.keys.Help("toggle_range"), Desc: "Compare: toggle from..to / from...to"},
+		{Key: v.keys.Help("swap"), Desc: "Compare: swap the two sides"},
+		{Key: v.keys.Help("back"), Desc: "Compare: leave (from the files)"},
 	}
 }
 
-func (v *DiffView) InputCapture() bool { return false }
+func (v *DiffView) InputCapture() bool { return v.picker.open }
 
 // isGitDiffHeader reports whether the line is part of the per-file
 // metadata header that Git emits before the actual unified diff hunks.
@@ -180,6 +254,8 @@ func parseHunkRange(tok string) (int, int) {
 //   - Colored gutter indicators (green █ / red █) instead of +/-
 //   - Subtle background tints on added/removed lines
 //   - Stripped +/- prefixes from content
+//   - The words that changed within paired -/+ lines emphasised
+//   - Code coloured by language when styles.Syntax is set
 //
 // Inspired by GitHub, VS Code, and GitKraken diff views.
 func renderDiffColored(styles ui.Styles, diff string) string {
@@ -202,7 +278,10 @@ func renderDiffColored(styles ui.Styles, diff string) string {
 	oldLine, newLine := 0, 0
 	fileCount := 0
 
-	for _, line := range strings.Split(diff, "\n") {
+	lines := strings.Split(diff, "\n")
+	emph := ui.DiffEmphasis(lines)
+	lang := ""
+	for i, line := range lines {
 		// ── Section title (=== STAGED CHANGES === etc.) ──────────
 		if strings.HasPrefix(line, "===") {
 			if fileCount > 0 {
@@ -216,6 +295,7 @@ func renderDiffColored(styles ui.Styles, diff string) string {
 		// ── Git metadata header → enter header mode ─────────────
 		if strings.HasPrefix(line, "diff --git ") {
 			inHeader = true
+			lang = styles.Syntax.DiffLanguage(line)
 			continue
 		}
 
@@ -290,7 +370,8 @@ func renderDiffColored(styles ui.Styles, diff string) string {
 			b.WriteString(styles.DiffAddedGutter.Render("│"))
 			b.WriteString(styles.DiffAddedLineNum.Render(ln))
 			b.WriteString(styles.DiffAddedGutter.Render("│"))
-			b.WriteString(styles.DiffAdded.Render(" " + content))
+			b.WriteString(styles.DiffAdded.Render(" ") +
+				ui.RenderDiffLine(content, styles.Syntax.Line(lang, content), emph[i], styles.DiffAdded, styles.DiffAddedEmph))
 			newLine++
 
 		case strings.HasPrefix(line, "-"):
@@ -300,7 +381,8 @@ func renderDiffColored(styles ui.Styles, diff string) string {
 			b.WriteString(styles.DiffRemovedGutter.Render("│"))
 			b.WriteString(styles.DiffRemovedLineNum.Render(lnBlank))
 			b.WriteString(styles.DiffRemovedGutter.Render("│"))
-			b.WriteString(styles.DiffRemoved.Render(" " + content))
+			b.WriteString(styles.DiffRemoved.Render(" ") +
+				ui.RenderDiffLine(content, styles.Syntax.Line(lang, content), emph[i], styles.DiffRemoved, styles.DiffRemovedEmph))
 			oldLine++
 
 		default:
@@ -322,7 +404,13 @@ func renderDiffColored(styles ui.Styles, diff string) string {
 			b.WriteString(lipgloss.NewStyle().Foreground(t.Border).Render("│"))
 			b.WriteString(styles.DiffContextLineNum.Render(newLn))
 			b.WriteString(sep)
-			b.WriteString(styles.DiffContext.Render(" " + line))
+			content := strings.TrimPrefix(line, " ")
+			var syntax []ui.Span
+			if !strings.HasPrefix(line, "\\") { // "\ No newline at end of file"
+				syntax = styles.Syntax.Line(lang, content)
+			}
+			b.WriteString(styles.DiffContext.Render(" ") +
+				ui.RenderDiffLine(content, syntax, nil, styles.DiffContext, styles.DiffContext))
 		}
 
 		b.WriteByte('\n')
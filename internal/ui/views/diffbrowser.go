@@ -0,0 +1,579 @@
+package views
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── Diff browser ────────────────────────────────────────────────────────────
+
+// diffSection is a group of changed files in a diffBrowser, e.g. the staged
+// changes. load fetches one file's diff.
+type diffSection struct {
+	label string // tree row above the files; "" lists the files alone
+	files []git.DiffFileStat
+	load  func(git.DiffFileStat) (string, error)
+}
+
+// treeRow is a row of the diffBrowser's file tree: a section, a directory
+// or a file.
+type treeRow struct {
+	key     string // identifies the row across reloads
+	label   string
+	depth   int
+	section int
+	file    int      // index into the section's files; -1 for other rows
+	parents []string // keys of the section and directories holding a file
+}
+
+// loadedDiff is a file's diff, or why it couldn't be loaded.
+type loadedDiff struct {
+	diff string
+	err  error
+}
+
+// diffBrowser shows a diff a file at a time: a collapsible tree of the
+// changed files and their line counts on the left, the selected file's
+// diff on the right. A file's diff is loaded when it is first selected,
+// so a large change is never fetched, or cut short, as a whole.
+type diffBrowser struct {
+	id     int64 // tells this browser's loads from another's
+	gen    int   // bumped by SetSections, so loads from before are dropped
+	styles ui.Styles
+	keys   keys.Set
+	width  int
+	height int
+
+	sections  []diffSection
+	collapsed map[string]bool // section and directory rows, by key
+	rows      []treeRow       // the rows shown
+	order     []treeRow       // every file, in tree order, for next/prev
+	statW     int             // width of the widest "+a -d"
+	cursor    int
+	offset    int
+	focusDiff bool
+
+	vp         viewport.Model
+	sideBySide bool
+	diffs      map[string]loadedDiff // by file row key
+	shown      string                // key of the file in vp
+	hunks      []int                 // vp lines where its hunks start
+}
+
+// diffBrowserLoadedMsg carries a file's diff to the browser that asked.
+type diffBrowserLoadedMsg struct {
+	id   int64
+	gen  int
+	key  string
+	diff loadedDiff
+}
+
+var diffBrowserIDs atomic.Int64
+
+func newDiffBrowser(styles ui.Styles, km keys.Set, sideBySide bool) diffBrowser {
+	vp := viewport.New(0, 0)
+	vp.KeyMap = viewport.KeyMap{}
+	return diffBrowser{
+		id:         diffBrowserIDs.Add(1),
+		styles:     styles,
+		keys:       km,
+		collapsed:  make(map[string]bool),
+		vp:         vp,
+		sideBySide: sideBySide,
+		diffs:      make(map[string]loadedDiff),
+	}
+}
+
+// SetSections replaces the files shown, keeping the cursor on the same
+// file when it is still listed. Loaded diffs are dropped, so the selected
+// file is reloaded.
+func (b *diffBrowser) SetSections(sections []diffSection) tea.Cmd {
+	selected := ""
+	if row, ok := b.selected(); ok {
+		selected = row.key
+	}
+	b.gen++
+	b.sections = sections
+	clear(b.diffs)
+	b.buildRows()
+	found := false
+	for i, r := range b.rows {
+		if r.key == selected {
+			b.cursor, found = i, true
+		}
+	}
+	if !found {
+		// Start on the first file rather than a section or directory.
+		for i := len(b.rows) - 1; i >= 0; i-- {
+			if b.rows[i].file >= 0 {
+				b.cursor = i
+			}
+		}
+	}
+	return b.moveCursor(0)
+}
+
+// Empty reports whether there are no files to show.
+func (b *diffBrowser) Empty() bool { return len(b.order) == 0 }
+
+func (b *diffBrowser) SetSize(w, h int) {
+	b.width, b.height = w, max(h, 1)
+	b.vp.Width = max(w-b.treeWidth()-1, 10)
+	b.vp.Height = b.height
+	b.scrollTree()
+	if b.sideBySide {
+		b.render()
+	}
+}
+
+// minBrowserHeight is the fewest rows a header above a browser leaves it.
+const minBrowserHeight = 5
+
+// fitHeader cuts header short so that it and a browser under it fit in h
+// rows, and returns what's left of it with the browser's height.
+func fitHeader(header []string, h int) ([]string, int) {
+	n := max(min(len(header), h-minBrowserHeight), 0)
+	return header[:n], h - n
+}
+
+// SetSideBySide switches between the inline and side-by-side renderers.
+func (b *diffBrowser) SetSideBySide(on bool) {
+	b.sideBySide = on
+	b.render()
+}
+
+// Current returns the file shown and its diff, once loaded.
+func (b *diffBrowser) Current() (git.DiffFileStat, string, bool) {
+	for _, r := range b.order {
+		if r.key == b.shown {
+			d, ok := b.diffs[r.key]
+			return b.sections[r.section].files[r.file], d.diff, ok && d.err == nil
+		}
+	}
+	return git.DiffFileStat{}, "", false
+}
+
+func (b *diffBrowser) treeWidth() int { return min(max(b.width/3, 20), 40) }
+
+func (b *diffBrowser) selected() (treeRow, bool) {
+	if b.cursor < len(b.rows) {
+		return b.rows[b.cursor], true
+	}
+	return treeRow{}, false
+}
+
+// ── Tree ────────────────────────────────────────────────────────────────────
+
+// dirNode is a directory of changed files.
+type dirNode struct {
+	name  string
+	dirs  []*dirNode
+	files []int // indexes into the section's files
+}
+
+func (n *dirNode) child(name string) *dirNode {
+	for _, d := range n.dirs {
+		if d.name == name {
+			return d
+		}
+	}
+	d := &dirNode{name: name}
+	n.dirs = append(n.dirs, d)
+	return d
+}
+
+// buildRows lays the sections out as a tree, skipping the contents of
+// collapsed rows.
+func (b *diffBrowser) buildRows() {
+	b.rows, b.order, b.statW = nil, nil, 0
+	for si, s := range b.sections {
+		root := &dirNode{}
+		for i, f := range s.files {
+			n := root
+			if dir := path.Dir(f.Path); dir != "." {
+				for _, name := range strings.Split(dir, "/") {
+					n = n.child(name)
+				}
+			}
+			n.files = append(n.files, i)
+			b.statW = max(b.statW, len(fileStat(f)))
+		}
+		key := s.label + "\x00"
+		var parents []string
+		depth := 0
+		if s.label != "" {
+			b.rows = append(b.rows, treeRow{key: key, label: fmt.Sprintf("%s (%d)", s.label, len(s.files)), section: si, file: -1})
+			parents = []string{key}
+			depth = 1
+		}
+		b.addDir(si, key, root, "", depth, parents, b.collapsed[key] && s.label != "")
+	}
+	b.scrollTree()
+}
+
+// addDir adds the rows under n, whose path is dir. A chain of directories
+// holding nothing but the next is shown as one row, "a/b/c".
+func (b *diffBrowser) addDir(section int, key string, n *dirNode, dir string, depth int, parents []string, hidden bool) {
+	for _, d := range n.dirs {
+		label := d.name
+		for len(d.dirs) == 1 && len(d.files) == 0 {
+			d = d.dirs[0]
+			label += "/" + d.name
+		}
+		p := dir + label + "/"
+		row := treeRow{key: key + p, label: label + "/", depth: depth, section: section, file: -1}
+		if !hidden {
+			b.rows = append(b.rows, row)
+		}
+		b.addDir(section, key, d, p, depth+1, append(parents[:len(parents):len(parents)], row.key),
+			hidden || b.collapsed[row.key])
+	}
+	for _, i := range n.files {
+		f := b.sections[section].files[i]
+		row := treeRow{key: key + f.Path, label: path.Base(f.Path), depth: depth, section: section, file: i, parents: parents}
+		b.order = append(b.order, row)
+		if !hidden {
+			b.rows = append(b.rows, row)
+		}
+	}
+}
+
+// fileStat formats a file's line counts.
+func fileStat(f git.DiffFileStat) string {
+	if f.Binary {
+		return "bin"
+	}
+	return fmt.Sprintf("+%d -%d", f.Added, f.Deleted)
+}
+
+// moveCursor moves the tree cursor by delta and shows the file under it.
+func (b *diffBrowser) moveCursor(delta int) tea.Cmd {
+	b.cursor = max(min(b.cursor+delta, len(b.rows)-1), 0)
+	b.scrollTree()
+	return b.show()
+}
+
+// scrollTree keeps the cursor within the tree rows shown.
+func (b *diffBrowser) scrollTree() {
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+b.height {
+		b.offset = b.cursor - b.height + 1
+	}
+	b.offset = max(min(b.offset, len(b.rows)-b.height), 0)
+}
+
+// jumpFile selects the file delta places from the one shown, unfolding
+// the rows it is in.
+func (b *diffBrowser) jumpFile(delta int) tea.Cmd {
+	cur := -1
+	for i, r := range b.order {
+		if r.key == b.shown {
+			cur = i
+		}
+	}
+	next := cur + delta
+	if cur < 0 {
+		next = 0
+	}
+	if next < 0 || next >= len(b.order) {
+		return nil
+	}
+	target := b.order[next]
+	for _, p := range target.parents {
+		delete(b.collapsed, p)
+	}
+	b.buildRows()
+	for i, r := range b.rows {
+		if r.key == target.key {
+			b.cursor = i
+		}
+	}
+	return b.moveCursor(0)
+}
+
+// jumpHunk scrolls to the next (delta 1) or previous (-1) hunk, moving on
+// to the next or previous file after the last or first.
+func (b *diffBrowser) jumpHunk(delta int) tea.Cmd {
+	y := b.vp.YOffset
+	if delta > 0 {
+		for _, h := range b.hunks {
+			if h > y && !b.vp.AtBottom() {
+				b.vp.SetYOffset(h)
+				return nil
+			}
+		}
+		return b.jumpFile(1)
+	}
+	for i := len(b.hunks) - 1; i >= 0; i-- {
+		if b.hunks[i] < y {
+			b.vp.SetYOffset(b.hunks[i])
+			return nil
+		}
+	}
+	return b.jumpFile(-1)
+}
+
+// ── Diff pane ───────────────────────────────────────────────────────────────
+
+// show puts the selected file in the diff pane, loading its diff if
+// needed. On a section or directory the last file stays.
+func (b *diffBrowser) show() tea.Cmd {
+	row, ok := b.selected()
+	if !ok {
+		b.shown, b.hunks = "", nil
+		b.vp.SetContent("")
+		return nil
+	}
+	if row.file < 0 {
+		return nil
+	}
+	if row.key != b.shown {
+		b.shown = row.key
+		b.vp.GotoTop()
+		if _, loaded := b.diffs[row.key]; loaded {
+			b.render()
+		} else {
+			b.hunks = nil
+			b.vp.SetContent(b.styles.Muted.Render("  Loading diff..."))
+		}
+	}
+	if _, loaded := b.diffs[row.key]; loaded {
+		return nil
+	}
+	s := b.sections[row.section]
+	f := s.files[row.file]
+	id, gen := b.id, b.gen
+	return func() tea.Msg {
+		diff, err := s.load(f)
+		return diffBrowserLoadedMsg{id: id, gen: gen, key: row.key, diff: loadedDiff{diff, err}}
+	}
+}
+
+// render renders the shown file's diff into the viewport.
+func (b *diffBrowser) render() {
+	d, ok := b.diffs[b.shown]
+	if !ok {
+		return
+	}
+	if d.err != nil {
+		b.hunks = nil
+		b.vp.SetContent(b.styles.Muted.Render("  " + d.err.Error()))
+		return
+	}
+	var content string
+	if b.sideBySide {
+		content, b.hunks = components.RenderSideBySideDiffHunks(b.styles, strings.TrimSuffix(d.diff, "\n"), b.vp.Width)
+	} else {
+		content, b.hunks = renderFileDiff(b.styles, d.diff)
+	}
+	if strings.Contains(d.diff, "... (diff truncated") {
+		content += "\n" + b.styles.Muted.Render("  ... (diff truncated — exceeds 512 KB) ...")
+	}
+	b.vp.SetContent(content)
+}
+
+// renderFileDiff renders a file's diff with renderHunkRows, returning the
+// line each hunk starts on. Files without hunks (binary files, renames,
+// mode changes) show what git says about them instead.
+func renderFileDiff(styles ui.Styles, diff string) (string, []int) {
+	files := git.ParseDiff(diff)
+	if len(files) == 0 {
+		return styles.Muted.Render("  No changes"), nil
+	}
+	var lines []string
+	var hunks []int
+	for _, f := range files {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		rows, refs := renderHunkRows(styles, &f)
+		for i, ref := range refs {
+			if ref.Hunk >= 0 && ref.Line < 0 {
+				hunks = append(hunks, len(lines)+i)
+			}
+		}
+		lines = append(lines, rows...)
+		if len(f.Hunks) > 0 {
+			continue
+		}
+		if f.Binary {
+			lines = append(lines, styles.Muted.Render("  Binary file"))
+			continue
+		}
+		for _, h := range f.Header {
+			if !strings.HasPrefix(h, "diff --git ") && !strings.HasPrefix(h, "index ") {
+				lines = append(lines, styles.Muted.Render("  "+h))
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), hunks
+}
+
+// ── Update / View ───────────────────────────────────────────────────────────
+
+// Update handles the browser's loads and, while it has input, keys and
+// the mouse wheel. ok is false for messages it doesn't use, which the
+// caller then handles.
+func (b *diffBrowser) Update(msg tea.Msg) (cmd tea.Cmd, ok bool) {
+	switch msg := msg.(type) {
+	case diffBrowserLoadedMsg:
+		if msg.id != b.id {
+			return nil, false
+		}
+		if msg.gen == b.gen {
+			b.diffs[msg.key] = msg.diff
+			if msg.key == b.shown {
+				b.render()
+			}
+		}
+		return nil, true
+
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			b.vp.ScrollUp(3)
+			return nil, true
+		case tea.MouseButtonWheelDown:
+			b.vp.ScrollDown(3)
+			return nil, true
+		}
+
+	case tea.KeyMsg:
+		return b.handleKey(msg)
+	}
+	return nil, false
+}
+
+func (b *diffBrowser) handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch b.keys.Action(msg) {
+	case "next_file":
+		return b.jumpFile(1), true
+	case "prev_file":
+		return b.jumpFile(-1), true
+	case "next_hunk":
+		return b.jumpHunk(1), true
+	case "prev_hunk":
+		return b.jumpHunk(-1), true
+	case "switch_pane":
+		b.focusDiff = !b.focusDiff
+	case "open":
+		row, ok := b.selected()
+		switch {
+		case b.focusDiff || !ok:
+		case row.file >= 0:
+			b.focusDiff = true
+		default:
+			b.collapsed[row.key] = !b.collapsed[row.key]
+			b.buildRows()
+			return b.moveCursor(0), true
+		}
+	case "back":
+		if !b.focusDiff {
+			return nil, false
+		}
+		b.focusDiff = false
+	case "page_down":
+		b.vp.HalfPageDown()
+	case "page_up":
+		b.vp.HalfPageUp()
+	case "down":
+		if !b.focusDiff {
+			return b.moveCursor(1), true
+		}
+		b.vp.ScrollDown(1)
+	case "up":
+		if !b.focusDiff {
+			return b.moveCursor(-1), true
+		}
+		b.vp.ScrollUp(1)
+	case "top":
+		if !b.focusDiff {
+			return b.moveCursor(-len(b.rows)), true
+		}
+		b.vp.GotoTop()
+	case "bottom":
+		if !b.focusDiff {
+			return b.moveCursor(len(b.rows)), true
+		}
+		b.vp.GotoBottom()
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func (b *diffBrowser) View() string {
+	if b.height <= 0 {
+		return "" // not sized yet
+	}
+	if b.Empty() {
+		return ui.PlaceCentre(b.width, b.height, b.styles.Muted.Render("No changes"))
+	}
+	t := b.styles.Theme
+	treeW := b.treeWidth()
+	marker := lipgloss.NewStyle().Foreground(t.TextMuted)
+	section := lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	selected := b.styles.ListSelected.PaddingLeft(0)
+
+	lines := make([]string, 0, b.height)
+	end := min(len(b.rows), b.offset+b.height)
+	for i := b.offset; i < end; i++ {
+		r := b.rows[i]
+		indent := strings.Repeat("  ", r.depth)
+		if r.file < 0 {
+			fold := "▾ "
+			if b.collapsed[r.key] {
+				fold = "▸ "
+			}
+			label := ui.Truncate(r.label, treeW-len(indent)-3)
+			line := indent + fold + label
+			switch {
+			case i == b.cursor && !b.focusDiff:
+				line = selected.Render(ui.PadRight(line, treeW-1))
+			case r.depth == 0 && b.sections[r.section].label != "":
+				line = indent + marker.Render(fold) + section.Render(label)
+			default:
+				line = indent + marker.Render(fold) + label
+			}
+			lines = append(lines, ui.PadRight(line, treeW))
+			continue
+		}
+
+		f := b.sections[r.section].files[r.file]
+		label := ui.PadRight(ui.Truncate(indent+"  "+r.label, treeW-b.statW-2), treeW-b.statW-2)
+		stat := b.styles.Muted.Render(fmt.Sprintf("%*s", b.statW, "bin"))
+		if !f.Binary {
+			add, del := fmt.Sprintf("+%d", f.Added), fmt.Sprintf("-%d", f.Deleted)
+			stat = strings.Repeat(" ", b.statW-len(add)-len(del)-1) +
+				b.styles.DiffAdded.Render(add) + " " + b.styles.DiffRemoved.Render(del)
+		}
+		switch {
+		case i == b.cursor && !b.focusDiff:
+			label = selected.Render(label)
+		case r.key == b.shown:
+			label = b.styles.Bold.Render(label)
+		}
+		lines = append(lines, ui.PadRight(label+" "+stat, treeW))
+	}
+	for len(lines) < b.height {
+		lines = append(lines, strings.Repeat(" ", treeW))
+	}
+
+	sep := lipgloss.NewStyle().Foreground(t.Border).Render(strings.Repeat("│\n", b.height-1) + "│")
+	if b.focusDiff {
+		sep = lipgloss.NewStyle().Foreground(t.Primary).Render(strings.Repeat("┃\n", b.height-1) + "┃")
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, strings.Join(lines, "\n"), sep, b.vp.View())
+}
@@ -0,0 +1,38 @@
+package views
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorCmd builds the command for an editor setting such as "vim" or
+// "code --wait", with the files appended as arguments.
+func editorCmd(editor string, files ...string) (*exec.Cmd, error) {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil, errors.New("no editor configured")
+	}
+	args := append(fields[1:], files...)
+	return exec.Command(fields[0], args...), nil //nolint:gosec // user-configured editor
+}
+
+// openInEditor suspends the TUI, runs the editor on path (relative to the
+// repo root) and refreshes when it exits.
+func openInEditor(editor, root, path string) tea.Cmd {
+	cmd, err := editorCmd(editor, filepath.Join(root, path))
+	if err != nil {
+		return common.CmdErr(err)
+	}
+	cmd.Dir = root
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.CmdRefresh()
+	})
+}
@@ -0,0 +1,102 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diffRowRef maps a rendered diff row back to the parsed hunk model.
+// Hunk is -1 for decoration rows (file header, separators); Line is -1
+// for the hunk spacer row.
+type diffRowRef struct {
+	Hunk int
+	Line int
+}
+
+func (r diffRowRef) isLine() bool { return r.Hunk >= 0 && r.Line >= 0 }
+
+// renderHunkRows renders a single parsed file diff in the same visual
+// language as renderDiffColored, but row-by-row so callers can overlay a
+// line cursor and selection. rows and refs are parallel slices.
+func renderHunkRows(styles ui.Styles, f *git.FileDiff) (rows []string, refs []diffRowRef) {
+	const lnW = 4
+	lnFmt := fmt.Sprintf("%%%dd", lnW)
+	lnBlank := strings.Repeat(" ", lnW)
+	ctxSep := lipgloss.NewStyle().Foreground(styles.Theme.Border).Render("│")
+
+	rows = append(rows, styles.DiffHeader.Render("  ▎ "+f.Path()))
+	refs = append(refs, diffRowRef{Hunk: -1, Line: -1})
+	rows = append(rows, styles.DiffSeparator.Render(
+		strings.Repeat("─", lnW)+"┬"+strings.Repeat("─", lnW)+"┬"+strings.Repeat("─", 40)))
+	refs = append(refs, diffRowRef{Hunk: -1, Line: -1})
+
+	lang := styles.Syntax.Language(f.Path())
+	for hi, h := range f.Hunks {
+		kinds := make([]byte, len(h.Lines))
+		contents := make([]string, len(h.Lines))
+		for li, l := range h.Lines {
+			kinds[li], contents[li] = byte(l.Kind), l.Content
+		}
+		emph := ui.LineEmphasis(kinds, contents)
+
+		spacer := lnBlank + "│" + lnBlank + "│" + "  ···"
+		if h.Section != "" {
+			spacer += " " + h.Section
+		}
+		rows = append(rows, styles.DiffSeparator.Render(spacer))
+		refs = append(refs, diffRowRef{Hunk: hi, Line: -1})
+
+		for li, l := range h.Lines {
+			var b strings.Builder
+			switch l.Kind {
+			case git.DiffLineAdded:
+				b.WriteString(styles.DiffAddedLineNum.Render(lnBlank))
+				b.WriteString(styles.DiffAddedGutter.Render("│"))
+				b.WriteString(styles.DiffAddedLineNum.Render(fmt.Sprintf(lnFmt, l.NewLine)))
+				b.WriteString(styles.DiffAddedGutter.Render("│"))
+				b.WriteString(styles.DiffAdded.Render(" ") +
+					ui.RenderDiffLine(l.Content, styles.Syntax.Line(lang, l.Content), emph[li], styles.DiffAdded, styles.DiffAddedEmph))
+			case git.DiffLineRemoved:
+				b.WriteString(styles.DiffRemovedLineNum.Render(fmt.Sprintf(lnFmt, l.OldLine)))
+				b.WriteString(styles.DiffRemovedGutter.Render("│"))
+				b.WriteString(styles.DiffRemovedLineNum.Render(lnBlank))
+				b.WriteString(styles.DiffRemovedGutter.Render("│"))
+				b.WriteString(styles.DiffRemoved.Render(" ") +
+					ui.RenderDiffLine(l.Content, styles.Syntax.Line(lang, l.Content), emph[li], styles.DiffRemoved, styles.DiffRemovedEmph))
+			case git.DiffLineNoNewline:
+				b.WriteString(styles.DiffContextLineNum.Render(lnBlank) + ctxSep +
+					styles.DiffContextLineNum.Render(lnBlank) + ctxSep)
+				b.WriteString(styles.Muted.Italic(true).Render(" " + l.Content))
+			default:
+				b.WriteString(styles.DiffContextLineNum.Render(fmt.Sprintf(lnFmt, l.OldLine)))
+				b.WriteString(ctxSep)
+				b.WriteString(styles.DiffContextLineNum.Render(fmt.Sprintf(lnFmt, l.NewLine)))
+				b.WriteString(ctxSep)
+				b.WriteString(styles.DiffContext.Render(" ") +
+					ui.RenderDiffLine(l.Content, styles.Syntax.Line(lang, l.Content), nil, styles.DiffContext, styles.DiffContext))
+			}
+			rows = append(rows, b.String())
+			refs = append(refs, diffRowRef{Hunk: hi, Line: li})
+		}
+	}
+	return rows, refs
+}
+
+// rowSelector builds a git.LineSelector covering the inclusive row range
+// [from, to] of a rendered hunk view.
+func rowSelector(refs []diffRowRef, from, to int) git.LineSelector {
+	if from > to {
+		from, to = to, from
+	}
+	picked := make(map[diffRowRef]bool, to-from+1)
+	for i := max(from, 0); i <= to && i < len(refs); i++ {
+		if refs[i].isLine() {
+			picked[refs[i]] = true
+		}
+	}
+	return func(h, l int) bool { return picked[diffRowRef{Hunk: h, Line: l}] }
+}
@@ -2,25 +2,28 @@ package views
 
 import (
 	"fmt"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// defaultLogLimit controls how many commits to load. Kept modest to ensure
-// fast rendering even on repos with thousands of branches/tags.
-const defaultLogLimit = 100
-
 // LogView shows the commit log with an ASCII graph.
 type LogView struct {
 	gitSvc  git.Service
 	styles  ui.Styles
+	cfg     *config.Config
+	keys    keys.Set
+	limit   int // commits to load (config: max_log_entries)
 	width   int
 	height  int
 	entries []git.GraphEntry
@@ -28,17 +31,50 @@ type LogView struct {
 	cursor  int
 	vp      viewport.Model
 
-	// Detail pane.
-	showDetail bool
-	detailVP   viewport.Model
+	// Detail pane: the commit shown and the files it changed. While it is
+	// open, keys go to the file browser first.
+	showDetail   bool
+	detailCommit *git.Commit
+	detail       diffBrowser
+
+	// Copy menu for the selected commit.
+	copy copyMenu
+
+	// Tag, reset and reword/squash prompts for the selected commit.
+	form   tagForm
+	reset  resetForm
+	reword rewordForm
+
+	// Commits marked for cherry-pick or revert, by hash.
+	marked map[string]bool
+
+	// Cherry-pick / revert options prompt.
+	picking     bool
+	pickKeys    keys.Set
+	pickRevert  bool
+	pickHashes  []string // in the order they will be applied
+	pickOpts    git.PickOptions
+	maxMainline int // most parents among pickHashes; 0 when none is a merge
+
+	// sequencer is "cherry-pick" or "revert" while one is stopped.
+	sequencer string
 }
 
 // NewLogView creates a new LogView.
-func NewLogView(gitSvc git.Service, styles ui.Styles) *LogView {
+func NewLogView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *LogView {
 	return &LogView{
-		gitSvc: gitSvc,
-		styles: styles,
-		vp:     viewport.New(0, 0),
+		gitSvc:   gitSvc,
+		styles:   styles,
+		cfg:      cfg,
+		keys:     keys.New(cfg.Keymap, "log"),
+		pickKeys: keys.New(cfg.Keymap, "log_pick"),
+		copy:     newCopyMenu(cfg),
+		limit:    cfg.MaxLogEntries,
+		vp:       viewport.New(0, 0),
+		detail:   newDiffBrowser(styles, keys.New(cfg.Keymap, "diff_browser"), false),
+		form:     newTagForm(),
+		reword:   newRewordForm(cfg),
+		marked:   make(map[string]bool),
 	}
 }
 
@@ -49,30 +85,60 @@ func (v *LogView) SetSize(w, h int) {
 	v.height = h
 	v.vp.Width = w
 	v.vp.Height = h - 2
-	v.detailVP.Width = w / 2
-	v.detailVP.Height = h - 2
+	v.sizeDetail()
+}
+
+// sizeDetail fits the file browser into the detail panel, under the
+// commit header and above the hints.
+func (v *LogView) sizeDetail() {
+	if v.detailCommit == nil {
+		return
+	}
+	_, h := v.detailLayout()
+	v.detail.SetSize(v.width/2-4, h)
+}
+
+// detailLayout returns the commit header, cut short on a short terminal,
+// and the height left for the file browser under it.
+func (v *LogView) detailLayout() ([]string, int) {
+	// The panel's border and padding take 4 columns and 2 rows, and the
+	// hints one row.
+	return fitHeader(v.commitHeader(v.detailCommit), v.height-2-1)
 }
 
 type logResultMsg struct {
-	entries []git.GraphEntry
-	commits []git.Commit
+	entries   []git.GraphEntry
+	commits   []git.Commit
+	sequencer string
 }
 
+type (
+	logOpDoneMsg   struct{ info string }
+	logConflictMsg struct{ info string }
+)
+
 type commitDetailMsg struct {
 	commit *git.Commit
-	diff   string
+	files  []git.DiffFileStat
 }
 
 func (v *LogView) refresh() tea.Cmd {
 	return func() tea.Msg {
-		entries, err := v.gitSvc.LogGraph(defaultLogLimit)
+		var sequencer string
+		switch {
+		case v.gitSvc.IsCherryPicking():
+			sequencer = "cherry-pick"
+		case v.gitSvc.IsReverting():
+			sequencer = "revert"
+		}
+		entries, err := v.gitSvc.LogGraph(v.limit)
 		if err != nil {
 			// Fall back to non-graph log.
-			commits, err2 := v.gitSvc.Log(defaultLogLimit)
+			commits, err2 := v.gitSvc.Log(v.limit)
 			if err2 != nil {
 				return common.ErrMsg{Err: err2}
 			}
-			return logResultMsg{commits: commits}
+			return logResultMsg{commits: commits, sequencer: sequencer}
 		}
 		var commits []git.Commit
 		for _, e := range entries {
@@ -80,7 +146,7 @@ func (v *LogView) refresh() tea.Cmd {
 				commits = append(commits, *e.Commit)
 			}
 		}
-		return logResultMsg{entries: entries, commits: commits}
+		return logResultMsg{entries: entries, commits: commits, sequencer: sequencer}
 	}
 }
 
@@ -89,6 +155,7 @@ func (v *LogView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 	case logResultMsg:
 		v.entries = msg.entries
 		v.commits = msg.commits
+		v.sequencer = msg.sequencer
 		if v.cursor >= len(v.commits) && len(v.commits) > 0 {
 			v.cursor = len(v.commits) - 1
 		}
@@ -97,9 +164,28 @@ func (v *LogView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 
 	case commitDetailMsg:
 		v.showDetail = true
-		v.detailVP = viewport.New(v.width/2, v.height-2)
-		v.detailVP.SetContent(v.renderCommitDetail(msg.commit, msg.diff))
-		return v, nil
+		v.detailCommit = msg.commit
+		v.sizeDetail()
+		hash := msg.commit.Hash
+		return v, v.detail.SetSections([]diffSection{{files: msg.files,
+			load: func(f git.DiffFileStat) (string, error) { return v.gitSvc.CommitFile(hash, f.Path, f.OldPath) }}})
+
+	case diffBrowserLoadedMsg:
+		cmd, _ := v.detail.Update(msg)
+		return v, cmd
+
+	case tagOpDoneMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+
+	case logOpDoneMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+
+	case resetOpDoneMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+
+	case logConflictMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh,
+			func() tea.Msg { return common.SwitchTabMsg{Tab: common.TabConflicts} })
 
 	case common.RefreshMsg:
 		return v, v.refresh()
@@ -108,21 +194,36 @@ func (v *LogView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 		return v.handleMouse(msg)
 
 	case tea.KeyMsg:
+		if v.copy.open {
+			return v, v.copy.Update(v.cfg, msg)
+		}
+		if v.form.open {
+			return v, v.form.Update(v.gitSvc, msg)
+		}
+		if v.reset.open {
+			return v, v.reset.Update(v.gitSvc, v.cfg, msg)
+		}
+		if v.reword.open {
+			return v, v.reword.Update(v.gitSvc, msg)
+		}
+		if v.picking {
+			return v.updatePick(msg)
+		}
+		if v.showDetail {
+			if cmd, ok := v.detail.Update(msg); ok {
+				return v, cmd
+			}
+		}
 		return v.handleKey(msg)
 	}
-
-	var cmd tea.Cmd
-	if v.showDetail {
-		v.detailVP, cmd = v.detailVP.Update(msg)
-	}
-	return v, cmd
+	return v, nil
 }
 
 func (v *LogView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 	switch msg.Button {
 	case tea.MouseButtonWheelUp:
 		if v.showDetail {
-			v.detailVP.ScrollUp(3)
+			v.detail.Update(msg)
 		} else {
 			v.vp.ScrollUp(3)
 			if v.cursor > 0 {
@@ -132,7 +233,7 @@ func (v *LogView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 		}
 	case tea.MouseButtonWheelDown:
 		if v.showDetail {
-			v.detailVP.ScrollDown(3)
+			v.detail.Update(msg)
 		} else {
 			v.vp.ScrollDown(3)
 			if v.cursor < len(v.commits)-1 {
@@ -158,69 +259,359 @@ func (v *LogView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 }
 
 func (v *LogView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "j", "down":
+	switch v.keys.Action(msg) {
+	case "down":
 		if v.cursor < len(v.commits)-1 {
 			v.cursor++
 			v.rebuildContent()
 		}
-	case "k", "up":
+	case "up":
 		if v.cursor > 0 {
 			v.cursor--
 			v.rebuildContent()
 		}
-	case "g", "home":
+	case "top":
 		v.cursor = 0
 		v.rebuildContent()
-	case "G", "end":
+	case "bottom":
 		if len(v.commits) > 0 {
 			v.cursor = len(v.commits) - 1
 			v.rebuildContent()
 		}
-	case "enter", "d":
+	case "detail":
 		if v.cursor < len(v.commits) {
 			c := v.commits[v.cursor]
 			return v, v.loadDetail(c.Hash)
 		}
-	case "y":
+	case "copy_hash":
+		if v.cursor < len(v.commits) {
+			c := v.commits[v.cursor]
+			return v, copyText(v.cfg, "hash "+c.ShortHash, c.ShortHash)
+		}
+	case "copy":
+		if v.cursor < len(v.commits) {
+			v.openCopy(v.commits[v.cursor])
+		}
+	case "tag":
+		if v.cursor < len(v.commits) {
+			c := v.commits[v.cursor]
+			return v, v.form.Open(c.Hash, c.ShortHash)
+		}
+	case "reset":
+		if v.cursor < len(v.commits) {
+			c := v.commits[v.cursor]
+			v.reset.Open(c.Hash, c.ShortHash)
+		}
+	case "fixup":
+		if v.cursor < len(v.commits) {
+			return v, v.fixup(v.commits[v.cursor])
+		}
+	case "squash":
+		if v.cursor < len(v.commits) {
+			return v, v.reword.Open(v.commits[v.cursor], true, v.width, v.height)
+		}
+	case "reword":
 		if v.cursor < len(v.commits) {
-			return v, common.CmdInfo("Copied: " + v.commits[v.cursor].ShortHash)
+			return v, v.reword.Open(v.commits[v.cursor], false, v.width, v.height)
+		}
+	case "autosquash":
+		if v.cursor < len(v.commits) {
+			return v, v.autosquash(v.commits[v.cursor])
+		}
+	case "mark":
+		if v.cursor < len(v.commits) {
+			h := v.commits[v.cursor].Hash
+			if v.marked[h] {
+				delete(v.marked, h)
+			} else {
+				v.marked[h] = true
+			}
+			if v.cursor < len(v.commits)-1 {
+				v.cursor++
+			}
+			v.rebuildContent()
+		}
+	case "compare":
+		return v, v.compare()
+	case "cherry_pick":
+		v.openPick(false)
+	case "revert":
+		v.openPick(true)
+	case "continue":
+		if v.sequencer != "" {
+			return v, v.sequencerStep("Continued "+v.sequencer, v.gitSvc.SequencerContinue)
+		}
+	case "skip":
+		if v.sequencer != "" {
+			return v, v.sequencerStep("Skipped commit", v.gitSvc.SequencerSkip)
+		}
+	case "abort":
+		if v.sequencer != "" {
+			return v, v.sequencerStep("Aborted "+v.sequencer, v.gitSvc.SequencerAbort)
+		}
+	case "back":
+		if !v.showDetail && len(v.marked) > 0 {
+			clear(v.marked)
+			v.rebuildContent()
 		}
-	case "esc":
 		v.showDetail = false
-	case "ctrl+d", "pgdown":
+	case "page_down":
 		v.vp.HalfPageDown()
-	case "ctrl+u", "pgup":
+	case "page_up":
 		v.vp.HalfPageUp()
 	}
 	return v, nil
 }
 
+// openPick opens the options prompt for the marked commits, or the one
+// under the cursor when none are marked. Cherry-picks apply oldest first,
+// reverts newest first, so each step applies cleanly on the one before.
+func (v *LogView) openPick(revert bool) {
+	var picked []git.Commit
+	for _, c := range v.commits {
+		if v.marked[c.Hash] {
+			picked = append(picked, c)
+		}
+	}
+	if len(picked) == 0 {
+		if v.cursor >= len(v.commits) {
+			return
+		}
+		picked = []git.Commit{v.commits[v.cursor]}
+	}
+	if !revert {
+		slices.Reverse(picked)
+	}
+	v.pickHashes = make([]string, len(picked))
+	v.maxMainline = 0
+	for i, c := range picked {
+		v.pickHashes[i] = c.Hash
+		if len(c.Parents) > 1 {
+			v.maxMainline = max(v.maxMainline, len(c.Parents))
+		}
+	}
+	v.pickOpts = git.PickOptions{}
+	if v.maxMainline > 0 {
+		v.pickOpts.Mainline = 1
+	}
+	v.pickRevert = revert
+	v.picking = true
+}
+
+func (v *LogView) updatePick(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch v.pickKeys.Action(msg) {
+	case "cancel":
+		v.picking = false
+	case "record_origin":
+		if !v.pickRevert {
+			v.pickOpts.RecordOrigin = !v.pickOpts.RecordOrigin
+		}
+	case "no_commit":
+		v.pickOpts.NoCommit = !v.pickOpts.NoCommit
+	case "mainline":
+		if v.maxMainline > 0 {
+			v.pickOpts.Mainline = v.pickOpts.Mainline%v.maxMainline + 1
+		}
+	case "submit":
+		v.picking = false
+		clear(v.marked)
+		v.rebuildContent()
+		return v, v.pick(v.pickRevert, v.pickHashes, v.pickOpts)
+	}
+	return v, nil
+}
+
+// pick cherry-picks or reverts hashes. When git stops on a conflict the
+// Conflicts tab opens; the status bar then shows the sequence in progress.
+func (v *LogView) pick(revert bool, hashes []string, opts git.PickOptions) tea.Cmd {
+	verb, run := "Cherry-picked", v.gitSvc.CherryPick
+	if revert {
+		verb, run = "Reverted", v.gitSvc.Revert
+	}
+	return func() tea.Msg {
+		if err := run(hashes, opts); err != nil {
+			if files, _ := v.gitSvc.ConflictFiles(); len(files) > 0 {
+				return logConflictMsg{info: fmt.Sprintf("Stopped on conflicts in %d file(s)", len(files))}
+			}
+			return common.ErrMsg{Err: err}
+		}
+		info := fmt.Sprintf("%s %d commit(s)", verb, len(hashes))
+		if opts.NoCommit {
+			info += " (changes staged, not committed)"
+		}
+		return logOpDoneMsg{info: info}
+	}
+}
+
+// sequencerStep continues, skips or aborts the cherry-pick or revert in
+// progress.
+func (v *LogView) sequencerStep(info string, step func() error) tea.Cmd {
+	return func() tea.Msg {
+		if err := step(); err != nil {
+			if files, _ := v.gitSvc.ConflictFiles(); len(files) > 0 {
+				return logConflictMsg{info: fmt.Sprintf("Stopped on conflicts in %d file(s)", len(files))}
+			}
+			return common.ErrMsg{Err: err}
+		}
+		return logOpDoneMsg{info: info}
+	}
+}
+
+// fixup commits the staged changes as a fixup! for c.
+func (v *LogView) fixup(c git.Commit) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.CommitFixup(c.Hash, false, ""); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return logOpDoneMsg{info: "Created fixup! commit for " + c.ShortHash}
+	}
+}
+
+// autosquash folds the fixup!/squash! commits made since c, c included,
+// into the commits they name.
+func (v *LogView) autosquash(c git.Commit) tea.Cmd {
+	onto := "--root"
+	if len(c.Parents) > 0 {
+		onto = c.Parents[0]
+	}
+	return func() tea.Msg {
+		if err := v.gitSvc.RebaseAutosquash(onto); err != nil {
+			if files, _ := v.gitSvc.ConflictFiles(); len(files) > 0 {
+				return logConflictMsg{info: fmt.Sprintf("Autosquash stopped on conflicts in %d file(s)", len(files))}
+			}
+			return common.ErrMsg{Err: err}
+		}
+		return logOpDoneMsg{info: "Autosquashed commits from " + c.ShortHash + " up"}
+	}
+}
+
 func (v *LogView) loadDetail(hash string) tea.Cmd {
 	return func() tea.Msg {
-		commit, diff, err := v.gitSvc.Show(hash)
+		commits, err := v.gitSvc.Log(1, hash, "-1")
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		if len(commits) == 0 {
+			return common.ErrMsg{Err: fmt.Errorf("commit %s not found", hash)}
+		}
+		files, err := v.gitSvc.CommitFiles(hash)
 		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
-		return commitDetailMsg{commit: commit, diff: diff}
+		return commitDetailMsg{commit: &commits[0], files: files}
 	}
 }
 
+// compare opens the Diff view comparing the two marked commits, or the
+// marked one and the selected one, older to newer.
+func (v *LogView) compare() tea.Cmd {
+	var picked []int
+	for i, c := range v.commits {
+		if v.marked[c.Hash] || (len(v.marked) == 1 && i == v.cursor) {
+			picked = append(picked, i)
+		}
+	}
+	if len(picked) != 2 {
+		return common.CmdErr(fmt.Errorf("mark two commits to compare (or mark one and select the other)"))
+	}
+	// The list is newest first.
+	from, to := v.commits[picked[1]].ShortHash, v.commits[picked[0]].ShortHash
+	return tea.Sequence(
+		func() tea.Msg { return common.SwitchTabMsg{Tab: common.TabDiff} },
+		func() tea.Msg { return diffCompareMsg{from: from, to: to} },
+	)
+}
+
+// openCopy offers c's hash, full hash, subject and message and, while the
+// detail pane shows c, the patch of the file selected there.
+func (v *LogView) openCopy(c git.Commit) {
+	message := c.Subject
+	if c.Body != "" {
+		message += "\n\n" + c.Body
+	}
+	choices := []copyChoice{
+		{"hash", "hash", c.ShortHash},
+		{"full_hash", "full hash", c.Hash},
+		{"subject", "subject", c.Subject},
+		{"message", "message", message},
+	}
+	if v.showDetail && v.detailCommit != nil && v.detailCommit.Hash == c.Hash {
+		if f, diff, ok := v.detail.Current(); ok {
+			choices = append(choices, copyChoice{"patch", "patch of " + path.Base(f.Path), diff})
+		}
+	}
+	v.copy.Open("Copy from "+c.ShortHash, choices)
+}
+
 func (v *LogView) View() string {
+	if v.copy.open {
+		return v.copy.View(v.styles)
+	}
+	if v.form.open {
+		return v.form.View(v.styles)
+	}
+	if v.reset.open {
+		return v.reset.View(v.styles)
+	}
+	if v.reword.open {
+		return v.reword.View(v.styles)
+	}
+	if v.picking {
+		return v.viewPick()
+	}
 	if v.showDetail {
-		left := v.vp.View()
-		right := v.styles.Panel.Width(v.width/2 - 2).Height(v.height - 2).
-			Render(v.detailVP.View())
+		left := lipgloss.NewStyle().MaxWidth(v.width - v.width/2).Render(v.vp.View())
+		hints := v.detail.keys.Hints("next_file", "next file", "next_hunk", "next hunk", "switch_pane", "files/diff", "back", "close")
+		// Long header lines are cut rather than wrapped, which would push
+		// the browser down.
+		lines, _ := v.detailLayout()
+		for i, l := range lines {
+			lines[i] = lipgloss.NewStyle().MaxWidth(v.width/2 - 4).Render(l)
+		}
+		detail := strings.Join(append(lines, v.detail.View(), v.styles.Muted.Render(ui.Truncate(hints, v.width/2-4))), "\n")
+		right := v.styles.PanelFocused.Width(v.width/2 - 2).Height(v.height - 2).Render(detail)
 		return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
 	}
 	return v.vp.View()
 }
 
+func (v *LogView) viewPick() string {
+	t := v.styles.Theme
+	verb := "Cherry-pick"
+	if v.pickRevert {
+		verb = "Revert"
+	}
+	title := fmt.Sprintf("  %s %d commit(s) onto HEAD", verb, len(v.pickHashes))
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	lines := []string{lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render(title), ""}
+	k := v.pickKeys.First
+	if !v.pickRevert {
+		lines = append(lines, "  "+check(v.pickOpts.RecordOrigin)+" "+k("record_origin")+"  record the original commit (-x)")
+	}
+	lines = append(lines, "  "+check(v.pickOpts.NoCommit)+" "+k("no_commit")+"  stage the changes without committing (--no-commit)")
+	if v.maxMainline > 0 {
+		lines = append(lines, fmt.Sprintf("  [%d] %s  mainline parent for merge commits (-m)", v.pickOpts.Mainline, k("mainline")))
+	}
+	lines = append(lines, "", v.styles.Muted.Render("  "+v.pickKeys.Hints("submit", "run", "cancel", "cancel")))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
 func (v *LogView) rebuildContent() {
 	t := v.styles.Theme
 	var b strings.Builder
 	commitIdx := 0
 
+	if v.sequencer != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(t.Warning).Bold(true).
+			Render("  "+strings.ToUpper(v.sequencer)+" IN PROGRESS") + "  " +
+			v.styles.Muted.Render(v.keys.Hints("continue", "continue", "skip", "skip", "abort", "abort")) + "\n")
+	}
+
 	if len(v.entries) > 0 {
 		for _, e := range v.entries {
 			graphStyle := lipgloss.NewStyle().Foreground(t.GraphColors[commitIdx%len(t.GraphColors)])
@@ -244,7 +635,13 @@ func (v *LogView) rebuildContent() {
 		b.WriteString(lipgloss.NewStyle().Foreground(t.TextMuted).Render("  No commits found"))
 	}
 
-	b.WriteString("\n" + v.styles.Muted.Render("  enter/d detail  y copy hash  j/k navigate"))
+	hints := v.keys.Hints("detail", "detail", "copy_hash", "copy hash", "tag", "tag",
+		"mark", "mark", "cherry_pick", "cherry-pick", "revert", "revert", "reset", "reset",
+		"fixup", "fixup", "reword", "reword")
+	if len(v.marked) > 0 {
+		hints = fmt.Sprintf("%d marked  ", len(v.marked)) + hints
+	}
+	b.WriteString("\n" + v.styles.Muted.Render("  "+hints))
 	v.vp.SetContent(b.String())
 }
 
@@ -257,7 +654,11 @@ func (v *LogView) renderCommitLine(c *git.Commit, selected bool) string {
 
 	refs := v.renderRefs(c.Refs)
 
-	line := fmt.Sprintf(" %s %s%s %s %s", hash, subj, refs, author, date)
+	mark := " "
+	if v.marked[c.Hash] {
+		mark = lipgloss.NewStyle().Foreground(t.Accent).Render("●")
+	}
+	line := fmt.Sprintf("%s%s %s%s %s %s", mark, hash, subj, refs, author, date)
 
 	if selected {
 		return lipgloss.NewStyle().Background(t.SurfaceHover).Bold(true).Render("▸" + line)
@@ -288,42 +689,72 @@ func (v *LogView) renderRefs(refs []git.Ref) string {
 	return " " + strings.Join(parts, " ")
 }
 
-func (v *LogView) renderCommitDetail(c *git.Commit, diff string) string {
-	t := v.styles.Theme
-	var b strings.Builder
-
-	b.WriteString(lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("Commit Detail") + "\n\n")
-	b.WriteString(v.styles.Muted.Render("Hash:    ") + v.styles.CommitHash.Render(c.Hash) + "\n")
-	b.WriteString(v.styles.Muted.Render("Author:  ") + v.styles.Author.Render(c.Author+" <"+c.AuthorEmail+">") + "\n")
-	b.WriteString(v.styles.Muted.Render("Date:    ") + v.styles.Date.Render(c.Date.Format("2006-01-02 15:04:05")) + "\n")
+// maxDetailBodyLines caps the commit message body in the detail header,
+// leaving the rest of the panel to the files.
+const maxDetailBodyLines = 6
 
+// commitHeader renders the detail panel's commit metadata and message.
+func (v *LogView) commitHeader(c *git.Commit) []string {
+	t := v.styles.Theme
+	lines := []string{
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("Commit Detail"),
+		"",
+		v.styles.Muted.Render("Hash:    ") + v.styles.CommitHash.Render(c.Hash),
+		v.styles.Muted.Render("Author:  ") + v.styles.Author.Render(c.Author+" <"+c.AuthorEmail+">"),
+		v.styles.Muted.Render("Date:    ") + v.styles.Date.Render(c.Date.Format("2006-01-02 15:04:05")),
+	}
 	if len(c.Parents) > 0 {
-		b.WriteString(v.styles.Muted.Render("Parents: ") + v.styles.CommitHash.Render(strings.Join(c.Parents, " ")) + "\n")
+		lines = append(lines, v.styles.Muted.Render("Parents: ")+v.styles.CommitHash.Render(strings.Join(c.Parents, " ")))
 	}
 	if len(c.Refs) > 0 {
-		b.WriteString(v.styles.Muted.Render("Refs:    ") + v.renderRefs(c.Refs) + "\n")
+		lines = append(lines, v.styles.Muted.Render("Refs:    ")+v.renderRefs(c.Refs))
 	}
 
-	b.WriteString("\n" + v.styles.Bold.Render(c.Subject) + "\n")
+	lines = append(lines, "", v.styles.Bold.Render(c.Subject))
 	if c.Body != "" {
-		b.WriteString("\n" + v.styles.Body.Render(c.Body) + "\n")
-	}
-
-	if diff != "" {
-		b.WriteString("\n" + renderDiffColored(v.styles, diff))
+		body := strings.Split(c.Body, "\n")
+		if len(body) > maxDetailBodyLines {
+			body = append(body[:maxDetailBodyLines], "…")
+		}
+		lines = append(lines, "")
+		for _, l := range body {
+			lines = append(lines, v.styles.Body.Render(l))
+		}
 	}
-
-	return b.String()
+	return append(lines, "")
 }
 
 func (v *LogView) ShortHelp() []components.HelpEntry {
 	return []components.HelpEntry{
-		{Key: "↑/↓", Desc: "Navigate commits"},
-		{Key: "enter / d", Desc: "Show commit detail"},
-		{Key: "y", Desc: "Copy commit hash"},
-		{Key: "home/end", Desc: "Top / bottom"},
-		{Key: "esc", Desc: "Close detail"},
+		{Key: v.keys.First("up") + "/" + v.keys.First("down"), Desc: "Navigate commits"},
+		{Key: v.keys.Help("detail"), Desc: "Show commit detail"},
+		{Key: v.keys.Help("copy_hash"), Desc: "Copy commit hash"},
+		{Key: v.keys.Help("copy"), Desc: "Copy full hash, subject, message or patch"},
+		{Key: v.keys.Help("tag"), Desc: "Tag commit"},
+		{Key: v.keys.Help("mark"), Desc: "Mark commit for cherry-pick / revert"},
+		{Key: v.keys.Help("compare"), Desc: "Compare the two marked commits"},
+		{Key: v.keys.Help("cherry_pick"), Desc: "Cherry-pick marked commits onto HEAD"},
+		{Key: v.keys.Help("revert"), Desc: "Revert marked commits"},
+		{Key: v.pickKeys.Help("record_origin"), Desc: "Pick prompt: toggle -x (record the original commit)"},
+		{Key: v.pickKeys.Help("no_commit"), Desc: "Pick prompt: toggle --no-commit"},
+		{Key: v.pickKeys.Help("mainline"), Desc: "Pick prompt: cycle the mainline parent of merges"},
+		{Key: v.keys.Help("reset"), Desc: "Reset current branch to commit"},
+		{Key: v.keys.Help("fixup"), Desc: "Commit staged changes as fixup! for commit"},
+		{Key: v.keys.Help("squash"), Desc: "Commit staged changes as squash! for commit"},
+		{Key: v.keys.Help("autosquash"), Desc: "Fold fixup!/squash! commits from commit up"},
+		{Key: v.keys.Help("reword"), Desc: "Reword commit message"},
+		{Key: v.keys.Help("continue"), Desc: "Continue cherry-pick / revert"},
+		{Key: v.keys.Help("skip"), Desc: "Skip commit"},
+		{Key: v.keys.Help("abort"), Desc: "Abort cherry-pick / revert"},
+		{Key: v.keys.First("top") + "/" + v.keys.First("bottom"), Desc: "Top / bottom"},
+		{Key: v.detail.keys.Help("next_file") + ", " + v.detail.keys.Help("prev_file"), Desc: "Detail: next / previous file"},
+		{Key: v.detail.keys.Help("next_hunk") + ", " + v.detail.keys.Help("prev_hunk"), Desc: "Detail: next / previous hunk"},
+		{Key: v.detail.keys.Help("open"), Desc: "Detail: fold a directory / focus the file's diff"},
+		{Key: v.detail.keys.Help("switch_pane"), Desc: "Detail: switch between the files and the diff"},
+		{Key: v.keys.Help("back"), Desc: "Close detail"},
 	}
 }
 
-func (v *LogView) InputCapture() bool { return false }
+func (v *LogView) InputCapture() bool {
+	return v.copy.open || v.form.open || v.reset.open || v.reword.open || v.picking
+}
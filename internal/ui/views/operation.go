@@ -0,0 +1,72 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lastOperationID numbers operations so the app can tell their progress
+// apart.
+var lastOperationID atomic.Int64
+
+// runOperation runs a network operation in the background, reporting its
+// progress to the status bar and letting the user cancel it. done turns
+// the operation's error (nil on success) into the view's result message;
+// a cancelled operation just says so.
+func runOperation(label string, op func(ctx context.Context, progress git.ProgressFunc) error, done func(error) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		id := int(lastOperationID.Add(1))
+		ctx, cancel := context.WithCancel(context.Background())
+		// Capacity 1: updates arriving faster than the UI reads them are
+		// dropped. The app keeps exactly one read (next) outstanding, so
+		// the final result always gets through.
+		ch := make(chan tea.Msg, 1)
+		next := func() tea.Msg { return <-ch }
+
+		progress := func(p git.Progress) {
+			select {
+			case ch <- common.ProgressMsg{ID: id, Text: formatProgress(p), Next: next}:
+			default:
+			}
+		}
+		go func() {
+			err := op(ctx, progress)
+			cancelled := ctx.Err() != nil
+			cancel()
+			var result tea.Msg
+			if cancelled && errors.Is(err, context.Canceled) {
+				result = common.InfoMsg{Text: "Cancelled " + label}
+			} else {
+				result = done(err)
+			}
+			// Make room if an unread progress update is still waiting.
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- common.OperationDoneMsg{ID: id, Result: result}
+		}()
+		return common.OperationStartMsg{ID: id, Label: label, Cancel: cancel, Next: next}
+	}
+}
+
+// formatProgress renders p for the status bar, e.g. "Receiving objects
+// 45% (450/1000) 600.00 KiB/s".
+func formatProgress(p git.Progress) string {
+	text := p.Phase
+	if p.Percent >= 0 {
+		text += fmt.Sprintf(" %d%% (%d/%d)", p.Percent, p.Current, p.Total)
+	} else {
+		text += fmt.Sprintf(" %d", p.Current)
+	}
+	if p.Rate != "" {
+		text += " " + p.Rate
+	}
+	return text
+}
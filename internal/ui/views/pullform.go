@@ -0,0 +1,111 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pullModes are the choices offered by pullForm, with the "pull" keymap
+// action that picks each.
+var pullModes = []struct {
+	mode   git.PullMode
+	action string
+	desc   string
+}{
+	{git.PullMerge, "merge", "merge, fast-forwarding when possible"},
+	{git.PullRebase, "rebase", "replay local commits on top of the remote's"},
+	{git.PullFFOnly, "ff_only", "fast-forward only; refuse if the branches diverged"},
+}
+
+// pullForm is the Remotes view's pull prompt: pick how to integrate the
+// remote branch, and whether to stash local changes around the pull.
+type pullForm struct {
+	keys   keys.Set
+	open   bool
+	remote string
+	branch string // branch pulled from remote
+	head   string // branch pulled into
+	choice int    // index into pullModes
+	opts   git.PullOptions
+}
+
+// pullSubmitMsg carries the choices made in the pull form.
+type pullSubmitMsg struct {
+	remote, branch string
+	opts           git.PullOptions
+}
+
+func newPullForm(cfg *config.Config) pullForm {
+	return pullForm{keys: keys.New(cfg.Keymap, "pull")}
+}
+
+// Open shows the form for pulling remote's branch into head, starting on
+// opts.
+func (f *pullForm) Open(remote, branch, head string, opts git.PullOptions) {
+	f.open = true
+	f.remote, f.branch, f.head = remote, branch, head
+	f.opts = opts
+	f.choice = 0
+	for i, m := range pullModes {
+		if m.mode == opts.Mode {
+			f.choice = i
+		}
+	}
+}
+
+// Update handles a key while the form is open. On enter it closes the form
+// and returns a pullSubmitMsg.
+func (f *pullForm) Update(msg tea.KeyMsg) tea.Cmd {
+	switch action := f.keys.Action(msg); action {
+	case "cancel":
+		f.open = false
+	case "prev":
+		f.choice = (f.choice + len(pullModes) - 1) % len(pullModes)
+	case "next":
+		f.choice = (f.choice + 1) % len(pullModes)
+	case "autostash":
+		f.opts.Autostash = !f.opts.Autostash
+	case "submit":
+		f.open = false
+		f.opts.Mode = pullModes[f.choice].mode
+		submit := pullSubmitMsg{remote: f.remote, branch: f.branch, opts: f.opts}
+		return func() tea.Msg { return submit }
+	default:
+		for i, m := range pullModes {
+			if m.action == action {
+				f.choice = i
+			}
+		}
+	}
+	return nil
+}
+
+func (f *pullForm) View(styles ui.Styles) string {
+	t := styles.Theme
+	lines := []string{
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).
+			Render(fmt.Sprintf("  Pull %s/%s into %s", f.remote, f.branch, f.head)),
+		"",
+	}
+	for i, m := range pullModes {
+		line := fmt.Sprintf("%s  %-8s %s", f.keys.First(m.action), m.mode, m.desc)
+		if i == f.choice {
+			lines = append(lines, styles.ListSelected.Render("▸ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	box := "[ ]"
+	if f.opts.Autostash {
+		box = "[x]"
+	}
+	lines = append(lines, "", "  "+box+" autostash local changes "+styles.Muted.Render(f.keys.First("autostash")),
+		"", styles.Muted.Render("  "+f.keys.First("prev")+"/"+f.keys.Hints("next", "choose", "submit", "pull", "cancel", "cancel")))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,234 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pushForm is the Remotes view's push prompt: pick the remote and the
+// branch name there, and the push options.
+type pushForm struct {
+	open     bool
+	keys     keys.Set
+	remotes  []string
+	remote   int // index into remotes
+	branch   string
+	upstream string // branch's upstream, e.g. "origin/main"; "" if none
+	target   textinput.Model
+	opts     git.PushOptions
+}
+
+// pushSubmitMsg carries the options chosen in the push form.
+type pushSubmitMsg struct{ opts git.PushOptions }
+
+func newPushForm(cfg *config.Config) pushForm {
+	ti := textinput.New()
+	ti.Placeholder = "branch on the remote"
+	ti.CharLimit = 200
+	ti.Width = 40
+	return pushForm{keys: keys.New(cfg.Keymap, "push"), target: ti}
+}
+
+// Open shows the form for pushing branch, starting on remote. Without an
+// upstream, --set-upstream starts ticked.
+func (f *pushForm) Open(remotes []git.Remote, remote, branch, upstream string) tea.Cmd {
+	f.open = true
+	f.remotes = f.remotes[:0]
+	f.remote = 0
+	for i, r := range remotes {
+		f.remotes = append(f.remotes, r.Name)
+		if r.Name == remote {
+			f.remote = i
+		}
+	}
+	f.branch, f.upstream = branch, upstream
+	f.opts = git.PushOptions{SetUpstream: upstream == ""}
+	f.resetTarget()
+	return f.target.Focus()
+}
+
+// resetTarget suggests the upstream's branch name when pushing to the
+// upstream's remote, else the local name.
+func (f *pushForm) resetTarget() {
+	target := f.branch
+	if name, ok := strings.CutPrefix(f.upstream, f.remotes[f.remote]+"/"); ok {
+		target = name
+	}
+	f.target.SetValue(target)
+	f.target.CursorEnd()
+}
+
+func (f *pushForm) close() {
+	f.open = false
+	f.target.Blur()
+}
+
+// Update handles a key while the form is open. On submit it closes the
+// form and returns a pushSubmitMsg.
+func (f *pushForm) Update(msg tea.KeyMsg) tea.Cmd {
+	switch f.keys.Action(msg) {
+	case "cancel":
+		f.close()
+		return nil
+	case "next_remote":
+		f.remote = (f.remote + 1) % len(f.remotes)
+		f.resetTarget()
+		return nil
+	case "prev_remote":
+		f.remote = (f.remote + len(f.remotes) - 1) % len(f.remotes)
+		f.resetTarget()
+		return nil
+	case "set_upstream":
+		f.opts.SetUpstream = !f.opts.SetUpstream
+		return nil
+	case "force":
+		f.opts.ForceWithLease = !f.opts.ForceWithLease
+		return nil
+	case "tags":
+		f.opts.Tags = !f.opts.Tags
+		return nil
+	case "no_verify":
+		f.opts.NoVerify = !f.opts.NoVerify
+		return nil
+	case "submit":
+		target := strings.TrimSpace(f.target.Value())
+		if target == "" {
+			return common.CmdErr(fmt.Errorf("the branch name on the remote cannot be empty"))
+		}
+		opts := f.opts
+		opts.Remote, opts.Branch, opts.Target = f.remotes[f.remote], f.branch, target
+		f.close()
+		return func() tea.Msg { return pushSubmitMsg{opts: opts} }
+	}
+	var cmd tea.Cmd
+	f.target, cmd = f.target.Update(msg)
+	return cmd
+}
+
+func (f *pushForm) View(styles ui.Styles) string {
+	t := styles.Theme
+	title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  Push " + f.branch)
+
+	var remotes []string
+	for i, r := range f.remotes {
+		if i == f.remote {
+			remotes = append(remotes, styles.ListSelected.Render(" "+r+" "))
+		} else {
+			remotes = append(remotes, styles.Muted.Render(" "+r+" "))
+		}
+	}
+	upstream := "no upstream"
+	if f.upstream != "" {
+		upstream = "upstream " + f.upstream
+	}
+
+	check := func(on bool, label, action string) string {
+		box := "[ ] "
+		if on {
+			box = "[x] "
+		}
+		return box + label + " " + styles.Muted.Render(f.keys.First(action))
+	}
+	options := []string{
+		"  " + check(f.opts.SetUpstream, "set upstream", "set_upstream"),
+		"  " + check(f.opts.ForceWithLease, "force with lease", "force"),
+		"  " + check(f.opts.Tags, "push tags", "tags"),
+		"  " + check(f.opts.NoVerify, "skip pre-push hook", "no_verify"),
+	}
+
+	hint := styles.Muted.Render("  " + f.keys.Hints("next_remote", "remote", "submit", "push", "cancel", "cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, title, styles.Muted.Render("  "+upstream), "",
+		styles.Muted.Render("  Remote"), "  "+strings.Join(remotes, " "), "",
+		styles.Muted.Render("  Branch on the remote"), "  "+f.target.View(), "",
+		lipgloss.JoinVertical(lipgloss.Left, options...), "", hint)
+}
+
+// askPush asks before running push(opts), listing the commits the remote
+// doesn't have yet and, for a force push, the ones it will lose. The lease
+// is taken on the remote-tracking branch as shown in the dialog.
+func askPush(gitSvc git.Service, cfg *config.Config, opts git.PushOptions, push func(git.PushOptions) tea.Cmd) tea.Cmd {
+	if pattern := cfg.ProtectedPattern(opts.Target); opts.ForceWithLease && pattern != "" {
+		return common.CmdErr(fmt.Errorf("refusing to force push to %s: it matches protected_branches pattern %q", opts.Target, pattern))
+	}
+	return confirm(cfg, push(opts), func() (common.ConfirmMsg, error) {
+		tracking := "refs/remotes/" + opts.Remote + "/" + opts.Target
+		remoteHash := gitSvc.RefHash(tracking)
+		local := "refs/heads/" + opts.Branch
+
+		not := []string{"--not", "--remotes=" + opts.Remote}
+		if remoteHash != "" {
+			not = []string{"--not", tracking}
+		}
+		outgoing, err := gitSvc.Log(maxOutgoingShown, append([]string{local}, not...)...)
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		dest := opts.Remote + "/" + opts.Target
+		detail := "Nothing new to push; " + dest + " already has every commit on " + opts.Branch + "."
+		if len(outgoing) > 0 {
+			detail = countCommits(outgoing) + " commit(s) will be published:\n\n" + confirmPreview(commitLines(outgoing))
+		}
+
+		title := fmt.Sprintf("Push %s to %s?", opts.Branch, dest)
+		if opts.ForceWithLease {
+			title = fmt.Sprintf("Force push %s to %s?", opts.Branch, dest)
+			opts.Expect = remoteHash
+			if remoteHash != "" {
+				lost, err := gitSvc.Log(maxOutgoingShown, tracking, "--not", local)
+				if err != nil {
+					return common.ConfirmMsg{}, err
+				}
+				if len(lost) > 0 {
+					detail += "\n\n" + countCommits(lost) + " commit(s) will be removed from " + dest + ":\n\n" +
+						confirmPreview(commitLines(lost))
+				}
+				detail += "\n\nRefused if " + dest + " has moved from " + remoteHash[:7] + " since the last fetch."
+			}
+		}
+		if flags := pushFlags(opts); flags != "" {
+			detail += "\n\nWith " + flags
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmPush,
+			Title:     title,
+			Detail:    detail,
+			OnConfirm: push(opts),
+		}, nil
+	})
+}
+
+// countCommits formats the size of a commit list capped at
+// maxOutgoingShown.
+func countCommits(commits []git.Commit) string {
+	if len(commits) == maxOutgoingShown {
+		return fmt.Sprint(len(commits)) + "+"
+	}
+	return fmt.Sprint(len(commits))
+}
+
+// pushFlags lists the options besides force, as git flags.
+func pushFlags(opts git.PushOptions) string {
+	var flags []string
+	if opts.SetUpstream {
+		flags = append(flags, "--set-upstream")
+	}
+	if opts.Tags {
+		flags = append(flags, "--tags")
+	}
+	if opts.NoVerify {
+		flags = append(flags, "--no-verify")
+	}
+	return strings.Join(flags, " ")
+}
+
+// maxOutgoingShown caps the commit lookups for the push prompt.
+const maxOutgoingShown = 100
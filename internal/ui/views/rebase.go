@@ -1,52 +1,148 @@
 package views
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// RebaseView handles interactive rebase operations.
+// rebaseTodoLimit caps how many commits the todo editor will load. Longer
+// rebases are refused: the todo replaces git's own list, so any commit
+// missing from it would be dropped.
+const rebaseTodoLimit = 500
+
+// rebaseMode is the RebaseView's current interaction state.
+type rebaseMode int
+
+const (
+	rebaseIdle   rebaseMode = iota // no editor open
+	rebaseOnto                     // typing the base ref
+	rebaseEdit                     // editing the todo list
+	rebasePrompt                   // typing a reword message or exec command
+)
+
+// RebaseView handles interactive rebase operations: choosing a base,
+// editing the todo list, and following a stopped rebase.
 type RebaseView struct {
-	gitSvc    git.Service
-	styles    ui.Styles
-	width     int
-	height    int
-	rebasing  bool
-	inputMode bool
-	input     textinput.Model
+	gitSvc git.Service
+	styles ui.Styles
+	keys   keys.Set // progress / idle screen
+	editKs keys.Set // todo editor
+	width  int
+	height int
+
+	rebasing bool
+	progress *git.RebaseProgress
+
+	mode   rebaseMode
+	input  textinput.Model
+	onto   string
+	todo   []git.RebaseTodoItem
+	bodies []string // original commit bodies, parallel to todo
+	cursor int
+	offset int
+
+	// promptAction is the action being prompted for in rebasePrompt
+	// (RebaseReword or RebaseExec).
+	promptAction git.RebaseAction
 }
 
+type (
+	rebaseTodoMsg struct {
+		onto   string
+		todo   []git.RebaseTodoItem
+		bodies []string
+	}
+	rebaseProgressMsg struct{ progress *git.RebaseProgress }
+)
+
 // NewRebaseView creates a new RebaseView.
-func NewRebaseView(gitSvc git.Service, styles ui.Styles) *RebaseView {
+func NewRebaseView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *RebaseView {
 	ti := textinput.New()
-	ti.Placeholder = "commit hash or branch (e.g. HEAD~3, main)"
-	ti.CharLimit = 100
+	ti.CharLimit = 200
 	ti.Width = 50
-	return &RebaseView{gitSvc: gitSvc, styles: styles, input: ti}
+	return &RebaseView{
+		gitSvc: gitSvc,
+		styles: styles,
+		keys:   keys.New(cfg.Keymap, "rebase"),
+		editKs: keys.New(cfg.Keymap, "rebase_editor"),
+		input:  ti,
+	}
 }
 
 func (v *RebaseView) Init() tea.Cmd {
 	v.rebasing = v.gitSvc.IsRebasing()
-	return nil
+	return v.loadProgress()
 }
 
 func (v *RebaseView) SetSize(w, h int) { v.width = w; v.height = h }
 
+func (v *RebaseView) loadProgress() tea.Cmd {
+	return func() tea.Msg {
+		p, err := v.gitSvc.RebaseProgress()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return rebaseProgressMsg{progress: p}
+	}
+}
+
+func (v *RebaseView) loadTodo(onto string) tea.Cmd {
+	return func() tea.Msg {
+		// --max-count applies before --reverse, so one more than the
+		// limit is asked for to tell a cut list from a complete one.
+		commits, err := v.gitSvc.Log(rebaseTodoLimit+1, "--reverse", "--no-merges", onto+"..HEAD")
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		if len(commits) > rebaseTodoLimit {
+			return common.ErrMsg{Err: fmt.Errorf("more than %d commits between %s and HEAD; rebase onto a later commit", rebaseTodoLimit, onto)}
+		}
+		if len(commits) == 0 {
+			return common.CmdInfo(fmt.Sprintf("No commits between %s and HEAD", onto))()
+		}
+		todo := make([]git.RebaseTodoItem, len(commits))
+		bodies := make([]string, len(commits))
+		for i, c := range commits {
+			todo[i] = git.RebaseTodoItem{Action: git.RebasePick, Hash: c.ShortHash, Subject: c.Subject}
+			bodies[i] = c.Body
+		}
+		return rebaseTodoMsg{onto: onto, todo: todo, bodies: bodies}
+	}
+}
+
 func (v *RebaseView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 	switch msg := msg.(type) {
 	case common.RefreshMsg:
 		v.rebasing = v.gitSvc.IsRebasing()
+		return v, v.loadProgress()
+	case rebaseProgressMsg:
+		v.progress = msg.progress
+		return v, nil
+	case rebaseTodoMsg:
+		v.mode = rebaseEdit
+		v.onto = msg.onto
+		v.todo = msg.todo
+		v.bodies = msg.bodies
+		v.cursor, v.offset = 0, 0
 		return v, nil
 	case tea.KeyMsg:
-		if v.inputMode {
-			return v.updateInput(msg)
+		switch v.mode {
+		case rebaseOnto:
+			return v.updateOnto(msg)
+		case rebaseEdit:
+			return v.updateEditor(msg)
+		case rebasePrompt:
+			return v.updatePrompt(msg)
 		}
 		return v.handleKey(msg)
 	}
@@ -54,17 +150,24 @@ func (v *RebaseView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 }
 
 func (v *RebaseView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "i": // Start interactive rebase
-		v.inputMode = true
+	switch v.keys.Action(msg) {
+	case "start":
+		if v.rebasing {
+			return v, nil
+		}
+		v.mode = rebaseOnto
 		v.input.Reset()
-		v.input.Focus()
+		v.input.Placeholder = "commit hash or branch (e.g. HEAD~3, main)"
 		return v, v.input.Focus()
-	case "c": // Continue
+	case "continue":
 		if v.rebasing {
 			return v, v.rebaseContinue()
 		}
-	case "a": // Abort
+	case "skip":
+		if v.rebasing {
+			return v, v.rebaseSkip()
+		}
+	case "abort":
 		if v.rebasing {
 			return v, v.rebaseAbort()
 		}
@@ -72,29 +175,192 @@ func (v *RebaseView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
 	return v, nil
 }
 
-func (v *RebaseView) updateInput(msg tea.KeyMsg) (common.View, tea.Cmd) {
+func (v *RebaseView) updateOnto(msg tea.KeyMsg) (common.View, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
-		v.inputMode = false
+		v.mode = rebaseIdle
 		v.input.Blur()
 		return v, nil
 	case "enter":
 		onto := strings.TrimSpace(v.input.Value())
-		v.inputMode = false
+		v.mode = rebaseIdle
 		v.input.Blur()
 		if onto == "" {
 			return v, nil
 		}
-		return v, v.rebaseStart(onto)
+		return v, v.loadTodo(onto)
 	}
 	var cmd tea.Cmd
 	v.input, cmd = v.input.Update(msg)
 	return v, cmd
 }
 
-func (v *RebaseView) rebaseStart(onto string) tea.Cmd {
+// rebaseActionKeys maps editor keymap actions to todo actions.
+var rebaseActionKeys = map[string]git.RebaseAction{
+	"pick":   git.RebasePick,
+	"edit":   git.RebaseEdit,
+	"squash": git.RebaseSquash,
+	"fixup":  git.RebaseFixup,
+	"drop":   git.RebaseDrop,
+}
+
+func (v *RebaseView) updateEditor(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	name := v.editKs.Action(msg)
+	if action, ok := rebaseActionKeys[name]; ok {
+		if item := v.current(); item != nil && item.Action != git.RebaseExec {
+			item.Action = action
+			item.Message = ""
+			v.moveCursor(1)
+		}
+		return v, nil
+	}
+
+	switch name {
+	case "down":
+		v.moveCursor(1)
+	case "up":
+		v.moveCursor(-1)
+	case "move_down": // later in history
+		if v.cursor < len(v.todo)-1 {
+			v.swap(v.cursor, v.cursor+1)
+			v.moveCursor(1)
+		}
+	case "move_up": // earlier in history
+		if v.cursor > 0 {
+			v.swap(v.cursor, v.cursor-1)
+			v.moveCursor(-1)
+		}
+	case "reword": // prompt for the new subject
+		item := v.current()
+		if item == nil || item.Action == git.RebaseExec {
+			return v, nil
+		}
+		v.promptAction = git.RebaseReword
+		v.input.Reset()
+		v.input.Placeholder = "new commit subject"
+		v.input.SetValue(strings.SplitN(item.Message, "\n", 2)[0])
+		if item.Message == "" {
+			v.input.SetValue(item.Subject)
+		}
+		v.input.CursorEnd()
+		v.mode = rebasePrompt
+		return v, v.input.Focus()
+	case "exec": // insert an exec line after the cursor
+		v.promptAction = git.RebaseExec
+		v.input.Reset()
+		v.input.Placeholder = "shell command (e.g. make test)"
+		if item := v.current(); item != nil && item.Action == git.RebaseExec {
+			v.input.SetValue(item.Command)
+			v.input.CursorEnd()
+		}
+		v.mode = rebasePrompt
+		return v, v.input.Focus()
+	case "remove_exec":
+		if item := v.current(); item != nil && item.Action == git.RebaseExec {
+			v.todo = append(v.todo[:v.cursor], v.todo[v.cursor+1:]...)
+			v.bodies = append(v.bodies[:v.cursor], v.bodies[v.cursor+1:]...)
+			v.moveCursor(0)
+		}
+	case "start":
+		if err := git.ValidateRebaseTodo(v.todo); err != nil {
+			return v, common.CmdErr(err)
+		}
+		todo := append([]git.RebaseTodoItem(nil), v.todo...)
+		v.closeEditor()
+		return v, v.rebaseStart(v.onto, todo)
+	case "cancel", "back":
+		v.closeEditor()
+	}
+	return v, nil
+}
+
+func (v *RebaseView) updatePrompt(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v.mode = rebaseEdit
+		v.input.Blur()
+		return v, nil
+	case "enter":
+		value := strings.TrimSpace(v.input.Value())
+		v.mode = rebaseEdit
+		v.input.Blur()
+		if value == "" {
+			return v, nil
+		}
+		v.applyPrompt(value)
+		return v, nil
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return v, cmd
+}
+
+// applyPrompt commits the prompt's value to the todo list.
+func (v *RebaseView) applyPrompt(value string) {
+	item := v.current()
+	switch v.promptAction {
+	case git.RebaseReword:
+		if item == nil {
+			return
+		}
+		item.Action = git.RebaseReword
+		item.Message = value
+		// Keep the original body so rewording only touches the subject.
+		if body := strings.TrimSpace(v.bodies[v.cursor]); body != "" {
+			item.Message += "\n\n" + body
+		}
+		v.moveCursor(1)
+	case git.RebaseExec:
+		if item != nil && item.Action == git.RebaseExec {
+			item.Command = value
+			return
+		}
+		at := min(v.cursor+1, len(v.todo))
+		v.todo = append(v.todo[:at], append([]git.RebaseTodoItem{{Action: git.RebaseExec, Command: value}}, v.todo[at:]...)...)
+		v.bodies = append(v.bodies[:at], append([]string{""}, v.bodies[at:]...)...)
+		v.cursor = at
+		v.moveCursor(0)
+	}
+}
+
+func (v *RebaseView) current() *git.RebaseTodoItem {
+	if v.cursor < 0 || v.cursor >= len(v.todo) {
+		return nil
+	}
+	return &v.todo[v.cursor]
+}
+
+func (v *RebaseView) swap(i, j int) {
+	v.todo[i], v.todo[j] = v.todo[j], v.todo[i]
+	v.bodies[i], v.bodies[j] = v.bodies[j], v.bodies[i]
+}
+
+// moveCursor moves the cursor by delta and keeps it inside the list and
+// the visible window.
+func (v *RebaseView) moveCursor(delta int) {
+	v.cursor = max(0, min(v.cursor+delta, len(v.todo)-1))
+	visible := v.editorRows()
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+visible {
+		v.offset = v.cursor - visible + 1
+	}
+}
+
+// editorRows is how many todo rows fit below the editor's title and
+// above its key hints.
+func (v *RebaseView) editorRows() int { return max(v.height-6, 1) }
+
+func (v *RebaseView) closeEditor() {
+	v.mode = rebaseIdle
+	v.todo, v.bodies = nil, nil
+	v.cursor, v.offset = 0, 0
+}
+
+func (v *RebaseView) rebaseStart(onto string, todo []git.RebaseTodoItem) tea.Cmd {
 	return func() tea.Msg {
-		if err := v.gitSvc.RebaseInteractive(onto); err != nil {
+		if err := v.gitSvc.RebaseInteractive(onto, todo); err != nil {
 			return common.ErrMsg{Err: err}
 		}
 		return common.CmdRefresh()
@@ -110,6 +376,15 @@ func (v *RebaseView) rebaseContinue() tea.Cmd {
 	}
 }
 
+func (v *RebaseView) rebaseSkip() tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.RebaseSkip(); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.CmdRefresh()
+	}
+}
+
 func (v *RebaseView) rebaseAbort() tea.Cmd {
 	return func() tea.Msg {
 		if err := v.gitSvc.RebaseAbort(); err != nil {
@@ -121,10 +396,13 @@ func (v *RebaseView) rebaseAbort() tea.Cmd {
 
 func (v *RebaseView) View() string {
 	t := v.styles.Theme
-	if v.inputMode {
+	switch v.mode {
+	case rebaseOnto:
 		title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  Interactive Rebase")
-		hint := v.styles.Muted.Render("  enter to start | esc to cancel")
+		hint := v.styles.Muted.Render("  enter to list commits | esc to cancel")
 		return lipgloss.JoinVertical(lipgloss.Left, title, "", "  Rebase onto:", "  "+v.input.View(), "", hint)
+	case rebaseEdit, rebasePrompt:
+		return v.viewEditor()
 	}
 
 	var b strings.Builder
@@ -134,27 +412,165 @@ func (v *RebaseView) View() string {
 	if v.rebasing {
 		b.WriteString(lipgloss.NewStyle().Foreground(t.Warning).Bold(true).
 			Render("  REBASE IN PROGRESS") + "\n\n")
+		if v.progress != nil {
+			b.WriteString(v.viewProgress())
+		}
 		b.WriteString("  " + v.styles.Muted.Render("Resolve conflicts, stage changes, then:") + "\n\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "c", "continue rebase") + "\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "a", "abort rebase") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("continue"), "continue rebase") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("skip"), "skip this commit") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("abort"), "abort rebase") + "\n")
 	} else {
 		b.WriteString("  " + v.styles.Body.Render("No rebase in progress.") + "\n\n")
-		b.WriteString("  " + ui.RenderKeyValue(v.styles, "i", "start interactive rebase") + "\n")
+		b.WriteString("  " + ui.RenderKeyValue(v.styles, v.keys.First("start"), "start interactive rebase") + "\n")
 	}
 
 	return b.String()
 }
 
+func (v *RebaseView) viewEditor() string {
+	t := v.styles.Theme
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(t.Primary).Bold(true).
+		Render(fmt.Sprintf("  Interactive Rebase onto %s (%d)", v.onto, len(v.todo))) + "\n")
+	b.WriteString(v.styles.Muted.Render("  oldest first — applied top to bottom") + "\n\n")
+
+	end := min(v.offset+v.editorRows(), len(v.todo))
+	for i := v.offset; i < end; i++ {
+		line := v.renderTodoItem(v.todo[i], v.width-4)
+		if i == v.cursor {
+			b.WriteString(v.styles.ListSelected.Render("▸ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if v.mode == rebasePrompt {
+		label := "  Reword:"
+		if v.promptAction == git.RebaseExec {
+			label = "  Exec:"
+		}
+		b.WriteString("\n" + label + " " + v.input.View() + "\n")
+		b.WriteString(v.styles.Muted.Render("  enter to confirm | esc to cancel"))
+		return b.String()
+	}
+	k := v.editKs
+	b.WriteString("\n" + v.styles.Muted.Render("  "+k.Hints(
+		"pick", "pick", "reword", "reword", "edit", "edit", "squash", "squash", "fixup", "fixup",
+		"drop", "drop", "exec", "exec")+"  "+k.First("move_down")+"/"+k.First("move_up")+" move  "+
+		k.Hints("start", "start", "back", "cancel")))
+	return b.String()
+}
+
+// renderTodoItem renders one todo line: a coloured action column followed
+// by the hash and subject (or the exec command).
+func (v *RebaseView) renderTodoItem(item git.RebaseTodoItem, width int) string {
+	t := v.styles.Theme
+	color := t.Text
+	switch item.Action {
+	case git.RebaseReword, git.RebaseEdit:
+		color = t.Warning
+	case git.RebaseSquash, git.RebaseFixup:
+		color = t.Info
+	case git.RebaseDrop:
+		color = t.Error
+	case git.RebaseExec:
+		color = t.Accent
+	}
+	action := lipgloss.NewStyle().Foreground(color).Bold(true).Width(7).Render(string(item.Action))
+
+	if item.Action == git.RebaseExec {
+		return action + " " + v.styles.Body.Render(ui.Truncate(item.Command, max(width-8, 10)))
+	}
+	subject := item.Subject
+	if item.Action == git.RebaseReword && item.Message != "" {
+		subject = strings.SplitN(item.Message, "\n", 2)[0]
+	}
+	hash := lipgloss.NewStyle().Foreground(t.CommitHash).Render(item.Hash)
+	subjectStyle := v.styles.Body
+	if item.Action == git.RebaseDrop {
+		subjectStyle = v.styles.Muted.Strikethrough(true)
+	}
+	return action + " " + hash + " " + subjectStyle.Render(ui.Truncate(subject, max(width-len(item.Hash)-9, 10)))
+}
+
+// viewProgress lists the steps already applied and those still pending.
+func (v *RebaseView) viewProgress() string {
+	t := v.styles.Theme
+	p := v.progress
+	var b strings.Builder
+
+	head := strings.TrimPrefix(p.HeadName, "refs/heads/")
+	summary := fmt.Sprintf("Step %d of %d", p.Step, p.Total)
+	if head != "" {
+		summary += " — rebasing " + head
+	}
+	if p.Onto != "" {
+		summary += " onto " + ui.Truncate(p.Onto, 8)
+	}
+	b.WriteString("  " + v.styles.Body.Render(summary) + "\n")
+	if p.Stopped != "" {
+		b.WriteString("  " + v.styles.Muted.Render("stopped at ") +
+			lipgloss.NewStyle().Foreground(t.CommitHash).Render(ui.Truncate(p.Stopped, 8)) + "\n")
+	}
+	b.WriteString("\n")
+
+	// Budget the rows between done and todo so the key hints stay visible.
+	rows := max(v.height-14, 4)
+	done := p.Done
+	if len(done) > rows/2 {
+		done = done[len(done)-rows/2:]
+	}
+	todo := p.Todo
+	if len(todo) > rows-len(done) {
+		todo = todo[:rows-len(done)]
+	}
+
+	doneMark := lipgloss.NewStyle().Foreground(t.Success).Render("✓")
+	for i, item := range done {
+		mark := doneMark
+		if i == len(done)-1 {
+			mark = lipgloss.NewStyle().Foreground(t.Warning).Render("●")
+		}
+		b.WriteString("  " + mark + " " + v.renderTodoItem(item, v.width-8) + "\n")
+	}
+	for _, item := range todo {
+		b.WriteString("  " + v.styles.Muted.Render("·") + " " + v.renderTodoItem(item, v.width-8) + "\n")
+	}
+	if hidden := len(p.Todo) - len(todo); hidden > 0 {
+		b.WriteString("  " + v.styles.Muted.Render(fmt.Sprintf("  … %d more", hidden)) + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
 func (v *RebaseView) ShortHelp() []components.HelpEntry {
-	if v.rebasing {
+	switch {
+	case v.mode == rebaseEdit:
+		k := v.editKs
+		return []components.HelpEntry{
+			{Key: k.First("down") + "/" + k.First("up"), Desc: "Move cursor"},
+			{Key: k.First("move_down") + "/" + k.First("move_up"), Desc: "Move commit down/up"},
+			{Key: k.Help("pick"), Desc: "Pick"},
+			{Key: k.Help("reword"), Desc: "Reword"},
+			{Key: k.Help("edit"), Desc: "Edit"},
+			{Key: k.Help("squash"), Desc: "Squash"},
+			{Key: k.Help("fixup"), Desc: "Fixup"},
+			{Key: k.Help("drop"), Desc: "Drop"},
+			{Key: k.Help("exec"), Desc: "Insert exec line"},
+			{Key: k.Help("remove_exec"), Desc: "Remove exec line"},
+			{Key: k.Help("start"), Desc: "Start rebase"},
+			{Key: k.First("back") + " / " + k.Help("cancel"), Desc: "Cancel"},
+		}
+	case v.rebasing:
 		return []components.HelpEntry{
-			{Key: "c", Desc: "Continue rebase"},
-			{Key: "a", Desc: "Abort rebase"},
+			{Key: v.keys.Help("continue"), Desc: "Continue rebase"},
+			{Key: v.keys.Help("skip"), Desc: "Skip commit"},
+			{Key: v.keys.Help("abort"), Desc: "Abort rebase"},
 		}
 	}
 	return []components.HelpEntry{
-		{Key: "i", Desc: "Start interactive rebase"},
+		{Key: v.keys.Help("start"), Desc: "Start interactive rebase"},
 	}
 }
 
-func (v *RebaseView) InputCapture() bool { return v.inputMode }
+func (v *RebaseView) InputCapture() bool { return v.mode != rebaseIdle }
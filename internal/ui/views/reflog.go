@@ -0,0 +1,422 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ReflogView lists where HEAD and each local branch have pointed, so
+// commits lost to a bad rebase or reset can be checked out, branched from,
+// reset to or diffed against.
+type ReflogView struct {
+	gitSvc  git.Service
+	styles  ui.Styles
+	cfg     *config.Config
+	keys    keys.Set
+	limit   int // entries to load (config: max_log_entries)
+	width   int
+	height  int
+	refs    []string // "HEAD", then local branches
+	ref     string   // whose reflog is shown
+	entries []git.ReflogEntry
+	cursor  int
+	offset  int
+
+	// Branch name prompt.
+	naming bool
+	input  textinput.Model
+
+	// Detail
+	showDetail bool
+	detailVP   viewport.Model
+}
+
+type (
+	reflogResultMsg struct {
+		ref     string
+		refs    []string
+		entries []git.ReflogEntry
+	}
+	reflogDiffMsg   struct{ title, diff string }
+	reflogOpDoneMsg struct{ info string }
+)
+
+// NewReflogView creates a new ReflogView.
+func NewReflogView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *ReflogView {
+	ti := textinput.New()
+	ti.CharLimit = 100
+	ti.Width = 40
+	return &ReflogView{
+		gitSvc: gitSvc,
+		styles: styles,
+		cfg:    cfg,
+		keys:   keys.New(cfg.Keymap, "reflog"),
+		limit:  cfg.MaxLogEntries,
+		ref:    "HEAD",
+		input:  ti,
+	}
+}
+
+func (v *ReflogView) Init() tea.Cmd { return v.refresh() }
+
+func (v *ReflogView) SetSize(w, h int) {
+	v.width = w
+	v.height = h
+	v.detailVP.Width = w / 2
+	v.detailVP.Height = h - 2
+	v.moveCursor(0)
+}
+
+func (v *ReflogView) refresh() tea.Cmd {
+	ref := v.ref
+	return func() tea.Msg {
+		branches, err := v.gitSvc.Branches()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		refs := []string{"HEAD"}
+		for _, b := range branches {
+			if !b.IsRemote {
+				refs = append(refs, b.Name)
+			}
+		}
+		// The branch may have been deleted or renamed since.
+		if !slices.Contains(refs, ref) {
+			ref = "HEAD"
+		}
+		entries, err := v.gitSvc.Reflog(ref, v.limit)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return reflogResultMsg{ref: ref, refs: refs, entries: entries}
+	}
+}
+
+func (v *ReflogView) Update(msg tea.Msg) (common.View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case reflogResultMsg:
+		if msg.ref != v.ref {
+			v.cursor, v.offset = 0, 0
+		}
+		v.ref, v.refs, v.entries = msg.ref, msg.refs, msg.entries
+		v.moveCursor(0)
+		return v, nil
+
+	case reflogDiffMsg:
+		v.showDetail = true
+		v.detailVP = viewport.New(v.width/2, v.height-2)
+		content := v.styles.Bold.Render(msg.title) + "\n\n"
+		if msg.diff == "" {
+			content += v.styles.Muted.Render("No differences.")
+		} else {
+			content += renderDiffColored(v.styles, msg.diff)
+		}
+		v.detailVP.SetContent(content)
+		return v, nil
+
+	case reflogOpDoneMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+
+	case common.RefreshMsg:
+		return v, v.refresh()
+
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			if v.showDetail {
+				v.detailVP.ScrollUp(3)
+			} else {
+				v.moveCursor(-1)
+			}
+		case tea.MouseButtonWheelDown:
+			if v.showDetail {
+				v.detailVP.ScrollDown(3)
+			} else {
+				v.moveCursor(1)
+			}
+		case tea.MouseButtonLeft:
+			if msg.Action == tea.MouseActionPress && !v.naming {
+				// Content starts at Y=2, header is 2 lines.
+				idx := v.offset + msg.Y - 2 - 2
+				if idx >= v.offset && idx < len(v.entries) {
+					v.cursor = idx
+				}
+			}
+		}
+		return v, nil
+
+	case tea.KeyMsg:
+		if v.naming {
+			return v.updateInput(msg)
+		}
+		return v.updateNormal(msg)
+	}
+	return v, nil
+}
+
+func (v *ReflogView) updateNormal(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch v.keys.Action(msg) {
+	case "down":
+		v.moveCursor(1)
+	case "up":
+		v.moveCursor(-1)
+	case "page_down":
+		v.moveCursor(v.listRows())
+	case "page_up":
+		v.moveCursor(-v.listRows())
+	case "top":
+		v.moveCursor(-len(v.entries))
+	case "bottom":
+		v.moveCursor(len(v.entries))
+	case "next_ref":
+		return v, v.cycleRef(1)
+	case "prev_ref":
+		return v, v.cycleRef(-1)
+	case "detail":
+		if e, ok := v.currentEntry(); ok {
+			return v, v.diffAgainst(e)
+		}
+	case "checkout":
+		if e, ok := v.currentEntry(); ok {
+			return v, v.checkout(e)
+		}
+	case "branch":
+		if e, ok := v.currentEntry(); ok {
+			v.naming = true
+			v.input.Placeholder = "new branch at " + e.ShortHash
+			v.input.Reset()
+			return v, v.input.Focus()
+		}
+	case "reset":
+		if e, ok := v.currentEntry(); ok {
+			return v, v.confirmReset(e)
+		}
+	case "back":
+		v.showDetail = false
+	}
+	return v, nil
+}
+
+func (v *ReflogView) updateInput(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v.naming = false
+		v.input.Blur()
+		return v, nil
+	case "enter":
+		name := strings.TrimSpace(v.input.Value())
+		v.naming = false
+		v.input.Blur()
+		if e, ok := v.currentEntry(); ok && name != "" {
+			return v, v.createBranch(name, e)
+		}
+		return v, nil
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return v, cmd
+}
+
+// cycleRef switches to the next or previous ref's reflog.
+func (v *ReflogView) cycleRef(delta int) tea.Cmd {
+	if len(v.refs) < 2 {
+		return nil
+	}
+	i := slices.Index(v.refs, v.ref)
+	v.ref = v.refs[(i+delta+len(v.refs))%len(v.refs)]
+	v.showDetail = false
+	return v.refresh()
+}
+
+// diffAgainst shows what changed between the entry and HEAD.
+func (v *ReflogView) diffAgainst(e git.ReflogEntry) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := v.gitSvc.DiffRange(e.Hash, "HEAD")
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return reflogDiffMsg{title: e.Selector() + " → HEAD", diff: diff}
+	}
+}
+
+func (v *ReflogView) checkout(e git.ReflogEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.CheckoutDetached(e.Hash); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return reflogOpDoneMsg{info: "HEAD detached at " + e.ShortHash}
+	}
+}
+
+func (v *ReflogView) createBranch(name string, e git.ReflogEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.CreateBranch(name, e.Hash); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return reflogOpDoneMsg{info: fmt.Sprintf("Created %s at %s", name, e.ShortHash)}
+	}
+}
+
+// confirmReset asks before moving the current branch to the entry,
+// listing the commits that will no longer be on it. `reset --keep` leaves
+// local changes alone and refuses if they would be overwritten.
+func (v *ReflogView) confirmReset(e git.ReflogEntry) tea.Cmd {
+	reset := v.reset(e)
+	return confirm(v.cfg, reset, func() (common.ConfirmMsg, error) {
+		head, err := v.gitSvc.Head()
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		dropped, err := v.gitSvc.Log(maxUnmergedShown, "HEAD", "--not", e.Hash)
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		detail := head + " only moves forward; no commits leave it."
+		if len(dropped) > 0 {
+			count := fmt.Sprint(len(dropped))
+			if len(dropped) == maxUnmergedShown {
+				count += "+"
+			}
+			detail = count + " commit(s) will no longer be on " + head + " (the reflog keeps them):\n\n" +
+				confirmPreview(commitLines(dropped))
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmReset,
+			Title:     fmt.Sprintf("Reset %s to %s (%s)?", head, e.Selector(), e.ShortHash),
+			Detail:    detail + "\n\nLocal changes are kept.",
+			OnConfirm: reset,
+		}, nil
+	})
+}
+
+func (v *ReflogView) reset(e git.ReflogEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.Reset(e.Hash, git.ResetKeep); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return reflogOpDoneMsg{info: "Reset to " + e.ShortHash}
+	}
+}
+
+// moveCursor moves the cursor by delta and keeps it inside the list and
+// the visible window.
+func (v *ReflogView) moveCursor(delta int) {
+	v.cursor = max(0, min(v.cursor+delta, len(v.entries)-1))
+	rows := v.listRows()
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+rows {
+		v.offset = v.cursor - rows + 1
+	}
+}
+
+// listRows is how many entries fit between the header and the key hints.
+func (v *ReflogView) listRows() int { return max(v.height-4, 1) }
+
+func (v *ReflogView) View() string {
+	if v.naming {
+		t := v.styles.Theme
+		e, _ := v.currentEntry()
+		title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).
+			Render("  New Branch at " + e.Selector() + " (" + e.ShortHash + ")")
+		hint := v.styles.Muted.Render("  enter to create | esc to cancel")
+		return lipgloss.JoinVertical(lipgloss.Left, title, "", "  "+v.input.View(), "", hint)
+	}
+
+	left := v.viewList()
+	if v.showDetail {
+		right := v.styles.Panel.Width(v.width/2 - 2).Height(v.height - 2).
+			Render(v.detailVP.View())
+		return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	}
+	return left
+}
+
+func (v *ReflogView) viewList() string {
+	t := v.styles.Theme
+	var b strings.Builder
+	header := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).
+		Render(fmt.Sprintf("  Reflog of %s (%d)", v.ref, len(v.entries)))
+	if len(v.refs) > 1 {
+		header += v.styles.Muted.Render(fmt.Sprintf("  %d/%d  %s",
+			slices.Index(v.refs, v.ref)+1, len(v.refs), v.keys.Hints("next_ref", "next ref", "prev_ref", "prev ref")))
+	}
+	b.WriteString(header + "\n\n")
+
+	if len(v.entries) == 0 {
+		b.WriteString(v.styles.Muted.Render("  No reflog entries") + "\n")
+	}
+	end := min(v.offset+v.listRows(), len(v.entries))
+	for i := v.offset; i < end; i++ {
+		line := v.renderEntry(v.entries[i])
+		if i == v.cursor {
+			b.WriteString(v.styles.ListSelected.Render("▸ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	b.WriteString("\n" + v.styles.Muted.Render("  "+v.keys.Hints(
+		"detail", "diff vs HEAD", "checkout", "checkout", "branch", "branch here", "reset", "reset")))
+	return b.String()
+}
+
+func (v *ReflogView) renderEntry(e git.ReflogEntry) string {
+	t := v.styles.Theme
+	sel := v.styles.Muted.Render(fmt.Sprintf("%-*s", len(v.ref)+5, e.Selector()))
+	hash := v.styles.CommitHash.Render(e.ShortHash)
+	op := lipgloss.NewStyle().Foreground(reflogOpColor(t, e.Op)).Bold(true).
+		Render(fmt.Sprintf("%-9s", ui.Truncate(e.Op, 9)))
+	msg := v.styles.Body.Render(ui.Truncate(e.Message, 50))
+	date := v.styles.Date.Render(e.RelDate)
+	return sel + " " + hash + " " + op + " " + msg + "  " + date
+}
+
+// reflogOpColor colours an operation by how much it moves history around.
+func reflogOpColor(t ui.Theme, op string) lipgloss.Color {
+	switch op {
+	case "commit", "cherry-pick", "revert":
+		return t.Success
+	case "checkout", "branch", "clone", "pull", "merge":
+		return t.Info
+	case "rebase", "am":
+		return t.Warning
+	case "reset":
+		return t.Error
+	}
+	return t.TextMuted
+}
+
+func (v *ReflogView) currentEntry() (git.ReflogEntry, bool) {
+	if v.cursor < 0 || v.cursor >= len(v.entries) {
+		return git.ReflogEntry{}, false
+	}
+	return v.entries[v.cursor], true
+}
+
+func (v *ReflogView) ShortHelp() []components.HelpEntry {
+	return []components.HelpEntry{
+		{Key: v.keys.Help("next_ref"), Desc: "Next ref (HEAD, branches)"},
+		{Key: v.keys.Help("prev_ref"), Desc: "Previous ref"},
+		{Key: v.keys.Help("detail"), Desc: "Diff entry against HEAD"},
+		{Key: v.keys.Help("checkout"), Desc: "Check out (detached)"},
+		{Key: v.keys.Help("branch"), Desc: "Create branch at entry"},
+		{Key: v.keys.Help("reset"), Desc: "Reset current branch to entry"},
+		{Key: v.keys.Help("back"), Desc: "Close diff"},
+	}
+}
+
+func (v *ReflogView) InputCapture() bool { return v.naming }
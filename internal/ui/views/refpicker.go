@@ -0,0 +1,141 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// refPicker is a filterable list of refs: typing narrows the list, the
+// arrow keys move through it and enter picks the highlighted ref. When
+// nothing matches, enter picks the typed text, so any revision (a commit
+// hash, HEAD~3) can be given.
+type refPicker struct {
+	open    bool
+	title   string
+	tag     string // identifies the pick in refPickedMsg
+	refs    []string
+	matches []string
+	cursor  int
+	filter  textinput.Model
+}
+
+// refPickedMsg carries the ref chosen in a refPicker opened with tag.
+type refPickedMsg struct{ tag, ref string }
+
+// maxPickerRows caps how many matches the picker lists at once.
+const maxPickerRows = 15
+
+func newRefPicker() refPicker {
+	ti := textinput.New()
+	ti.Placeholder = "type to filter"
+	ti.CharLimit = 200
+	ti.Width = 40
+	return refPicker{filter: ti}
+}
+
+// Open shows refs titled title, highlighting selected if it is listed.
+func (p *refPicker) Open(title, tag string, refs []string, selected string) tea.Cmd {
+	p.open = true
+	p.title, p.tag, p.refs = title, tag, refs
+	p.filter.Reset()
+	p.match()
+	for i, r := range p.matches {
+		if r == selected {
+			p.cursor = i
+		}
+	}
+	return p.filter.Focus()
+}
+
+func (p *refPicker) close() {
+	p.open = false
+	p.filter.Blur()
+}
+
+// match keeps the refs containing every word of the filter.
+func (p *refPicker) match() {
+	words := strings.Fields(strings.ToLower(p.filter.Value()))
+	p.matches = p.matches[:0]
+	for _, r := range p.refs {
+		lower := strings.ToLower(r)
+		ok := true
+		for _, w := range words {
+			if !strings.Contains(lower, w) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			p.matches = append(p.matches, r)
+		}
+	}
+	p.cursor = 0
+}
+
+// Update handles a key while the picker is open. On enter it closes the
+// picker and returns a refPickedMsg.
+func (p *refPicker) Update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		p.close()
+		return nil
+	case "up", "ctrl+p", "shift+tab":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return nil
+	case "down", "ctrl+n", "tab":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return nil
+	case "enter":
+		picked := refPickedMsg{tag: p.tag, ref: strings.TrimSpace(p.filter.Value())}
+		if len(p.matches) > 0 {
+			picked.ref = p.matches[p.cursor]
+		}
+		if picked.ref == "" {
+			return nil
+		}
+		p.close()
+		return func() tea.Msg { return picked }
+	}
+	value := p.filter.Value()
+	var cmd tea.Cmd
+	p.filter, cmd = p.filter.Update(msg)
+	if p.filter.Value() != value {
+		p.match()
+	}
+	return cmd
+}
+
+func (p *refPicker) View(styles ui.Styles) string {
+	t := styles.Theme
+	lines := []string{
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  " + p.title),
+		"",
+		"  " + p.filter.View(),
+		"",
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, styles.Muted.Render("  no matching refs; enter uses what you typed"))
+	}
+	// Keep the cursor in the window of rows shown.
+	start := max(p.cursor-maxPickerRows+1, 0)
+	for i := start; i < min(len(p.matches), start+maxPickerRows); i++ {
+		if i == p.cursor {
+			lines = append(lines, styles.ListSelected.Render("▸ "+p.matches[i]))
+		} else {
+			lines = append(lines, "  "+p.matches[i])
+		}
+	}
+	if len(p.matches) > maxPickerRows {
+		lines = append(lines, styles.Muted.Render("  …"))
+	}
+	lines = append(lines, "", styles.Muted.Render("  ↑/↓ choose | enter to pick | esc to cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -1,50 +1,185 @@
 package views
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// RemoteView manages remotes and push/pull/fetch operations.
+// RemoteView manages remotes and push/pull/fetch operations. A remote can
+// be expanded to list its remote-tracking branches.
 type RemoteView struct {
 	gitSvc  git.Service
 	styles  ui.Styles
+	cfg     *config.Config
+	keys    keys.Set
 	width   int
 	height  int
 	remotes []git.Remote
-	cursor  int
+	cursor  int // index into rows()
+	offset  int // first body line shown
 	loading bool
+
+	expanded map[string]bool
+	branches map[string][]git.Branch // remote-tracking branches by remote
+
+	// dialog prompts for names and URLs; target is the remote (or, for
+	// checkout, the remote branch) it applies to.
+	dialog *components.Dialog
+	target string
+
+	pushForm pushForm
+	pullForm pullForm
+}
+
+// remoteRow is a line of the list: a remote, or (branch >= 0) one of its
+// remote-tracking branches.
+type remoteRow struct {
+	remote int
+	branch int
 }
 
 type (
-	remoteListMsg   struct{ remotes []git.Remote }
-	remoteOpDoneMsg struct{ info string }
+	remoteListMsg     struct{ remotes []git.Remote }
+	remoteBranchesMsg struct {
+		remote   string
+		branches []git.Branch
+	}
+	remoteOpDoneMsg struct {
+		info string
+		err  error
+	}
+	remoteConflictMsg struct {
+		info string
+		tab  common.TabID
+	}
+	remoteBusyMsg struct{}
+)
+
+// Dialog tags.
+const (
+	remoteDialogAddName  = "remote-add-name"
+	remoteDialogAddURL   = "remote-add-url"
+	remoteDialogRename   = "remote-rename"
+	remoteDialogURL      = "remote-url"
+	remoteDialogPushURL  = "remote-push-url"
+	remoteDialogCheckout = "remote-checkout"
 )
 
 // NewRemoteView creates a new RemoteView.
-func NewRemoteView(gitSvc git.Service, styles ui.Styles) *RemoteView {
-	return &RemoteView{gitSvc: gitSvc, styles: styles}
+func NewRemoteView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *RemoteView {
+	return &RemoteView{
+		gitSvc:   gitSvc,
+		styles:   styles,
+		cfg:      cfg,
+		keys:     keys.New(cfg.Keymap, "remotes"),
+		expanded: map[string]bool{},
+		branches: map[string][]git.Branch{},
+		pushForm: newPushForm(cfg),
+		pullForm: newPullForm(cfg),
+	}
 }
 
 func (v *RemoteView) Init() tea.Cmd { return v.refresh() }
 
-func (v *RemoteView) SetSize(w, h int) { v.width = w; v.height = h }
+func (v *RemoteView) SetSize(w, h int) {
+	v.width, v.height = w, h
+	v.moveCursor(0)
+}
 
 func (v *RemoteView) refresh() tea.Cmd {
-	return func() tea.Msg {
+	cmds := []tea.Cmd{func() tea.Msg {
 		remotes, err := v.gitSvc.Remotes()
 		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
 		return remoteListMsg{remotes: remotes}
+	}}
+	for name := range v.expanded {
+		cmds = append(cmds, v.loadBranches(name))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (v *RemoteView) loadBranches(remote string) tea.Cmd {
+	return func() tea.Msg {
+		branches, err := v.gitSvc.RemoteBranches(remote)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return remoteBranchesMsg{remote: remote, branches: branches}
+	}
+}
+
+// rows lists the remotes, each followed by its branches when expanded.
+func (v *RemoteView) rows() []remoteRow {
+	var rows []remoteRow
+	for i, r := range v.remotes {
+		rows = append(rows, remoteRow{remote: i, branch: -1})
+		if v.expanded[r.Name] {
+			for j := range v.branches[r.Name] {
+				rows = append(rows, remoteRow{remote: i, branch: j})
+			}
+		}
+	}
+	return rows
+}
+
+func (v *RemoteView) currentRow() (remoteRow, bool) {
+	rows := v.rows()
+	if v.cursor < 0 || v.cursor >= len(rows) {
+		return remoteRow{}, false
+	}
+	return rows[v.cursor], true
+}
+
+// currentBranch returns the remote-tracking branch under the cursor.
+func (v *RemoteView) currentBranch() (git.Branch, bool) {
+	row, ok := v.currentRow()
+	if !ok || row.branch < 0 {
+		return git.Branch{}, false
 	}
+	return v.branches[v.remotes[row.remote].Name][row.branch], true
+}
+
+// moveCursor moves the cursor by delta rows and scrolls it into view.
+func (v *RemoteView) moveCursor(delta int) {
+	rows := v.rows()
+	v.cursor = max(0, min(v.cursor+delta, len(rows)-1))
+	if len(rows) == 0 {
+		v.offset = 0
+		return
+	}
+	top := 0
+	for _, row := range rows[:v.cursor] {
+		top += rowHeight(row)
+	}
+	// The header, status and hint lines take 4.
+	height := max(v.height-4, 1)
+	if top < v.offset {
+		v.offset = top
+	} else if bottom := top + rowHeight(rows[v.cursor]); bottom > v.offset+height {
+		v.offset = bottom - height
+	}
+}
+
+// rowHeight is the number of body lines a row takes.
+func rowHeight(row remoteRow) int {
+	if row.branch >= 0 {
+		return 1
+	}
+	return 4 // name, fetch URL, push URL, blank
 }
 
 func (v *RemoteView) Update(msg tea.Msg) (common.View, tea.Cmd) {
@@ -52,13 +187,45 @@ func (v *RemoteView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 	case remoteListMsg:
 		v.remotes = msg.remotes
 		v.loading = false
-		if v.cursor >= len(v.remotes) && len(v.remotes) > 0 {
-			v.cursor = len(v.remotes) - 1
+		// Forget removed (or renamed) remotes' branches.
+		for name := range v.expanded {
+			if !slices.ContainsFunc(v.remotes, func(r git.Remote) bool { return r.Name == name }) {
+				delete(v.expanded, name)
+				delete(v.branches, name)
+			}
 		}
+		v.moveCursor(0)
+		return v, nil
+
+	case remoteBranchesMsg:
+		v.branches[msg.remote] = msg.branches
+		v.moveCursor(0)
+		return v, nil
+
+	case components.DialogResult:
+		return v, v.dialogDone(msg)
+
+	case remoteConflictMsg:
+		v.loading = false
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh,
+			func() tea.Msg { return common.SwitchTabMsg{Tab: msg.tab} })
+
+	case pullSubmitMsg:
+		v.loading = true
+		return v, v.pull(msg.remote, msg.branch, msg.opts)
+
+	case pushSubmitMsg:
+		return v, v.confirmPush(msg.opts)
+
+	case remoteBusyMsg:
+		v.loading = true
 		return v, nil
 
 	case remoteOpDoneMsg:
 		v.loading = false
+		if msg.err != nil {
+			return v, common.CmdErr(msg.err)
+		}
 		return v, tea.Batch(
 			common.CmdInfo(msg.info),
 			common.CmdRefresh,
@@ -70,18 +237,22 @@ func (v *RemoteView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 	case tea.MouseMsg:
 		switch msg.Button {
 		case tea.MouseButtonWheelUp:
-			if v.cursor > 0 {
-				v.cursor--
-			}
+			v.moveCursor(-1)
 		case tea.MouseButtonWheelDown:
-			if v.cursor < len(v.remotes)-1 {
-				v.cursor++
-			}
+			v.moveCursor(1)
 		case tea.MouseButtonLeft:
-			if msg.Action == tea.MouseActionPress {
-				idx := msg.Y - 2 - 2
-				if idx >= 0 && idx < len(v.remotes) {
-					v.cursor = idx
+			if msg.Action == tea.MouseActionPress && v.dialog == nil {
+				// Content starts at Y=2, header is 2 lines.
+				line := v.offset + msg.Y - 2 - 2
+				for i, row := range v.rows() {
+					if line < 0 {
+						break
+					}
+					if line < rowHeight(row) {
+						v.cursor = i
+						break
+					}
+					line -= rowHeight(row)
 				}
 			}
 		}
@@ -94,123 +265,362 @@ func (v *RemoteView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 }
 
 func (v *RemoteView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "j", "down":
-		if v.cursor < len(v.remotes)-1 {
-			v.cursor++
+	if v.dialog != nil {
+		d, cmd := v.dialog.Update(msg)
+		v.dialog = &d
+		return v, cmd
+	}
+	if v.pushForm.open {
+		return v, v.pushForm.Update(msg)
+	}
+	if v.pullForm.open {
+		return v, v.pullForm.Update(msg)
+	}
+
+	switch v.keys.Action(msg) {
+	case "down":
+		v.moveCursor(1)
+	case "up":
+		v.moveCursor(-1)
+	case "expand":
+		if b, ok := v.currentBranch(); ok {
+			return v, v.openCheckout(b)
+		}
+		if r, ok := v.currentRemote(); ok {
+			if v.expanded[r.Name] {
+				delete(v.expanded, r.Name)
+				v.moveCursor(0)
+				return v, nil
+			}
+			v.expanded[r.Name] = true
+			return v, v.loadBranches(r.Name)
+		}
+	case "checkout":
+		if b, ok := v.currentBranch(); ok {
+			return v, v.openCheckout(b)
+		}
+	case "copy":
+		if b, ok := v.currentBranch(); ok {
+			return v, copyText(v.cfg, b.Name, b.Name)
+		}
+		if r, ok := v.currentRemote(); ok {
+			return v, copyText(v.cfg, "URL of "+r.Name, r.FetchURL)
+		}
+	case "add":
+		return v, v.openDialog(components.NewInputDialog(v.styles, "Add remote", "name, e.g. upstream", remoteDialogAddName), "")
+	case "rename":
+		if r, ok := v.currentRemote(); ok {
+			return v, v.openDialog(components.NewInputDialog(v.styles, "Rename remote "+r.Name, "new name", remoteDialogRename).
+				WithValue(r.Name), r.Name)
+		}
+	case "remove":
+		if r, ok := v.currentRemote(); ok {
+			return v, v.confirmRemove(r.Name)
+		}
+	case "edit_url":
+		if r, ok := v.currentRemote(); ok {
+			return v, v.openDialog(components.NewInputDialog(v.styles, "Fetch URL of "+r.Name, "URL", remoteDialogURL).
+				WithValue(r.FetchURL), r.Name)
+		}
+	case "edit_push_url":
+		if r, ok := v.currentRemote(); ok {
+			return v, v.openDialog(components.NewInputDialog(v.styles, "Push URL of "+r.Name, "URL", remoteDialogPushURL).
+				WithValue(r.PushURL), r.Name)
 		}
-	case "k", "up":
-		if v.cursor > 0 {
-			v.cursor--
+	case "prune":
+		if r, ok := v.currentRemote(); ok {
+			v.loading = true
+			return v, v.prune(r.Name)
 		}
-	case "f": // Fetch
+	case "fetch":
 		if r, ok := v.currentRemote(); ok {
 			v.loading = true
 			return v, v.fetch(r.Name)
 		}
-	case "F": // Fetch all
+	case "fetch_all":
 		v.loading = true
 		return v, v.fetchAll()
-	case "p": // Pull
+	case "pull":
 		if r, ok := v.currentRemote(); ok {
-			v.loading = true
-			head, _ := v.gitSvc.Head()
-			return v, v.pull(r.Name, head)
+			head, err := v.gitSvc.Head()
+			if err != nil {
+				return v, common.CmdErr(err)
+			}
+			// Pull the upstream's branch when it lives on this remote.
+			branch := head
+			if name, ok := strings.CutPrefix(v.gitSvc.Upstream(), r.Name+"/"); ok {
+				branch = name
+			}
+			opts := git.PullOptions{Mode: git.PullMode(v.cfg.PullMode), Autostash: v.cfg.PullAutostash}
+			if opts.Mode == "" {
+				opts.Mode = v.gitSvc.PullDefault()
+			}
+			v.pullForm.Open(r.Name, branch, head, opts)
 		}
-	case "P": // Push
+	case "push":
 		if r, ok := v.currentRemote(); ok {
-			v.loading = true
-			head, _ := v.gitSvc.Head()
-			return v, v.push(r.Name, head)
+			head, err := v.gitSvc.Head()
+			if err != nil {
+				return v, common.CmdErr(err)
+			}
+			if v.gitSvc.RefHash("refs/heads/"+head) == "" {
+				return v, common.CmdErr(fmt.Errorf("HEAD is detached; check out a branch to push"))
+			}
+			return v, v.pushForm.Open(v.remotes, r.Name, head, v.gitSvc.Upstream())
 		}
 	}
 	return v, nil
 }
 
-func (v *RemoteView) fetch(remote string) tea.Cmd {
-	return func() tea.Msg {
-		if err := v.gitSvc.Fetch(remote); err != nil {
-			return common.ErrMsg{Err: err}
+func (v *RemoteView) openDialog(d components.Dialog, target string) tea.Cmd {
+	v.dialog = &d
+	v.target = target
+	return textinput.Blink
+}
+
+// openCheckout asks for the name of the local branch that will track b,
+// suggesting b's name without the remote.
+func (v *RemoteView) openCheckout(b git.Branch) tea.Cmd {
+	local := b.Name
+	if _, after, ok := strings.Cut(b.Name, "/"); ok {
+		local = after
+	}
+	return v.openDialog(components.NewInputDialog(v.styles, "Check out "+b.Name+" as", "local branch name", remoteDialogCheckout).
+		WithValue(local), b.Name)
+}
+
+// dialogDone acts on a submitted dialog. Adding a remote asks for its
+// name, then its URL.
+func (v *RemoteView) dialogDone(res components.DialogResult) tea.Cmd {
+	v.dialog = nil
+	value := strings.TrimSpace(res.Value)
+	if !res.Confirmed || value == "" {
+		return nil
+	}
+	target := v.target
+	switch res.Tag {
+	case remoteDialogAddName:
+		return v.openDialog(components.NewInputDialog(v.styles, "URL of "+value, "https://… or git@…", remoteDialogAddURL), value)
+	case remoteDialogAddURL:
+		return v.remoteOp("Added remote "+target, func() error { return v.gitSvc.AddRemote(target, value) })
+	case remoteDialogRename:
+		if value == target {
+			return nil
+		}
+		if v.expanded[target] {
+			v.expanded[value] = true
+		}
+		return v.remoteOp(fmt.Sprintf("Renamed %s to %s", target, value), func() error {
+			return v.gitSvc.RenameRemote(target, value)
+		})
+	case remoteDialogURL, remoteDialogPushURL:
+		push := res.Tag == remoteDialogPushURL
+		kind := "fetch"
+		if push {
+			kind = "push"
 		}
-		return remoteOpDoneMsg{info: "Fetched from " + remote}
+		return v.remoteOp(fmt.Sprintf("Set %s URL of %s", kind, target), func() error {
+			return v.gitSvc.SetRemoteURL(target, value, push)
+		})
+	case remoteDialogCheckout:
+		return v.remoteOp(fmt.Sprintf("Checked out %s tracking %s", value, target), func() error {
+			return v.gitSvc.CheckoutTracking(value, target)
+		})
 	}
+	return nil
 }
 
-func (v *RemoteView) fetchAll() tea.Cmd {
-	return func() tea.Msg {
-		for _, r := range v.remotes {
-			if err := v.gitSvc.Fetch(r.Name); err != nil {
-				return common.ErrMsg{Err: err}
+// remoteOp runs a quick (local) remote operation.
+func (v *RemoteView) remoteOp(info string, op func() error) tea.Cmd {
+	return func() tea.Msg { return remoteOpDoneMsg{info: info, err: op()} }
+}
+
+// confirmRemove asks before removing remote, listing the remote-tracking
+// branches that go with it.
+func (v *RemoteView) confirmRemove(remote string) tea.Cmd {
+	remove := v.remoteOp("Removed remote "+remote, func() error { return v.gitSvc.RemoveRemote(remote) })
+	return confirm(v.cfg, remove, func() (common.ConfirmMsg, error) {
+		branches, err := v.gitSvc.RemoteBranches(remote)
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		detail := "Its configuration is deleted; it has no remote-tracking branches."
+		if len(branches) > 0 {
+			names := make([]string, len(branches))
+			for i, b := range branches {
+				names[i] = b.Name
 			}
+			detail = fmt.Sprintf("Its configuration and %d remote-tracking branch(es) are deleted, "+
+				"and local branches stop tracking them:\n\n%s", len(branches), confirmPreview(names))
 		}
-		return remoteOpDoneMsg{info: "Fetched from all remotes"}
-	}
+		return common.ConfirmMsg{
+			Kind:      confirmRemoveRemote,
+			Title:     "Remove remote " + remote + "?",
+			Detail:    detail,
+			OnConfirm: remove,
+		}, nil
+	})
 }
 
-func (v *RemoteView) pull(remote, branch string) tea.Cmd {
-	return func() tea.Msg {
-		if err := v.gitSvc.Pull(remote, branch); err != nil {
-			return common.ErrMsg{Err: err}
+func (v *RemoteView) prune(remote string) tea.Cmd {
+	return runOperation("prune "+remote, func(ctx context.Context, _ git.ProgressFunc) error {
+		return v.gitSvc.PruneRemote(ctx, remote)
+	}, remoteDone("Pruned stale branches of "+remote))
+}
+
+// remoteDone returns the result of a remote operation, reported as info
+// on success.
+func remoteDone(info string) func(error) tea.Msg {
+	return func(err error) tea.Msg { return remoteOpDoneMsg{info: info, err: err} }
+}
+
+func (v *RemoteView) fetch(remote string) tea.Cmd {
+	return runOperation("fetch "+remote, func(ctx context.Context, progress git.ProgressFunc) error {
+		return v.gitSvc.Fetch(ctx, remote, progress)
+	}, remoteDone("Fetched from "+remote))
+}
+
+func (v *RemoteView) fetchAll() tea.Cmd {
+	remotes := v.remotes
+	return runOperation("fetch all", func(ctx context.Context, progress git.ProgressFunc) error {
+		for _, r := range remotes {
+			if err := v.gitSvc.Fetch(ctx, r.Name, progress); err != nil {
+				return err
+			}
 		}
-		return remoteOpDoneMsg{info: fmt.Sprintf("Pulled %s from %s", branch, remote)}
-	}
+		return nil
+	}, remoteDone("Fetched from all remotes"))
 }
 
-func (v *RemoteView) push(remote, branch string) tea.Cmd {
-	return func() tea.Msg {
-		if err := v.gitSvc.Push(remote, branch, false); err != nil {
-			return common.ErrMsg{Err: err}
+// pull pulls remote's branch. When it stops part way, the Rebase tab (for
+// a rebase) or the Conflicts tab (for a merge) opens.
+func (v *RemoteView) pull(remote, branch string, opts git.PullOptions) tea.Cmd {
+	return runOperation("pull "+remote, func(ctx context.Context, progress git.ProgressFunc) error {
+		return v.gitSvc.Pull(ctx, remote, branch, opts, progress)
+	}, func(err error) tea.Msg {
+		if err != nil {
+			files, _ := v.gitSvc.ConflictFiles()
+			switch {
+			case v.gitSvc.IsRebasing() && len(files) > 0:
+				return remoteConflictMsg{info: fmt.Sprintf("Pull stopped the rebase on conflicts in %d file(s)", len(files)), tab: common.TabRebase}
+			case v.gitSvc.IsRebasing():
+				return remoteConflictMsg{info: "Pull stopped the rebase", tab: common.TabRebase}
+			case len(files) > 0:
+				return remoteConflictMsg{info: fmt.Sprintf("Pull stopped on conflicts in %d file(s)", len(files)), tab: common.TabConflicts}
+			}
 		}
-		return remoteOpDoneMsg{info: fmt.Sprintf("Pushed %s to %s", branch, remote)}
-	}
+		return remoteOpDoneMsg{info: fmt.Sprintf("Pulled %s from %s (%s)", branch, remote, opts.Mode), err: err}
+	})
+}
+
+// confirmPush asks before pushing (see askPush), showing the view as busy
+// while the push runs.
+func (v *RemoteView) confirmPush(opts git.PushOptions) tea.Cmd {
+	busy := func() tea.Msg { return remoteBusyMsg{} }
+	return askPush(v.gitSvc, v.cfg, opts, func(opts git.PushOptions) tea.Cmd {
+		return tea.Sequence(busy, v.push(opts))
+	})
+}
+
+func (v *RemoteView) push(opts git.PushOptions) tea.Cmd {
+	info := fmt.Sprintf("Pushed %s to %s/%s", opts.Branch, opts.Remote, opts.Target)
+	return runOperation("push "+opts.Remote, func(ctx context.Context, progress git.ProgressFunc) error {
+		return v.gitSvc.Push(ctx, opts, progress)
+	}, remoteDone(info))
 }
 
 func (v *RemoteView) View() string {
 	t := v.styles.Theme
+	if v.dialog != nil {
+		return ui.PlaceCentre(v.width, v.height, v.dialog.View())
+	}
+	if v.pushForm.open {
+		return v.pushForm.View(v.styles)
+	}
+	if v.pullForm.open {
+		return v.pullForm.View(v.styles)
+	}
+	hints := v.styles.Muted.Render("  " + v.keys.Hints("fetch", "fetch", "pull", "pull", "push", "push",
+		"expand", "branches", "add", "add", "edit_url", "url", "remove", "remove"))
 	if len(v.remotes) == 0 {
-		return ui.PlaceCentre(v.width, v.height,
-			lipgloss.NewStyle().Foreground(t.TextMuted).Render("No remotes configured"))
+		return ui.PlaceCentre(v.width, v.height, lipgloss.JoinVertical(lipgloss.Center,
+			lipgloss.NewStyle().Foreground(t.TextMuted).Render("No remotes configured"), "",
+			v.styles.Muted.Render(v.keys.Hints("add", "add a remote"))))
 	}
 
-	var b strings.Builder
-	b.WriteString(lipgloss.NewStyle().Foreground(t.Remote).Bold(true).
-		Render(fmt.Sprintf("  Remotes (%d)", len(v.remotes))) + "\n\n")
-
-	for i, r := range v.remotes {
-		name := lipgloss.NewStyle().Foreground(t.Remote).Bold(true).Render(r.Name)
-		fetch := v.styles.Muted.Render("fetch: " + r.FetchURL)
-		push := v.styles.Muted.Render("push:  " + r.PushURL)
-		line := name + "\n      " + fetch + "\n      " + push
-
-		if i == v.cursor {
-			b.WriteString(v.styles.ListSelected.Render("▸ "+line) + "\n\n")
-		} else {
-			b.WriteString("  " + line + "\n\n")
+	var body []string
+	for i, row := range v.rows() {
+		r := v.remotes[row.remote]
+		selected := i == v.cursor
+		if row.branch >= 0 {
+			body = append(body, v.branchLine(v.branches[r.Name][row.branch], selected))
+			continue
+		}
+		arrow := "▸ "
+		if v.expanded[r.Name] {
+			arrow = "▾ "
 		}
+		name := lipgloss.NewStyle().Foreground(t.Remote).Bold(true).Render(arrow + r.Name)
+		if selected {
+			name = v.styles.ListSelected.Render(arrow + r.Name)
+		}
+		body = append(body, "  "+name,
+			"      "+v.styles.Muted.Render("fetch: "+r.FetchURL),
+			"      "+v.styles.Muted.Render("push:  "+r.PushURL),
+			"")
 	}
 
+	offset := min(v.offset, len(body)-1)
+	body = body[offset:min(len(body), offset+max(v.height-4, 1))]
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(t.Remote).Bold(true).
+		Render(fmt.Sprintf("  Remotes (%d)", len(v.remotes))) + "\n\n")
+	b.WriteString(strings.Join(body, "\n") + "\n")
 	if v.loading {
 		b.WriteString(lipgloss.NewStyle().Foreground(t.Warning).Render("  Working...") + "\n")
 	}
-
-	b.WriteString(v.styles.Muted.Render("  f fetch  F fetch all  p pull  P push"))
+	b.WriteString(hints)
 	return b.String()
 }
 
+func (v *RemoteView) branchLine(br git.Branch, selected bool) string {
+	line := fmt.Sprintf("%-30s %s %s", ui.Truncate(br.Name, 30), br.Hash, ui.Truncate(br.Subject, max(v.width-50, 10)))
+	if selected {
+		return "      " + v.styles.ListSelected.Render(line)
+	}
+	return "      " + lipgloss.NewStyle().Foreground(v.styles.Theme.Remote).Render(fmt.Sprintf("%-30s", ui.Truncate(br.Name, 30))) +
+		" " + v.styles.Muted.Render(br.Hash+" "+ui.Truncate(br.Subject, max(v.width-50, 10)))
+}
+
+// currentRemote returns the remote under the cursor, or the remote of the
+// branch under it.
 func (v *RemoteView) currentRemote() (git.Remote, bool) {
-	if v.cursor < 0 || v.cursor >= len(v.remotes) {
+	row, ok := v.currentRow()
+	if !ok {
 		return git.Remote{}, false
 	}
-	return v.remotes[v.cursor], true
+	return v.remotes[row.remote], true
 }
 
 func (v *RemoteView) ShortHelp() []components.HelpEntry {
 	return []components.HelpEntry{
-		{Key: "f", Desc: "Fetch from remote"},
-		{Key: "F", Desc: "Fetch all remotes"},
-		{Key: "p", Desc: "Pull"},
-		{Key: "P", Desc: "Push"},
+		{Key: v.keys.Help("fetch"), Desc: "Fetch from remote"},
+		{Key: v.keys.Help("fetch_all"), Desc: "Fetch all remotes"},
+		{Key: v.keys.Help("pull"), Desc: "Pull (merge, rebase or fast-forward only)"},
+		{Key: v.keys.Help("push"), Desc: "Push (choose remote, branch and options)"},
+		{Key: v.keys.Help("expand"), Desc: "Show remote-tracking branches"},
+		{Key: v.keys.Help("checkout"), Desc: "Check out branch as local tracking branch"},
+		{Key: v.keys.Help("add"), Desc: "Add remote"},
+		{Key: v.keys.Help("rename"), Desc: "Rename remote"},
+		{Key: v.keys.Help("remove"), Desc: "Remove remote"},
+		{Key: v.keys.Help("edit_url"), Desc: "Edit fetch URL"},
+		{Key: v.keys.Help("edit_push_url"), Desc: "Edit push URL"},
+		{Key: v.keys.Help("prune"), Desc: "Prune stale remote-tracking branches"},
+		{Key: v.keys.Help("copy"), Desc: "Copy remote URL or branch name"},
 	}
 }
 
-func (v *RemoteView) InputCapture() bool { return false }
+func (v *RemoteView) InputCapture() bool {
+	return v.dialog != nil || v.pushForm.open || v.pullForm.open
+}
@@ -0,0 +1,150 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// resetModes are the choices offered by resetForm, keyed by the letter
+// that picks them.
+var resetModes = []struct {
+	mode git.ResetMode
+	key  string
+	desc string
+}{
+	{git.ResetSoft, "s", "keep the changes staged"},
+	{git.ResetMixed, "m", "keep the changes in the working tree, unstaged"},
+	{git.ResetKeep, "k", "update files, but refuse if local changes would be overwritten"},
+	{git.ResetHard, "h", "discard all changes to tracked files"},
+}
+
+// resetForm is the "reset current branch" prompt shared by the Log and
+// Branches views: pick a mode, then confirm with a preview of what moves.
+type resetForm struct {
+	open   bool
+	ref    string // where HEAD's branch moves to
+	target string // ref as shown in the title
+	choice int    // index into resetModes
+}
+
+type resetOpDoneMsg struct{ info string }
+
+// Open shows the form for resetting to ref, titled with target. Mixed,
+// git's default, is preselected.
+func (f *resetForm) Open(ref, target string) {
+	f.open = true
+	f.ref, f.target = ref, target
+	f.choice = 1
+}
+
+// Update handles a key while the form is open. On enter it closes the form
+// and returns the command that confirms and runs the reset.
+func (f *resetForm) Update(gitSvc git.Service, cfg *config.Config, msg tea.KeyMsg) tea.Cmd {
+	switch k := msg.String(); k {
+	case "esc":
+		f.open = false
+	case "up", "shift+tab":
+		f.choice = (f.choice + len(resetModes) - 1) % len(resetModes)
+	case "down", "tab":
+		f.choice = (f.choice + 1) % len(resetModes)
+	case "enter":
+		f.open = false
+		return confirmResetTo(gitSvc, cfg, f.ref, f.target, resetModes[f.choice].mode)
+	default:
+		for i, m := range resetModes {
+			if m.key == k {
+				f.choice = i
+			}
+		}
+	}
+	return nil
+}
+
+func (f *resetForm) View(styles ui.Styles) string {
+	t := styles.Theme
+	lines := []string{
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  Reset current branch to " + f.target),
+		"",
+	}
+	for i, m := range resetModes {
+		line := fmt.Sprintf("%s  --%-5s  %s", m.key, m.mode, m.desc)
+		if i == f.choice {
+			lines = append(lines, styles.ListSelected.Render("▸ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "", styles.Muted.Render("  s/m/k/h or ↑/↓ choose | enter to preview | esc to cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// confirmResetTo asks before resetting the current branch to ref, listing
+// the commits that will leave it and the files the reset touches: for a
+// hard reset every tracked file that differs from ref, local changes
+// included.
+func confirmResetTo(gitSvc git.Service, cfg *config.Config, ref, target string, mode git.ResetMode) tea.Cmd {
+	reset := func() tea.Msg {
+		if err := gitSvc.Reset(ref, mode); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return resetOpDoneMsg{info: fmt.Sprintf("Reset (--%s) to %s", mode, target)}
+	}
+	return confirm(cfg, reset, func() (common.ConfirmMsg, error) {
+		head, err := gitSvc.Head()
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		dropped, err := gitSvc.Log(maxUnmergedShown, "HEAD", "--not", ref)
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		detail := head + " only moves forward; no commits leave it."
+		if len(dropped) > 0 {
+			count := fmt.Sprint(len(dropped))
+			if len(dropped) == maxUnmergedShown {
+				count += "+"
+			}
+			detail = count + " commit(s) will no longer be on " + head + " (the reflog keeps them):\n\n" +
+				confirmPreview(commitLines(dropped))
+		}
+
+		switch mode {
+		case git.ResetSoft:
+			detail += "\n\nTheir changes stay staged."
+		case git.ResetMixed:
+			detail += "\n\nTheir changes stay in the working tree, unstaged."
+		case git.ResetKeep, git.ResetHard:
+			// keep only rewrites files that differ between HEAD and ref;
+			// hard also throws away local changes.
+			to := "HEAD"
+			if mode == git.ResetHard {
+				to = ""
+			}
+			stat, err := gitSvc.DiffRangeStat(ref, to)
+			if err != nil {
+				return common.ConfirmMsg{}, err
+			}
+			lines := splitLines(stat)
+			switch {
+			case len(lines) == 0:
+				detail += "\n\nNo files change."
+			case mode == git.ResetHard:
+				detail += "\n\nFiles reset to " + target + ", uncommitted changes lost:\n\n" + confirmPreview(lines)
+			default:
+				detail += "\n\nFiles updated (local changes are kept):\n\n" + confirmPreview(lines)
+			}
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmReset,
+			Title:     fmt.Sprintf("Reset %s to %s (--%s)?", head, target, mode),
+			Detail:    detail,
+			OnConfirm: reset,
+		}, nil
+	})
+}
@@ -0,0 +1,101 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// rewordForm is the Log view's message prompt for a commit: its new
+// message when rewording, or the text a squash! commit adds to it. It
+// takes the commit box's keys.
+type rewordForm struct {
+	open   bool
+	squash bool
+	commit git.Commit
+	keys   keys.Set
+	ta     textarea.Model
+}
+
+func newRewordForm(cfg *config.Config) rewordForm {
+	ta := textarea.New()
+	ta.CharLimit = 0
+	return rewordForm{keys: keys.New(cfg.Keymap, "commit"), ta: ta}
+}
+
+// Open shows the form for c: prefilled with its message when rewording,
+// empty for a squash.
+func (f *rewordForm) Open(c git.Commit, squash bool, width, height int) tea.Cmd {
+	f.open = true
+	f.squash = squash
+	f.commit = c
+	f.ta.SetWidth(max(width-6, 20))
+	f.ta.SetHeight(max(3, min(12, height-10)))
+	f.ta.Reset()
+	if squash {
+		f.ta.Placeholder = "Text to add to the message (optional)"
+	} else {
+		f.ta.Placeholder = "Commit message..."
+		f.ta.SetValue(commitMessage(c))
+	}
+	return f.ta.Focus()
+}
+
+func (f *rewordForm) close() {
+	f.open = false
+	f.ta.Blur()
+}
+
+// Update handles a key while the form is open. On submit it closes the
+// form and returns the command that rewords or commits.
+func (f *rewordForm) Update(gitSvc git.Service, msg tea.KeyMsg) tea.Cmd {
+	switch f.keys.Action(msg) {
+	case "cancel":
+		f.close()
+		return nil
+	case "submit":
+		message := cleanupMessage(f.ta.Value())
+		c, squash := f.commit, f.squash
+		if !squash && message == "" {
+			return common.CmdErr(fmt.Errorf("commit message cannot be empty"))
+		}
+		f.close()
+		return func() tea.Msg {
+			if squash {
+				if err := gitSvc.CommitFixup(c.Hash, true, message); err != nil {
+					return common.ErrMsg{Err: err}
+				}
+				return logOpDoneMsg{info: "Created squash! commit for " + c.ShortHash}
+			}
+			if err := gitSvc.Reword(c.Hash, message); err != nil {
+				return common.ErrMsg{Err: err}
+			}
+			return logOpDoneMsg{info: "Reworded " + c.ShortHash}
+		}
+	}
+	var cmd tea.Cmd
+	f.ta, cmd = f.ta.Update(msg)
+	return cmd
+}
+
+func (f *rewordForm) View(styles ui.Styles) string {
+	t := styles.Theme
+	title := "  Reword " + f.commit.ShortHash
+	verb := "reword"
+	if f.squash {
+		title = "  Squash staged changes into " + f.commit.ShortHash
+		verb = "commit"
+	}
+	hint := styles.Muted.Render("  " + f.keys.Hints("submit", verb, "cancel", "cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render(title),
+		styles.Muted.Render("  "+ui.Truncate(f.commit.Subject, 70)), "",
+		"  "+f.ta.View(), "", hint)
+}
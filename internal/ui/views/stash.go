@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +20,8 @@ import (
 type StashView struct {
 	gitSvc  git.Service
 	styles  ui.Styles
+	cfg     *config.Config
+	keys    keys.Set
 	width   int
 	height  int
 	entries []git.StashEntry
@@ -30,6 +34,7 @@ type StashView struct {
 	// Detail
 	showDetail bool
 	detailVP   viewport.Model
+	detailDiff string
 }
 
 type (
@@ -38,12 +43,18 @@ type (
 )
 
 // NewStashView creates a new StashView.
-func NewStashView(gitSvc git.Service, styles ui.Styles) *StashView {
+func NewStashView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *StashView {
 	ti := textinput.New()
 	ti.Placeholder = "stash message (optional)"
 	ti.CharLimit = 200
 	ti.Width = 50
-	return &StashView{gitSvc: gitSvc, styles: styles, input: ti}
+	return &StashView{
+		gitSvc: gitSvc,
+		styles: styles,
+		cfg:    cfg,
+		keys:   keys.New(cfg.Keymap, "stash"),
+		input:  ti,
+	}
 }
 
 func (v *StashView) Init() tea.Cmd { return v.refresh() }
@@ -78,6 +89,7 @@ func (v *StashView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 		v.showDetail = true
 		v.detailVP = viewport.New(v.width/2, v.height-2)
 		v.detailVP.SetContent(renderDiffColored(v.styles, msg.diff))
+		v.detailDiff = msg.diff
 		return v, nil
 
 	case common.RefreshMsg:
@@ -117,37 +129,46 @@ func (v *StashView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 }
 
 func (v *StashView) updateNormal(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "j", "down":
+	switch v.keys.Action(msg) {
+	case "down":
 		if v.cursor < len(v.entries)-1 {
 			v.cursor++
 		}
-	case "k", "up":
+	case "up":
 		if v.cursor > 0 {
 			v.cursor--
 		}
-	case "s": // Save/push new stash
+	case "save":
 		v.saving = true
 		v.input.Reset()
 		v.input.Focus()
 		return v, v.input.Focus()
-	case "p": // Pop
+	case "pop":
 		if v.cursor < len(v.entries) {
 			return v, v.stashPop(v.entries[v.cursor].Index)
 		}
-	case "a": // Apply
+	case "apply":
 		if v.cursor < len(v.entries) {
 			return v, v.stashApply(v.entries[v.cursor].Index)
 		}
-	case "D": // Drop
+	case "drop":
 		if v.cursor < len(v.entries) {
-			return v, v.stashDrop(v.entries[v.cursor].Index)
+			return v, v.confirmDrop(v.entries[v.cursor])
 		}
-	case "enter", "d": // Show diff
+	case "show":
 		if v.cursor < len(v.entries) {
 			return v, v.stashShow(v.entries[v.cursor].Index)
 		}
-	case "esc":
+	case "copy_ref":
+		if v.cursor < len(v.entries) {
+			ref := fmt.Sprintf("stash@{%d}", v.entries[v.cursor].Index)
+			return v, copyText(v.cfg, ref, ref)
+		}
+	case "copy_diff":
+		if v.showDetail {
+			return v, copyText(v.cfg, "stash diff", v.detailDiff)
+		}
+	case "back":
 		v.showDetail = false
 	}
 	return v, nil
@@ -197,6 +218,27 @@ func (v *StashView) stashApply(idx int) tea.Cmd {
 	}
 }
 
+// confirmDrop asks before dropping a stash, showing its diffstat.
+func (v *StashView) confirmDrop(e git.StashEntry) tea.Cmd {
+	drop := v.stashDrop(e.Index)
+	return confirm(v.cfg, drop, func() (common.ConfirmMsg, error) {
+		stat, err := v.gitSvc.StashStat(e.Index)
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		detail := fmt.Sprintf("stash@{%d}: %s", e.Index, e.Message)
+		if lines := splitLines(stat); len(lines) > 0 {
+			detail += "\n\n" + confirmPreview(lines)
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmDropStash,
+			Title:     "Drop stash?",
+			Detail:    detail,
+			OnConfirm: drop,
+		}, nil
+	})
+}
+
 func (v *StashView) stashDrop(idx int) tea.Cmd {
 	return func() tea.Msg {
 		if err := v.gitSvc.StashDrop(idx); err != nil {
@@ -260,17 +302,19 @@ func (v *StashView) viewList() string {
 		}
 	}
 
-	b.WriteString("\n" + v.styles.Muted.Render("  s save  p pop  a apply  D drop  d/enter show diff"))
+	b.WriteString("\n" + v.styles.Muted.Render("  "+v.keys.Hints("save", "save", "pop", "pop", "apply", "apply", "drop", "drop", "show", "show diff")))
 	return b.String()
 }
 
 func (v *StashView) ShortHelp() []components.HelpEntry {
 	return []components.HelpEntry{
-		{Key: "s", Desc: "Save stash"},
-		{Key: "p", Desc: "Pop stash"},
-		{Key: "a", Desc: "Apply stash"},
-		{Key: "D", Desc: "Drop stash"},
-		{Key: "d / enter", Desc: "Show stash diff"},
+		{Key: v.keys.Help("save"), Desc: "Save stash"},
+		{Key: v.keys.Help("pop"), Desc: "Pop stash"},
+		{Key: v.keys.Help("apply"), Desc: "Apply stash"},
+		{Key: v.keys.Help("drop"), Desc: "Drop stash"},
+		{Key: v.keys.Help("show"), Desc: "Show stash diff"},
+		{Key: v.keys.Help("copy_ref"), Desc: "Copy stash ref"},
+		{Key: v.keys.Help("copy_diff"), Desc: "Copy the shown stash diff"},
 	}
 }
 
@@ -6,9 +6,11 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -60,6 +62,7 @@ type statusCachedStyles struct {
 	diffFocusDot  lipgloss.Style
 	diffEmptyHint lipgloss.Style
 	diffScrollPct lipgloss.Style
+	warnStyle     lipgloss.Style
 }
 
 func newStatusCachedStyles(t ui.Theme) statusCachedStyles {
@@ -82,6 +85,7 @@ func newStatusCachedStyles(t ui.Theme) statusCachedStyles {
 		diffFocusDot:  lipgloss.NewStyle().Foreground(t.Primary).Faint(true),
 		diffEmptyHint: lipgloss.NewStyle().Foreground(t.TextSubtle),
 		diffScrollPct: lipgloss.NewStyle().Foreground(t.TextSubtle),
+		warnStyle:     lipgloss.NewStyle().Foreground(t.Warning),
 	}
 }
 
@@ -99,6 +103,10 @@ func newStatusCachedStyles(t ui.Theme) statusCachedStyles {
 type StatusView struct {
 	gitSvc git.Service
 	styles ui.Styles
+	cfg    *config.Config
+	keys   keys.Set           // file list
+	hunkKs keys.Set           // diff pane in hunk mode
+	commKs keys.Set           // commit box
 	sc     statusCachedStyles // pre-computed render styles
 	width  int
 	height int
@@ -109,9 +117,20 @@ type StatusView struct {
 	// Focus pane.
 	focus focusPane
 
-	// Commit mode.
+	// Commit mode. historyPos indexes history.entries while recalling a
+	// message (-1: the message being written, kept in draft). amending is
+	// the commit being amended, nil for a new commit.
 	commitTA   textarea.Model
 	commitMode bool
+	amending   *git.Commit
+	history    *commitHistory
+	historyPos int
+	draft      string
+	commitOpts git.CommitOptions
+	signing    git.Signing
+
+	// Output of the last commit's hooks and signer.
+	output outputPanel
 
 	// Diff preview (inline, always visible in right pane).
 	diffVP      viewport.Model
@@ -119,6 +138,15 @@ type StatusView struct {
 	diffPath    string // path of the file whose diff is shown
 	diffStaged  bool
 
+	// Hunk/line staging. diffFile is nil when the preview can't be parsed
+	// into hunks (untracked, binary, placeholder text) — the pane is then
+	// read-only.
+	diffFile   *git.FileDiff
+	diffRows   []string
+	diffRefs   []diffRowRef
+	diffCursor int // row index into diffRows
+	selAnchor  int // row index where range selection started; -1 = none
+
 	// Cached scroll state from last render — used by mouse click handler
 	// so the hit-test exactly matches what's drawn on screen.
 	lastScrollStart int
@@ -133,7 +161,7 @@ type statusItem struct {
 
 // ── Constructor ─────────────────────────────────────────────────────────────
 
-func NewStatusView(gitSvc git.Service, styles ui.Styles) *StatusView {
+func NewStatusView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *StatusView {
 	ta := textarea.New()
 	ta.Placeholder = "Commit message..."
 	ta.CharLimit = 0
@@ -141,12 +169,19 @@ func NewStatusView(gitSvc git.Service, styles ui.Styles) *StatusView {
 	ta.SetHeight(3)
 
 	return &StatusView{
-		gitSvc:   gitSvc,
-		styles:   styles,
-		sc:       newStatusCachedStyles(styles.Theme),
-		status:   &git.StatusResult{},
-		diffVP:   viewport.New(0, 0),
-		commitTA: ta,
+		gitSvc:    gitSvc,
+		styles:    styles,
+		cfg:       cfg,
+		keys:      keys.New(cfg.Keymap, "status"),
+		hunkKs:    keys.New(cfg.Keymap, "status_diff"),
+		commKs:    keys.New(cfg.Keymap, "commit"),
+		sc:        newStatusCachedStyles(styles.Theme),
+		status:    &git.StatusResult{},
+		diffVP:    viewport.New(0, 0),
+		commitTA:  ta,
+		history:   loadCommitHistory(cfg.CommitHistory),
+		output:    newOutputPanel(),
+		selAnchor: -1,
 	}
 }
 
@@ -158,6 +193,8 @@ func (v *StatusView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
 	v.commitTA.SetWidth(width - 6)
+	v.commitTA.SetHeight(max(3, min(12, height-12)))
+	v.output.SetSize(width, height)
 
 	// Diff pane takes ~60% of width.
 	diffW := v.diffPaneWidth()
@@ -182,8 +219,11 @@ func (v *StatusView) diffPaneWidth() int {
 // ── Messages ────────────────────────────────────────────────────────────────
 
 type (
-	statusResultMsg struct{ status *git.StatusResult }
-	diffPreviewMsg  struct{ diff string }
+	statusResultMsg   struct{ status *git.StatusResult }
+	diffPreviewMsg    struct{ diff string }
+	commitTemplateMsg struct{ template string }
+	amendHeadMsg      struct{ commit git.Commit }
+	signingMsg        struct{ signing git.Signing }
 )
 
 func (v *StatusView) refresh() tea.Cmd {
@@ -209,11 +249,70 @@ func (v *StatusView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 		// Auto-load diff for the selected file.
 		return v, v.autoLoadDiff()
 
+	case commitTemplateMsg:
+		// Only fill a box the user hasn't started typing in.
+		if v.commitMode && v.commitTA.Value() == "" {
+			v.commitTA.SetValue(msg.template)
+			// Start typing the subject on the template's first line.
+			for v.commitTA.Line() > 0 {
+				v.commitTA.CursorUp()
+			}
+			v.commitTA.CursorStart()
+		}
+		return v, nil
+
+	case amendHeadMsg:
+		cmd := v.openCommit(&msg.commit)
+		v.commitTA.SetValue(commitMessage(msg.commit))
+		return v, tea.Batch(v.commitTA.Focus(), cmd)
+
+	case signingMsg:
+		v.signing = msg.signing
+		v.commitOpts.Sign = msg.signing.Enabled
+		return v, nil
+
+	case commitOutputMsg:
+		v.output.Append(msg.chunk)
+		return v, waitCommitOutput(msg.ch)
+
+	case commitDoneMsg:
+		v.output.Append(msg.output)
+		v.output.Finish(msg.err)
+		if msg.err != nil {
+			return v, common.CmdErr(msg.err)
+		}
+		return v, common.CmdRefresh
+
+	case commitEditedMsg:
+		if v.commitMode {
+			v.commitTA.SetValue(msg.message)
+			v.historyPos = -1
+		}
+		return v, nil
+
 	case diffPreviewMsg:
+		sameFile := v.diffFile != nil && v.diffFile.Path() == v.diffPath
 		v.diffContent = msg.diff
-		colored := renderDiffColored(v.styles, msg.diff)
-		v.diffVP.SetContent(colored)
-		v.diffVP.GotoTop()
+		v.diffFile = nil
+		v.diffRows, v.diffRefs = nil, nil
+		v.selAnchor = -1
+		if files := git.ParseDiff(msg.diff); len(files) == 1 && len(files[0].Hunks) > 0 && !files[0].Binary {
+			v.diffFile = &files[0]
+			v.diffRows, v.diffRefs = renderHunkRows(v.styles, v.diffFile)
+		}
+		if v.diffFile == nil {
+			v.diffVP.SetContent(renderDiffColored(v.styles, msg.diff))
+			v.diffVP.GotoTop()
+			return v, nil
+		}
+		// Keep the cursor roughly in place after staging part of the same
+		// file, so the next lines can be staged without re-navigating.
+		if !sameFile {
+			v.diffCursor = 0
+			v.diffVP.GotoTop()
+		}
+		v.clampDiffCursor(1)
+		v.renderDiffContent()
 		return v, nil
 
 	case common.RefreshMsg:
@@ -226,6 +325,9 @@ func (v *StatusView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 		if v.commitMode {
 			return v.updateCommitMode(msg)
 		}
+		if v.output.open {
+			return v.updateOutput(msg)
+		}
 		return v.updateNormal(msg)
 	}
 
@@ -292,7 +394,7 @@ func (v *StatusView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 				return v, v.loadDiffPreview(item)
 			}
 		} else {
-			v.focus = focusDiffPane
+			v.focusDiff()
 		}
 	}
 	return v, nil
@@ -301,37 +403,40 @@ func (v *StatusView) handleMouse(msg tea.MouseMsg) (common.View, tea.Cmd) {
 // ── Keyboard handler ────────────────────────────────────────────────────────
 
 func (v *StatusView) updateNormal(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	// If diff pane is focused, handle scroll keys there.
+	// If diff pane is focused, handle line cursor / scroll keys there.
 	if v.focus == focusDiffPane {
-		switch msg.String() {
+		if v.hunkKs.Action(msg) == "copy_diff" {
+			return v, copyText(v.cfg, "diff of "+v.diffPath, v.diffContent)
+		}
+		if v.diffFile != nil {
+			return v.updateHunkMode(msg)
+		}
+		switch v.keys.Action(msg) {
 		case "down":
 			v.diffVP.ScrollDown(1)
 			return v, nil
 		case "up":
 			v.diffVP.ScrollUp(1)
 			return v, nil
-		case "ctrl+d", "pgdown":
+		case "page_down":
 			v.diffVP.HalfPageDown()
 			return v, nil
-		case "ctrl+u", "pgup":
+		case "page_up":
 			v.diffVP.HalfPageUp()
 			return v, nil
-		case "home":
+		case "top":
 			v.diffVP.GotoTop()
 			return v, nil
-		case "end":
+		case "bottom":
 			v.diffVP.GotoBottom()
 			return v, nil
-		case "tab":
-			v.focus = focusFileList
-			return v, nil
-		case "esc":
-			v.focus = focusFileList
+		case "switch_pane", "back":
+			v.blurDiff()
 			return v, nil
 		}
 	}
 
-	switch msg.String() {
+	switch v.keys.Action(msg) {
 	case "down":
 		if v.cursor < len(v.items)-1 {
 			v.cursor++
@@ -342,70 +447,160 @@ func (v *StatusView) updateNormal(msg tea.KeyMsg) (common.View, tea.Cmd) {
 			v.cursor--
 			return v, v.autoLoadDiff()
 		}
-	case "home":
+	case "top":
 		v.cursor = 0
 		return v, v.autoLoadDiff()
-	case "end":
+	case "bottom":
 		if len(v.items) > 0 {
 			v.cursor = len(v.items) - 1
 			return v, v.autoLoadDiff()
 		}
-	case "ctrl+d", "pgdown":
+	case "page_down":
 		v.cursor = min(v.cursor+v.pageSize(), len(v.items)-1)
 		return v, v.autoLoadDiff()
-	case "ctrl+u", "pgup":
+	case "page_up":
 		v.cursor = max(v.cursor-v.pageSize(), 0)
 		return v, v.autoLoadDiff()
-	case "tab":
+	case "switch_pane":
 		if v.diffPaneWidth() > 0 {
-			v.focus = focusDiffPane
+			v.focusDiff()
 		}
 		return v, nil
-	case "s":
+	case "stage":
 		if item, ok := v.currentItem(); ok {
 			return v, v.stageFile(item)
 		}
-	case "S":
+	case "stage_all":
 		return v, v.stageAllFiles()
-	case "u":
+	case "unstage":
 		if item, ok := v.currentItem(); ok {
 			return v, v.unstageFile(item)
 		}
-	case "U":
+	case "unstage_all":
 		return v, v.unstageAllFiles()
-	case "x":
+	case "discard":
 		if item, ok := v.currentItem(); ok {
-			return v, v.discardFile(item)
-		}
-	case "c":
-		v.commitMode = true
-		v.commitTA.Reset()
-		v.commitTA.Focus()
-		return v, v.commitTA.Focus()
-	case "d", "enter":
-		// Diff is already shown; pressing d/enter could toggle focus.
+			return v, v.confirmDiscard(item)
+		}
+	case "copy_path":
+		if item, ok := v.currentItem(); ok {
+			return v, copyText(v.cfg, item.file.Path, item.file.Path)
+		}
+	case "edit":
+		if item, ok := v.currentItem(); ok &&
+			item.file.Worktree != git.StatusDeleted && item.file.Staging != git.StatusDeleted {
+			return v, openInEditor(v.cfg.EditorCommand(), v.gitSvc.RepoRoot(), item.file.Path)
+		}
+	case "commit":
+		cmd := v.openCommit(nil)
+		return v, tea.Batch(v.commitTA.Focus(), v.loadCommitTemplate(), cmd)
+	case "amend":
+		return v, v.loadAmendHead()
+	case "focus_diff":
+		// Diff is already shown; focus_diff moves focus into it.
 		if v.diffPaneWidth() > 0 {
-			v.focus = focusDiffPane
+			v.focusDiff()
+		}
+		return v, nil
+	}
+	return v, nil
+}
+
+// updateHunkMode handles keys while the diff pane is focused and the
+// preview was parsed into hunks: a line cursor, optional range selection,
+// and partial stage / unstage / discard.
+func (v *StatusView) updateHunkMode(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	action := v.hunkKs.Action(msg)
+	switch action {
+	case "down":
+		v.moveDiffCursor(1)
+	case "up":
+		v.moveDiffCursor(-1)
+	case "page_down":
+		v.moveDiffCursor(max(v.diffVP.Height/2, 1))
+	case "page_up":
+		v.moveDiffCursor(-max(v.diffVP.Height/2, 1))
+	case "top":
+		v.diffCursor = 0
+		v.clampDiffCursor(1)
+	case "bottom":
+		v.diffCursor = len(v.diffRows) - 1
+		v.clampDiffCursor(-1)
+	case "next_hunk":
+		v.jumpHunk(1)
+	case "prev_hunk":
+		v.jumpHunk(-1)
+	case "select":
+		if v.selAnchor >= 0 {
+			v.selAnchor = -1
+		} else {
+			v.selAnchor = v.diffCursor
+		}
+	case "toggle_line":
+		// Stage or unstage the selected lines, depending on which side
+		// of the index the preview shows.
+		return v, v.applyPatch(v.selectedLines(), v.diffStaged, patchStage)
+	case "stage_hunk":
+		if !v.diffStaged {
+			return v, v.applyPatch(v.currentHunk(), false, patchStage)
+		}
+	case "unstage_hunk":
+		if v.diffStaged {
+			return v, v.applyPatch(v.currentHunk(), true, patchStage)
+		}
+	case "discard_lines":
+		if !v.diffStaged {
+			return v, v.applyPatch(v.selectedLines(), true, patchDiscard)
 		}
+	case "discard_hunk":
+		if !v.diffStaged {
+			return v, v.applyPatch(v.currentHunk(), true, patchDiscard)
+		}
+	case "switch_pane", "back":
+		if action == "back" && v.selAnchor >= 0 {
+			v.selAnchor = -1
+			break
+		}
+		v.blurDiff()
+		return v, nil
+	default:
 		return v, nil
 	}
+	v.renderDiffContent()
 	return v, nil
 }
 
 func (v *StatusView) updateCommitMode(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	switch v.commKs.Action(msg) {
+	case "cancel":
 		v.commitMode = false
 		v.commitTA.Blur()
 		return v, nil
-	case "ctrl+s":
-		message := strings.TrimSpace(v.commitTA.Value())
+	case "submit":
+		message := cleanupMessage(v.commitTA.Value())
 		if message == "" {
 			return v, common.CmdErr(fmt.Errorf("commit message cannot be empty"))
 		}
 		v.commitMode = false
 		v.commitTA.Blur()
-		return v, v.doCommit(message)
+		return v, v.runCommit(message)
+	case "editor":
+		return v, editCommitMessage(v.cfg.EditorCommand(), v.gitSvc.GitDir(), v.commitTA.Value())
+	case "signoff":
+		v.commitOpts.SignOff = !v.commitOpts.SignOff
+		return v, nil
+	case "sign":
+		v.commitOpts.Sign = !v.commitOpts.Sign
+		return v, nil
+	case "no_verify":
+		v.commitOpts.NoVerify = !v.commitOpts.NoVerify
+		return v, nil
+	case "history_prev":
+		v.recallHistory(1)
+		return v, nil
+	case "history_next":
+		v.recallHistory(-1)
+		return v, nil
 	}
 	var cmd tea.Cmd
 	v.commitTA, cmd = v.commitTA.Update(msg)
@@ -450,6 +645,28 @@ func (v *StatusView) unstageAllFiles() tea.Cmd {
 	}
 }
 
+// confirmDiscard asks before discarding a file's unstaged changes,
+// showing their diffstat.
+func (v *StatusView) confirmDiscard(item statusItem) tea.Cmd {
+	discard := v.discardFile(item)
+	return confirm(v.cfg, discard, func() (common.ConfirmMsg, error) {
+		stat, err := v.gitSvc.DiffStat(false, item.file.Path)
+		if err != nil {
+			return common.ConfirmMsg{}, err
+		}
+		detail := "Unstaged changes to " + item.file.Path + " will be lost."
+		if lines := splitLines(stat); len(lines) > 0 {
+			detail += "\n\n" + confirmPreview(lines)
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmDiscard,
+			Title:     "Discard changes?",
+			Detail:    detail,
+			OnConfirm: discard,
+		}, nil
+	})
+}
+
 func (v *StatusView) discardFile(item statusItem) tea.Cmd {
 	return func() tea.Msg {
 		if err := v.gitSvc.Discard(item.file.Path); err != nil {
@@ -459,13 +676,174 @@ func (v *StatusView) discardFile(item statusItem) tea.Cmd {
 	}
 }
 
-func (v *StatusView) doCommit(message string) tea.Cmd {
-	return func() tea.Msg {
-		if err := v.gitSvc.Commit(message); err != nil {
+// patchAction is what applyPatch does with the selected lines.
+type patchAction int
+
+const (
+	patchStage   patchAction = iota // stage (forward) or unstage (reverse)
+	patchDiscard                    // drop from the working tree
+)
+
+// applyPatch builds a partial patch for sel and hands it to git. reverse
+// selects the unstage/discard direction; see git.BuildPatch.
+func (v *StatusView) applyPatch(sel git.LineSelector, reverse bool, action patchAction) tea.Cmd {
+	if v.diffFile == nil || sel == nil {
+		return nil
+	}
+	patch, ok := git.BuildPatch(v.diffFile, sel, reverse)
+	if !ok {
+		return common.CmdInfo("No changes selected")
+	}
+	v.selAnchor = -1
+	// Force the preview to reload even if the same file stays selected.
+	v.diffPath = ""
+	run := func() tea.Msg {
+		var err error
+		switch {
+		case action == patchDiscard:
+			err = v.gitSvc.DiscardPatch(patch)
+		case reverse:
+			err = v.gitSvc.UnstagePatch(patch)
+		default:
+			err = v.gitSvc.StagePatch(patch)
+		}
+		if err != nil {
 			return common.ErrMsg{Err: err}
 		}
 		return common.CmdRefresh()
 	}
+	if action != patchDiscard {
+		return run
+	}
+	path := v.diffFile.Path()
+	return confirm(v.cfg, run, func() (common.ConfirmMsg, error) {
+		// Read the patch back rather than scanning its text, so lines
+		// such as "--- x" or "+++" are not taken for file headers.
+		var lost []string
+		for _, f := range git.ParseDiff(patch) {
+			for _, h := range f.Hunks {
+				for _, l := range h.Lines {
+					if l.IsChange() {
+						lost = append(lost, string(l.Kind)+l.Content)
+					}
+				}
+			}
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmDiscard,
+			Title:     "Discard lines?",
+			Detail:    fmt.Sprintf("%d changed line(s) in %s will be lost.\n\n%s", len(lost), path, confirmPreview(lost)),
+			OnConfirm: run,
+		}, nil
+	})
+}
+
+// recallHistory steps through past messages (delta 1 is older), keeping
+// the message being written so stepping back past the newest restores it.
+func (v *StatusView) recallHistory(delta int) {
+	pos := v.historyPos + delta
+	if pos < -1 || pos >= len(v.history.entries) {
+		return
+	}
+	if v.historyPos == -1 {
+		v.draft = v.commitTA.Value()
+	}
+	v.historyPos = pos
+	if pos == -1 {
+		v.commitTA.SetValue(v.draft)
+	} else {
+		v.commitTA.SetValue(v.history.entries[pos])
+	}
+}
+
+// openCommit shows an empty commit box; amending is the commit being
+// amended, nil for a new commit. The returned command loads the signing
+// setup, which decides whether "sign" starts ticked.
+func (v *StatusView) openCommit(amending *git.Commit) tea.Cmd {
+	v.commitMode = true
+	v.amending = amending
+	v.commitTA.Reset()
+	v.historyPos, v.draft = -1, ""
+	v.commitOpts = git.CommitOptions{SignOff: v.cfg.CommitSignOff, Sign: v.signing.Enabled}
+	return func() tea.Msg {
+		signing, err := v.gitSvc.CommitSigning()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return signingMsg{signing: signing}
+	}
+}
+
+// updateOutput scrolls and dismisses the output panel.
+func (v *StatusView) updateOutput(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch v.keys.Action(msg) {
+	case "back":
+		v.output.Close()
+	case "up":
+		v.output.vp.LineUp(1)
+	case "down":
+		v.output.vp.LineDown(1)
+	case "page_up":
+		v.output.vp.HalfViewUp()
+	case "page_down":
+		v.output.vp.HalfViewDown()
+	case "top":
+		v.output.vp.GotoTop()
+	case "bottom":
+		v.output.vp.GotoBottom()
+	}
+	return v, nil
+}
+
+// loadAmendHead fetches HEAD so the amend box starts with its message.
+func (v *StatusView) loadAmendHead() tea.Cmd {
+	return func() tea.Msg {
+		commits, err := v.gitSvc.Log(1, "HEAD")
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		if len(commits) == 0 {
+			return common.ErrMsg{Err: fmt.Errorf("no commit to amend")}
+		}
+		return amendHeadMsg{commit: commits[0]}
+	}
+}
+
+// loadCommitTemplate fetches commit.template for the commit box.
+func (v *StatusView) loadCommitTemplate() tea.Cmd {
+	return func() tea.Msg {
+		tmpl, err := v.gitSvc.CommitTemplate()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		if tmpl == "" {
+			return nil
+		}
+		return commitTemplateMsg{template: tmpl}
+	}
+}
+
+// runCommit commits (or amends) with the box's options. Hook output
+// streams into the output panel; a signed commit runs in the terminal so
+// the signer can prompt for a passphrase.
+func (v *StatusView) runCommit(message string) tea.Cmd {
+	run, title := v.gitSvc.Commit, "Commit"
+	if v.amending != nil {
+		run, title = v.gitSvc.CommitAmend, "Amend "+v.amending.ShortHash
+	}
+	history := v.history
+	commit := func(opts git.CommitOptions) error {
+		if err := history.Add(message); err != nil {
+			return err
+		}
+		return run(message, opts)
+	}
+	v.output.Start(title)
+	if v.commitOpts.Sign {
+		header := fmt.Sprintf("zgv: committing, signed with %s. Enter the passphrase if asked.", v.signing.Format)
+		return execCommit(commit, v.commitOpts, header)
+	}
+	return streamCommit(commit, v.commitOpts)
 }
 
 func (v *StatusView) loadDiffPreview(item statusItem) tea.Cmd {
@@ -485,12 +863,140 @@ func (v *StatusView) loadDiffPreview(item statusItem) tea.Cmd {
 	}
 }
 
+// ── Hunk cursor helpers ─────────────────────────────────────────────────────
+
+func (v *StatusView) focusDiff() {
+	v.focus = focusDiffPane
+	if v.diffFile != nil {
+		v.clampDiffCursor(1)
+		v.renderDiffContent()
+	}
+}
+
+func (v *StatusView) blurDiff() {
+	v.focus = focusFileList
+	v.selAnchor = -1
+	if v.diffFile != nil {
+		v.renderDiffContent()
+	}
+}
+
+// moveDiffCursor moves the line cursor by delta rows, skipping decoration
+// rows in the direction of travel.
+func (v *StatusView) moveDiffCursor(delta int) {
+	v.diffCursor += delta
+	dir := 1
+	if delta < 0 {
+		dir = -1
+	}
+	v.clampDiffCursor(dir)
+}
+
+// clampDiffCursor keeps the cursor in range and on a diff line, searching
+// first in direction dir and then the other way.
+func (v *StatusView) clampDiffCursor(dir int) {
+	n := len(v.diffRefs)
+	if n == 0 {
+		v.diffCursor = 0
+		return
+	}
+	v.diffCursor = min(max(v.diffCursor, 0), n-1)
+	for _, d := range []int{dir, -dir} {
+		for i := v.diffCursor; i >= 0 && i < n; i += d {
+			if v.diffRefs[i].isLine() {
+				v.diffCursor = i
+				return
+			}
+		}
+	}
+}
+
+// jumpHunk moves the cursor to the first line of the next/previous hunk.
+func (v *StatusView) jumpHunk(dir int) {
+	if v.diffCursor >= len(v.diffRefs) {
+		return
+	}
+	cur := v.diffRefs[v.diffCursor].Hunk
+	for i := v.diffCursor; i >= 0 && i < len(v.diffRefs); i += dir {
+		r := v.diffRefs[i]
+		if r.isLine() && r.Hunk != cur {
+			// Land on the hunk's first line, not its last, when going up.
+			for i > 0 && v.diffRefs[i-1].Hunk == r.Hunk && v.diffRefs[i-1].isLine() {
+				i--
+			}
+			v.diffCursor = i
+			return
+		}
+	}
+}
+
+// selectedLines returns the range selection, or just the cursor line.
+func (v *StatusView) selectedLines() git.LineSelector {
+	if len(v.diffRefs) == 0 {
+		return nil
+	}
+	from := v.diffCursor
+	if v.selAnchor >= 0 {
+		from = v.selAnchor
+	}
+	return rowSelector(v.diffRefs, from, v.diffCursor)
+}
+
+// currentHunk returns a selector for the whole hunk under the cursor.
+func (v *StatusView) currentHunk() git.LineSelector {
+	if v.diffCursor >= len(v.diffRefs) || !v.diffRefs[v.diffCursor].isLine() {
+		return nil
+	}
+	return git.SelectHunk(v.diffRefs[v.diffCursor].Hunk)
+}
+
+// renderDiffContent pushes the hunk rows into the viewport, marking the
+// cursor and selection when the diff pane has focus, and scrolls so the
+// cursor stays visible.
+func (v *StatusView) renderDiffContent() {
+	focused := v.focus == focusDiffPane
+	lo, hi := v.diffCursor, v.diffCursor
+	if v.selAnchor >= 0 {
+		lo, hi = min(v.selAnchor, v.diffCursor), max(v.selAnchor, v.diffCursor)
+	}
+
+	var b strings.Builder
+	for i, row := range v.diffRows {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		marker := " "
+		if focused && v.diffRefs[i].isLine() {
+			switch {
+			case i == v.diffCursor:
+				marker = v.sc.cursorStyle.Render("▸")
+			case i >= lo && i <= hi:
+				marker = v.sc.cursorStyle.Render("┃")
+			}
+		}
+		b.WriteString(marker + row)
+	}
+	v.diffVP.SetContent(b.String())
+
+	if !focused {
+		return
+	}
+	if v.diffCursor < v.diffVP.YOffset {
+		v.diffVP.SetYOffset(v.diffCursor)
+	} else if h := v.diffVP.Height; h > 0 && v.diffCursor >= v.diffVP.YOffset+h {
+		v.diffVP.SetYOffset(v.diffCursor - h + 1)
+	}
+}
+
 // ── View ────────────────────────────────────────────────────────────────────
 
 func (v *StatusView) View() string {
 	if v.commitMode {
 		return v.viewCommit()
 	}
+	if v.output.open {
+		return v.output.View(v.styles, v.keys.Hints("up", "scroll", "back", "close"))
+	}
 
 	// Reserve 2 lines at the bottom for the persistent command bar.
 	cmdBar := v.renderCommandBar()
@@ -796,18 +1302,42 @@ func (v *StatusView) renderCommandBar() string {
 	// Show only action shortcuts — navigation (arrows) is self-evident.
 	var entries []string
 
-	if v.focus == focusDiffPane {
+	entry := func(k, desc string) string {
+		return v.sc.keyStyle.Render(k) + v.sc.descStyle.Render(" "+desc)
+	}
+	hk, fk := v.hunkKs, v.keys
+	switch {
+	case v.focus == focusDiffPane && v.diffFile != nil && v.diffStaged:
 		entries = []string{
-			v.sc.keyStyle.Render("tab") + v.sc.descStyle.Render(" files"),
-			v.sc.keyStyle.Render("esc") + v.sc.descStyle.Render(" back"),
+			entry(hk.First("toggle_line"), "unstage line"),
+			entry(hk.First("unstage_hunk"), "unstage hunk"),
+			entry(hk.First("select"), "select"),
+			entry(hk.First("prev_hunk")+"/"+hk.First("next_hunk"), "hunk"),
+			entry(hk.First("switch_pane"), "files"),
 		}
-	} else {
+	case v.focus == focusDiffPane && v.diffFile != nil:
+		entries = []string{
+			entry(hk.First("toggle_line"), "stage line"),
+			entry(hk.First("stage_hunk"), "stage hunk"),
+			entry(hk.First("discard_lines")+"/"+hk.First("discard_hunk"), "discard line/hunk"),
+			entry(hk.First("select"), "select"),
+			entry(hk.First("prev_hunk")+"/"+hk.First("next_hunk"), "hunk"),
+			entry(hk.First("switch_pane"), "files"),
+		}
+	case v.focus == focusDiffPane:
 		entries = []string{
-			v.sc.keyStyle.Render("s") + v.sc.descStyle.Render(" stage"),
-			v.sc.keyStyle.Render("u") + v.sc.descStyle.Render(" unstage"),
-			v.sc.keyStyle.Render("S/U") + v.sc.descStyle.Render(" all"),
-			v.sc.keyStyle.Render("x") + v.sc.descStyle.Render(" discard"),
-			v.sc.keyStyle.Render("c") + v.sc.descStyle.Render(" commit"),
+			entry(fk.First("switch_pane"), "files"),
+			entry(fk.First("back"), "back"),
+		}
+	default:
+		entries = []string{
+			entry(fk.First("stage"), "stage"),
+			entry(fk.First("unstage"), "unstage"),
+			entry(fk.First("stage_all")+"/"+fk.First("unstage_all"), "all"),
+			entry(fk.First("discard"), "discard"),
+			entry(fk.First("edit"), "edit"),
+			entry(fk.First("commit"), "commit"),
+			entry(fk.First("amend"), "amend"),
 		}
 	}
 
@@ -843,17 +1373,52 @@ func (v *StatusView) renderCommandBar() string {
 func (v *StatusView) viewCommit() string {
 	title := v.sc.titlePrimary.Render(" Commit")
 	info := v.styles.Muted.Render(fmt.Sprintf(" %d file(s) staged", len(v.status.Staged)))
+	submit := "commit"
+	if c := v.amending; c != nil {
+		title = v.sc.titlePrimary.Render(" Amend " + c.ShortHash)
+		info = v.styles.Muted.Render(fmt.Sprintf(" %d staged file(s) will be added to %s", len(v.status.Staged), c.ShortHash))
+		submit = "amend"
+	}
 	ta := " " + v.commitTA.View()
 
+	var notes []string
+	for _, w := range lintCommit(v.cfg, v.commitTA.Value()) {
+		notes = append(notes, v.sc.warnStyle.Render(" ⚠ "+w))
+	}
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	sign := "sign"
+	if v.signing.Format != "" {
+		sign += " (" + v.signing.Format + ")"
+	}
+	options := v.styles.Muted.Render(fmt.Sprintf(" %s sign-off %s   %s %s %s   %s skip hooks %s",
+		check(v.commitOpts.SignOff), v.commKs.First("signoff"),
+		check(v.commitOpts.Sign), sign, v.commKs.First("sign"),
+		check(v.commitOpts.NoVerify), v.commKs.First("no_verify")))
+	if v.historyPos >= 0 {
+		notes = append(notes, v.styles.Muted.Render(fmt.Sprintf(" history %d/%d", v.historyPos+1, len(v.history.entries))))
+	}
+
 	// Command bar for commit mode.
-	hint := " " + v.sc.keyStyle.Render("ctrl+s") + v.sc.descStyle.Render(" commit") + "  " +
-		v.sc.keyStyle.Render("esc") + v.sc.descStyle.Render(" cancel")
+	hint := " "
+	for _, e := range []struct{ action, desc string }{
+		{"submit", submit}, {"editor", "$EDITOR"}, {"history_prev", "older"}, {"history_next", "newer"}, {"cancel", "cancel"},
+	} {
+		if e.action != "submit" {
+			hint += "  "
+		}
+		hint += v.sc.keyStyle.Render(v.commKs.First(e.action)) + v.sc.descStyle.Render(" "+e.desc)
+	}
 
 	divider := v.sc.dividerStyle.Width(v.width).
 		Render(strings.Repeat("─", v.width))
 	cmdBar := v.sc.barBgStyle.Width(v.width).Render(hint)
 
-	top := lipgloss.JoinVertical(lipgloss.Left, title, "", info, "", ta)
+	top := lipgloss.JoinVertical(lipgloss.Left, append([]string{title, "", info, "", ta, "", options}, notes...)...)
 	topH := v.height - 2 // reserve for command bar
 	topPadded := lipgloss.NewStyle().Width(v.width).Height(topH).Render(top)
 
@@ -987,14 +1552,24 @@ func (v *StatusView) statusColor(code git.StatusCode, fallback lipgloss.Color) l
 }
 
 func (v *StatusView) ShortHelp() []components.HelpEntry {
+	fk, hk := v.keys, v.hunkKs
 	return []components.HelpEntry{
-		{Key: "↑/↓", Desc: "Navigate files"},
-		{Key: "s / S", Desc: "Stage file / all"},
-		{Key: "u / U", Desc: "Unstage file / all"},
-		{Key: "x", Desc: "Discard changes"},
-		{Key: "c", Desc: "Commit"},
-		{Key: "tab", Desc: "Switch file/diff pane"},
-		{Key: "d/enter", Desc: "Focus diff"},
+		{Key: fk.First("up") + "/" + fk.First("down"), Desc: "Navigate files"},
+		{Key: fk.First("stage") + " / " + fk.First("stage_all"), Desc: "Stage file / all"},
+		{Key: fk.First("unstage") + " / " + fk.First("unstage_all"), Desc: "Unstage file / all"},
+		{Key: fk.Help("discard"), Desc: "Discard changes"},
+		{Key: fk.Help("edit"), Desc: "Open file in editor"},
+		{Key: fk.Help("copy_path"), Desc: "Copy file path"},
+		{Key: fk.Help("commit"), Desc: "Commit"},
+		{Key: fk.Help("amend"), Desc: "Amend last commit"},
+		{Key: fk.Help("switch_pane"), Desc: "Switch file/diff pane"},
+		{Key: fk.Help("focus_diff"), Desc: "Focus diff"},
+		{Key: hk.Help("toggle_line"), Desc: "Diff: stage/unstage line(s)"},
+		{Key: hk.First("stage_hunk") + " / " + hk.First("unstage_hunk"), Desc: "Diff: stage/unstage hunk"},
+		{Key: hk.First("discard_lines") + " / " + hk.First("discard_hunk"), Desc: "Diff: discard line(s) / hunk"},
+		{Key: hk.Help("select"), Desc: "Diff: start/cancel range select"},
+		{Key: hk.First("prev_hunk") + " / " + hk.First("next_hunk"), Desc: "Diff: previous/next hunk"},
+		{Key: hk.Help("copy_diff"), Desc: "Diff: copy diff"},
 	}
 }
 
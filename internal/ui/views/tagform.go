@@ -0,0 +1,120 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tagForm is the "new tag" prompt shared by the Tags and Log views. A tag
+// with a message is annotated; one without is lightweight unless signed.
+type tagForm struct {
+	open    bool
+	ref     string // what gets tagged ("" means HEAD)
+	target  string // ref as shown in the title
+	field   int    // 0: name, 1: message
+	name    textinput.Model
+	message textinput.Model
+	signed  bool
+}
+
+type tagOpDoneMsg struct{ info string }
+
+func newTagForm() tagForm {
+	name := textinput.New()
+	name.Placeholder = "v1.2.3"
+	name.CharLimit = 100
+	name.Width = 40
+	message := textinput.New()
+	message.Placeholder = "message (empty for a lightweight tag)"
+	message.CharLimit = 500
+	message.Width = 60
+	return tagForm{name: name, message: message}
+}
+
+// Open shows the form for tagging ref, titled with target.
+func (f *tagForm) Open(ref, target string) tea.Cmd {
+	f.open = true
+	f.ref, f.target = ref, target
+	f.field = 0
+	f.signed = false
+	f.name.Reset()
+	f.message.Reset()
+	f.message.Blur()
+	return f.name.Focus()
+}
+
+func (f *tagForm) close() {
+	f.open = false
+	f.name.Blur()
+	f.message.Blur()
+}
+
+// Update handles a key while the form is open. On enter it closes the form
+// and returns the command that creates the tag.
+func (f *tagForm) Update(gitSvc git.Service, msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		f.close()
+		return nil
+	case "tab", "shift+tab":
+		f.field = 1 - f.field
+		if f.field == 0 {
+			f.message.Blur()
+			return f.name.Focus()
+		}
+		f.name.Blur()
+		return f.message.Focus()
+	case "ctrl+g":
+		f.signed = !f.signed
+		return nil
+	case "enter":
+		name := strings.TrimSpace(f.name.Value())
+		if name == "" {
+			return nil
+		}
+		message := strings.TrimSpace(f.message.Value())
+		ref, target, signed := f.ref, f.target, f.signed
+		f.close()
+		return func() tea.Msg {
+			if err := gitSvc.CreateTag(name, ref, message, message != "", signed); err != nil {
+				return common.ErrMsg{Err: err}
+			}
+			kind := "tag"
+			switch {
+			case signed:
+				kind = "signed tag"
+			case message != "":
+				kind = "annotated tag"
+			}
+			return tagOpDoneMsg{info: fmt.Sprintf("Created %s %s at %s", kind, name, target)}
+		}
+	}
+	var cmd tea.Cmd
+	if f.field == 0 {
+		f.name, cmd = f.name.Update(msg)
+	} else {
+		f.message, cmd = f.message.Update(msg)
+	}
+	return cmd
+}
+
+func (f *tagForm) View(styles ui.Styles) string {
+	t := styles.Theme
+	title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render("  New Tag at " + f.target)
+	sign := "[ ] sign (ctrl+g)"
+	if f.signed {
+		sign = "[x] sign (ctrl+g)"
+	}
+	hint := styles.Muted.Render("  tab switch field | enter to create | esc to cancel")
+	return lipgloss.JoinVertical(lipgloss.Left, title, "",
+		styles.Muted.Render("  Name"), "  "+f.name.View(), "",
+		styles.Muted.Render("  Message"), "  "+f.message.View(), "",
+		"  "+sign, "", hint)
+}
@@ -0,0 +1,324 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TagView lists tags, newest first, and creates, deletes and pushes them.
+type TagView struct {
+	gitSvc git.Service
+	styles ui.Styles
+	cfg    *config.Config
+	keys   keys.Set
+	width  int
+	height int
+	tags   []git.Tag
+	remote string // where tags are pushed: the upstream's remote, else origin
+	cursor int
+	offset int
+	form   tagForm
+}
+
+type tagListMsg struct {
+	tags   []git.Tag
+	remote string
+}
+
+// NewTagView creates a new TagView.
+func NewTagView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *TagView {
+	return &TagView{
+		gitSvc: gitSvc,
+		styles: styles,
+		cfg:    cfg,
+		keys:   keys.New(cfg.Keymap, "tags"),
+		form:   newTagForm(),
+	}
+}
+
+func (v *TagView) Init() tea.Cmd { return v.refresh() }
+
+func (v *TagView) SetSize(w, h int) {
+	v.width = w
+	v.height = h
+	v.moveCursor(0)
+}
+
+func (v *TagView) refresh() tea.Cmd {
+	return func() tea.Msg {
+		tags, err := v.gitSvc.Tags()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		remotes, err := v.gitSvc.Remotes()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		branches, err := v.gitSvc.Branches()
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return tagListMsg{tags: tags, remote: defaultRemote(remotes, branches)}
+	}
+}
+
+// defaultRemote picks the current branch's upstream remote, then origin,
+// then the first remote.
+func defaultRemote(remotes []git.Remote, branches []git.Branch) string {
+	if len(remotes) == 0 {
+		return ""
+	}
+	for _, b := range branches {
+		if !b.IsCurrent || b.Upstream == "" {
+			continue
+		}
+		for _, r := range remotes {
+			if strings.HasPrefix(b.Upstream, r.Name+"/") {
+				return r.Name
+			}
+		}
+	}
+	for _, r := range remotes {
+		if r.Name == "origin" {
+			return r.Name
+		}
+	}
+	return remotes[0].Name
+}
+
+func (v *TagView) Update(msg tea.Msg) (common.View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tagListMsg:
+		v.tags, v.remote = msg.tags, msg.remote
+		v.moveCursor(0)
+		return v, nil
+
+	case tagOpDoneMsg:
+		return v, tea.Batch(common.CmdInfo(msg.info), common.CmdRefresh)
+
+	case common.RefreshMsg:
+		return v, v.refresh()
+
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			v.moveCursor(-1)
+		case tea.MouseButtonWheelDown:
+			v.moveCursor(1)
+		case tea.MouseButtonLeft:
+			if msg.Action == tea.MouseActionPress && !v.form.open {
+				// Content starts at Y=2, header is 2 lines.
+				idx := v.offset + msg.Y - 2 - 2
+				if idx >= v.offset && idx < len(v.tags) {
+					v.cursor = idx
+				}
+			}
+		}
+		return v, nil
+
+	case tea.KeyMsg:
+		if v.form.open {
+			return v, v.form.Update(v.gitSvc, msg)
+		}
+		return v.handleKey(msg)
+	}
+	return v, nil
+}
+
+func (v *TagView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
+	switch v.keys.Action(msg) {
+	case "down":
+		v.moveCursor(1)
+	case "up":
+		v.moveCursor(-1)
+	case "page_down":
+		v.moveCursor(v.listRows())
+	case "page_up":
+		v.moveCursor(-v.listRows())
+	case "top":
+		v.moveCursor(-len(v.tags))
+	case "bottom":
+		v.moveCursor(len(v.tags))
+	case "new":
+		return v, v.form.Open("", "HEAD")
+	case "delete":
+		if t, ok := v.currentTag(); ok {
+			return v, v.confirmDelete(t)
+		}
+	case "push":
+		if t, ok := v.currentTag(); ok && v.remote != "" {
+			return v, v.confirmPushTag(t)
+		}
+	case "delete_remote":
+		if t, ok := v.currentTag(); ok && v.remote != "" {
+			return v, v.confirmDeleteRemote(t)
+		}
+	}
+	return v, nil
+}
+
+// confirmDelete asks before deleting a local tag. The tagged commit stays;
+// only the name (and an annotated tag's message) goes.
+func (v *TagView) confirmDelete(t git.Tag) tea.Cmd {
+	del := v.deleteTag(t)
+	return confirm(v.cfg, del, func() (common.ConfirmMsg, error) {
+		detail := "Lightweight tag on " + t.ShortHash + "."
+		if t.Annotated {
+			detail = "Annotated tag on " + t.ShortHash + " by " + t.Tagger + ":\n\n" + confirmPreview([]string{t.Message})
+		}
+		return common.ConfirmMsg{
+			Kind:      confirmDeleteTag,
+			Title:     "Delete tag " + t.Name + "?",
+			Detail:    detail,
+			OnConfirm: del,
+		}, nil
+	})
+}
+
+func (v *TagView) deleteTag(t git.Tag) tea.Cmd {
+	return func() tea.Msg {
+		if err := v.gitSvc.DeleteTag(t.Name); err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return tagOpDoneMsg{info: "Deleted tag " + t.Name}
+	}
+}
+
+// confirmPushTag asks before publishing a tag.
+func (v *TagView) confirmPushTag(t git.Tag) tea.Cmd {
+	remote := v.remote
+	push := runOperation("push tag "+t.Name, func(ctx context.Context, progress git.ProgressFunc) error {
+		return v.gitSvc.PushTag(ctx, remote, t.Name, progress)
+	}, func(err error) tea.Msg {
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return tagOpDoneMsg{info: fmt.Sprintf("Pushed tag %s to %s", t.Name, remote)}
+	})
+	return confirm(v.cfg, push, func() (common.ConfirmMsg, error) {
+		return common.ConfirmMsg{
+			Kind:      confirmPush,
+			Title:     fmt.Sprintf("Push tag %s to %s?", t.Name, remote),
+			Detail:    confirmPreview([]string{t.ShortHash + " " + t.Message}),
+			OnConfirm: push,
+		}, nil
+	})
+}
+
+// confirmDeleteRemote asks before deleting a tag from the remote, which
+// other clones may already have fetched.
+func (v *TagView) confirmDeleteRemote(t git.Tag) tea.Cmd {
+	remote := v.remote
+	del := runOperation("delete tag "+t.Name+" from "+remote, func(ctx context.Context, progress git.ProgressFunc) error {
+		return v.gitSvc.DeleteRemoteTag(ctx, remote, t.Name, progress)
+	}, func(err error) tea.Msg {
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return tagOpDoneMsg{info: fmt.Sprintf("Deleted tag %s from %s", t.Name, remote)}
+	})
+	return confirm(v.cfg, del, func() (common.ConfirmMsg, error) {
+		return common.ConfirmMsg{
+			Kind:  confirmDeleteRemote,
+			Title: fmt.Sprintf("Delete tag %s from %s?", t.Name, remote),
+			Detail: "The local tag is kept. Clones that already fetched " + t.Name +
+				" keep it until they prune tags.",
+			OnConfirm: del,
+		}, nil
+	})
+}
+
+// moveCursor moves the cursor by delta and keeps it inside the list and
+// the visible window.
+func (v *TagView) moveCursor(delta int) {
+	v.cursor = max(0, min(v.cursor+delta, len(v.tags)-1))
+	rows := v.listRows()
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+rows {
+		v.offset = v.cursor - rows + 1
+	}
+}
+
+// listRows is how many tags fit between the header and the key hints.
+func (v *TagView) listRows() int { return max(v.height-4, 1) }
+
+func (v *TagView) View() string {
+	if v.form.open {
+		return v.form.View(v.styles)
+	}
+
+	t := v.styles.Theme
+	var b strings.Builder
+	header := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).
+		Render(fmt.Sprintf("  Tags (%d)", len(v.tags)))
+	if v.remote != "" {
+		header += v.styles.Muted.Render("  remote: " + v.remote)
+	}
+	b.WriteString(header + "\n\n")
+
+	if len(v.tags) == 0 {
+		b.WriteString(v.styles.Muted.Render("  No tags") + "\n")
+	}
+	nameWidth := 0
+	for _, tag := range v.tags {
+		nameWidth = max(nameWidth, min(len(tag.Name), 30))
+	}
+	end := min(v.offset+v.listRows(), len(v.tags))
+	for i := v.offset; i < end; i++ {
+		line := v.renderTag(v.tags[i], nameWidth)
+		if i == v.cursor {
+			b.WriteString(v.styles.ListSelected.Render("▸ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	b.WriteString("\n" + v.styles.Muted.Render("  "+v.keys.Hints(
+		"new", "new", "delete", "delete", "push", "push", "delete_remote", "delete remote")))
+	return b.String()
+}
+
+func (v *TagView) renderTag(tag git.Tag, nameWidth int) string {
+	name := v.styles.TagName.Render(fmt.Sprintf("%-*s", nameWidth, ui.Truncate(tag.Name, 30)))
+	hash := v.styles.CommitHash.Render(tag.ShortHash)
+	kind := v.styles.Muted.Render("light")
+	if tag.Annotated {
+		kind = v.styles.Muted.Render("annot")
+	}
+	msg := v.styles.Body.Render(ui.Truncate(tag.Message, 50))
+	line := name + " " + hash + " " + kind + " " + msg
+	if tag.Tagger != "" {
+		line += " " + v.styles.Author.Render(tag.Tagger)
+	}
+	return line + " " + v.styles.Date.Render(tag.RelDate)
+}
+
+func (v *TagView) currentTag() (git.Tag, bool) {
+	if v.cursor < 0 || v.cursor >= len(v.tags) {
+		return git.Tag{}, false
+	}
+	return v.tags[v.cursor], true
+}
+
+func (v *TagView) ShortHelp() []components.HelpEntry {
+	return []components.HelpEntry{
+		{Key: v.keys.Help("new"), Desc: "New tag at HEAD"},
+		{Key: v.keys.Help("delete"), Desc: "Delete tag"},
+		{Key: v.keys.Help("push"), Desc: "Push tag to remote"},
+		{Key: v.keys.Help("delete_remote"), Desc: "Delete tag from remote"},
+	}
+}
+
+func (v *TagView) InputCapture() bool { return v.form.open }
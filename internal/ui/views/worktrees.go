@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/Akashdeep-Patra/zed-git-view/internal/common"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/config"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/git"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui"
 	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/components"
+	"github.com/Akashdeep-Patra/zed-git-view/internal/ui/keys"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -17,6 +19,7 @@ import (
 type WorktreeView struct {
 	gitSvc    git.Service
 	styles    ui.Styles
+	keys      keys.Set
 	width     int
 	height    int
 	worktrees []git.Worktree
@@ -30,7 +33,7 @@ type WorktreeView struct {
 type worktreeListMsg struct{ wts []git.Worktree }
 
 // NewWorktreeView creates a new WorktreeView.
-func NewWorktreeView(gitSvc git.Service, styles ui.Styles) *WorktreeView {
+func NewWorktreeView(gitSvc git.Service, styles ui.Styles, cfg *config.Config) *WorktreeView {
 	pi := textinput.New()
 	pi.Placeholder = "/path/to/worktree"
 	pi.CharLimit = 200
@@ -41,7 +44,13 @@ func NewWorktreeView(gitSvc git.Service, styles ui.Styles) *WorktreeView {
 	bi.CharLimit = 100
 	bi.Width = 50
 
-	return &WorktreeView{gitSvc: gitSvc, styles: styles, pathInput: pi, brInput: bi}
+	return &WorktreeView{
+		gitSvc:    gitSvc,
+		styles:    styles,
+		keys:      keys.New(cfg.Keymap, "worktrees"),
+		pathInput: pi,
+		brInput:   bi,
+	}
 }
 
 func (v *WorktreeView) Init() tea.Cmd { return v.refresh() }
@@ -78,23 +87,23 @@ func (v *WorktreeView) Update(msg tea.Msg) (common.View, tea.Cmd) {
 }
 
 func (v *WorktreeView) handleKey(msg tea.KeyMsg) (common.View, tea.Cmd) {
-	switch msg.String() {
-	case "j", "down":
+	switch v.keys.Action(msg) {
+	case "down":
 		if v.cursor < len(v.worktrees)-1 {
 			v.cursor++
 		}
-	case "k", "up":
+	case "up":
 		if v.cursor > 0 {
 			v.cursor--
 		}
-	case "n": // Add worktree
+	case "add":
 		v.adding = true
 		v.inputStep = 0
 		v.pathInput.Reset()
 		v.brInput.Reset()
 		v.pathInput.Focus()
 		return v, v.pathInput.Focus()
-	case "D": // Remove
+	case "remove":
 		if v.cursor > 0 && v.cursor < len(v.worktrees) {
 			return v, v.removeWorktree(v.worktrees[v.cursor].Path)
 		}
@@ -197,14 +206,14 @@ func (v *WorktreeView) View() string {
 		}
 	}
 
-	b.WriteString("\n" + v.styles.Muted.Render("  n add worktree  D remove"))
+	b.WriteString("\n" + v.styles.Muted.Render("  "+v.keys.Hints("add", "add worktree", "remove", "remove")))
 	return b.String()
 }
 
 func (v *WorktreeView) ShortHelp() []components.HelpEntry {
 	return []components.HelpEntry{
-		{Key: "n", Desc: "Add worktree"},
-		{Key: "D", Desc: "Remove worktree"},
+		{Key: v.keys.Help("add"), Desc: "Add worktree"},
+		{Key: v.keys.Help("remove"), Desc: "Remove worktree"},
 	}
 }
 
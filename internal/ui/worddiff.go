@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Range is the byte range [Start, End) of a string.
+type Range struct{ Start, End int }
+
+// maxWordDiffTokens bounds the token diff, which is quadratic; longer
+// lines get no emphasis.
+const maxWordDiffTokens = 400
+
+// minWordDiffShared is how much of a pair of lines must be unchanged for
+// their words to be emphasised. Below it the line was rewritten rather
+// than edited, and emphasis would cover nearly all of both.
+const minWordDiffShared = 0.4
+
+// DiffEmphasis finds the changed words of the lines of a unified diff
+// (see LineEmphasis). Only lines inside hunks are paired, so the ---/+++
+// file headers are never mistaken for a change.
+func DiffEmphasis(lines []string) [][]Range {
+	kinds := make([]byte, len(lines))
+	contents := make([]string, len(lines))
+	inHunk := false
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case strings.HasPrefix(line, "diff --git "), strings.HasPrefix(line, "==="):
+			inHunk = false
+		case inHunk && line != "":
+			kinds[i], contents[i] = line[0], line[1:]
+		}
+	}
+	return LineEmphasis(kinds, contents)
+}
+
+// LineEmphasis pairs removed and added lines and finds the words that
+// changed between each pair, like `git diff --word-diff`. kinds holds
+// each line's unified-diff prefix ('-', '+', ' ', '\\', or 0 outside a
+// hunk) and contents the line without it. The n-th line of a run of
+// removed lines is paired with the n-th of the added lines right after
+// it. The result holds the ranges of each line's content to emphasise;
+// lines without a pair, or too unlike theirs, get none.
+func LineEmphasis(kinds []byte, contents []string) [][]Range {
+	out := make([][]Range, len(kinds))
+	for i := 0; i < len(kinds); {
+		if kinds[i] != '-' {
+			i++
+			continue
+		}
+		// "\ No newline at end of file" may follow the last line of
+		// either run.
+		var removed, added []int
+		for ; i < len(kinds) && (kinds[i] == '-' || kinds[i] == '\\'); i++ {
+			if kinds[i] == '-' {
+				removed = append(removed, i)
+			}
+		}
+		for ; i < len(kinds) && (kinds[i] == '+' || kinds[i] == '\\'); i++ {
+			if kinds[i] == '+' {
+				added = append(added, i)
+			}
+		}
+		for n := 0; n < min(len(removed), len(added)); n++ {
+			r, a := removed[n], added[n]
+			out[r], out[a] = WordDiff(contents[r], contents[a])
+		}
+	}
+	return out
+}
+
+// token is a word, a run of spaces or a single other character, and
+// where it starts in its line.
+type token struct {
+	text  string
+	start int
+}
+
+func tokenize(s string) []token {
+	var toks []token
+	class := func(r rune) int {
+		switch {
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		}
+		return 0
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		end := i + size
+		if c := class(r); c != 0 {
+			for end < len(s) {
+				next, n := utf8.DecodeRuneInString(s[end:])
+				if class(next) != c {
+					break
+				}
+				end += n
+			}
+		}
+		toks = append(toks, token{s[i:end], i})
+		i = end
+	}
+	return toks
+}
+
+// WordDiff returns the ranges of a and of b that differ between the two,
+// by a longest common subsequence of their tokens. It returns nothing
+// when the lines share too little for the emphasis to help.
+func WordDiff(a, b string) (ra, rb []Range) {
+	ta, tb := tokenize(a), tokenize(b)
+	if len(ta) > maxWordDiffTokens || len(tb) > maxWordDiffTokens {
+		return nil, nil
+	}
+	n, m := len(ta), len(tb)
+	// lcs[i*(m+1)+j] is the LCS length of ta[i:] and tb[j:].
+	lcs := make([]int, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if ta[i].text == tb[j].text {
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			} else {
+				lcs[i*(m+1)+j] = max(lcs[(i+1)*(m+1)+j], lcs[i*(m+1)+j+1])
+			}
+		}
+	}
+	keepA, keepB := make([]bool, n), make([]bool, m)
+	shared := 0
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case ta[i].text == tb[j].text:
+			keepA[i], keepB[j] = true, true
+			if strings.TrimSpace(ta[i].text) != "" {
+				shared += len(ta[i].text)
+			}
+			i++
+			j++
+		case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	longest := max(len(strings.TrimSpace(a)), len(strings.TrimSpace(b)))
+	if longest == 0 || float64(shared) < minWordDiffShared*float64(longest) {
+		return nil, nil
+	}
+	return changedRanges(ta, keepA), changedRanges(tb, keepB)
+}
+
+// changedRanges merges the tokens not kept into ranges. A space between
+// two changed tokens counts as changed, so "a b" → "c d" is one range.
+func changedRanges(toks []token, keep []bool) []Range {
+	var out []Range
+	for i, t := range toks {
+		changed := !keep[i]
+		if !changed && strings.TrimSpace(t.text) == "" && i > 0 && i < len(toks)-1 {
+			changed = !keep[i-1] && !keep[i+1]
+		}
+		if !changed {
+			continue
+		}
+		end := t.start + len(t.text)
+		if len(out) > 0 && out[len(out)-1].End == t.start {
+			out[len(out)-1].End = end
+		} else {
+			out = append(out, Range{t.start, end})
+		}
+	}
+	return out
+}
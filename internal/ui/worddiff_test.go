@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   string
+		ra, rb []Range
+	}{
+		{"one word", "foo := bar(x)", "foo := baz(x)", []Range{{7, 10}}, []Range{{7, 10}}},
+		{"identical", "return nil", "return nil", nil, nil},
+		{"insertion", "f(a)", "f(a, b)", nil, []Range{{3, 6}}},
+		{"space between changes", "value = a b;", "value = c d;", []Range{{8, 11}}, []Range{{8, 11}}},
+		{"multi-byte runes", "héllo wörld", "héllo welt", []Range{{7, 13}}, []Range{{7, 11}}},
+		{"rewritten", "alpha beta", "gamma delta", nil, nil},
+		{"blank", "  ", "", nil, nil},
+		{"too long", strings.Repeat("a ", 300), strings.Repeat("a ", 299) + "b", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ra, rb := WordDiff(tt.a, tt.b)
+			if !reflect.DeepEqual(ra, tt.ra) || !reflect.DeepEqual(rb, tt.rb) {
+				t.Errorf("WordDiff(%q, %q) = %v, %v, want %v, %v", tt.a, tt.b, ra, rb, tt.ra, tt.rb)
+			}
+		})
+	}
+}
+
+func TestLineEmphasis(t *testing.T) {
+	changed := []Range{{4, 5}}
+	tests := []struct {
+		name     string
+		kinds    string
+		contents []string
+		want     [][]Range
+	}{
+		{"pair", " -+ ", []string{"a", "foo(1)", "foo(2)", "b"},
+			[][]Range{nil, changed, changed, nil}},
+		{"pairs in order", "--++", []string{"foo(1)", "bar(1)", "foo(2)", "bar(2)"},
+			[][]Range{changed, changed, changed, changed}},
+		{"extra removal", "--+", []string{"foo(1)", "bar(1)", "foo(2)"},
+			[][]Range{changed, nil, changed}},
+		{"no newline markers", "-\\+\\", []string{"foo(1)", " No newline at end of file", "foo(2)", " No newline at end of file"},
+			[][]Range{changed, nil, changed, nil}},
+		{"addition only", " +", []string{"foo(1)", "foo(2)"}, [][]Range{nil, nil}},
+		{"context between", "- +", []string{"foo(1)", "x", "foo(2)"}, [][]Range{nil, nil, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LineEmphasis([]byte(tt.kinds), tt.contents); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LineEmphasis = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffEmphasis(t *testing.T) {
+	lines := []string{
+		"diff --git a/f b/f",
+		"--- a/f",
+		"+++ b/f",
+		"@@ -1 +1 @@",
+		"-foo(1)",
+		"+foo(2)",
+		"diff --git a/g b/g",
+		"--- a/g",
+		"+++ b/g",
+	}
+	want := make([][]Range, len(lines))
+	want[4], want[5] = []Range{{4, 5}}, []Range{{4, 5}}
+	if got := DiffEmphasis(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffEmphasis = %v, want %v", got, want)
+	}
+}